@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"strconv"
@@ -12,12 +13,12 @@ import (
 	"github.com/piatoss3612/my-study-bot/internal/logger/service"
 	"github.com/piatoss3612/my-study-bot/internal/pubsub"
 	"github.com/piatoss3612/my-study-bot/internal/pubsub/rabbitmq"
-	"github.com/piatoss3612/my-study-bot/internal/study"
+	redisps "github.com/piatoss3612/my-study-bot/internal/pubsub/redis"
 	"github.com/piatoss3612/my-study-bot/internal/study/event"
+	"github.com/piatoss3612/my-study-bot/internal/study/repository"
+	"github.com/piatoss3612/my-study-bot/internal/study/repository/mongo"
 	"github.com/piatoss3612/my-study-bot/internal/utils"
 	"go.uber.org/zap"
-	"golang.org/x/oauth2/google"
-	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
 
@@ -48,41 +49,32 @@ func run() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	sub, close := mustInitSubscriber(ctx, cfg.RabbitMQ.Addr, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.Kind, cfg.RabbitMQ.Queue)
+	sub, close := mustInitSubscriber(ctx, cfg)
 	defer func() {
 		_ = close()
-		sugar.Info("RabbitMQ connection is closed!")
+		sugar.Infof("%s connection is closed!", cfg.PubSub.Driver)
 	}()
 
-	sheetsSvc := mustInitSheetsService(ctx)
-
-	sugar.Info("Sheets service is ready!")
-
-	sh := mustInitStudyEventHandler(ctx, sheetsSvc)
-
-	mapper := pubsub.NewMapper()
+	sinks, closeSinkFiles := mustInitSinks(ctx, cfg)
+	defer func() {
+		_ = closeSinkFiles()
+	}()
 
-	type mapping struct {
-		topic string
-		h     pubsub.Handler
-	}
+	sugar.Infof("%d export sinks are ready!", len(sinks))
 
-	mappings := []mapping{
-		{topic: study.EventTopicStudyRoundCreated.String(), h: sh},
-		{topic: study.EventTopicStudyRoundFinished.String(), h: sh},
-		{topic: study.EventTopicStudyRoundProgress.String(), h: sh},
+	consumers := []pubsub.Consumer{
+		event.New(sinks...),
 	}
 
-	topics := make([]string, 0, len(mappings))
+	mapper := pubsub.NewMapper()
 
-	for _, m := range mappings {
-		mapper.Register(m.topic, m.h)
-		topics = append(topics, m.topic)
+	for _, c := range consumers {
+		c.Register(mapper)
 	}
 
 	sugar.Info("Event handlers are ready!")
 
-	svc := service.New(sub, mapper, sugar)
+	svc := service.New(sub, mapper, sugar, service.WithCollectors(event.ProgressBufferDepth, event.ProgressRowsDropped, event.WebhookDeliveries))
 	defer func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -91,11 +83,21 @@ func run() {
 		sugar.Info("Logger service is closed!")
 	}()
 
+	// flush buffered records after the subscription is stopped
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := event.CloseSinks(ctx, sinks...); err != nil {
+			sugar.Errorw("Failed to close export sinks", "error", err)
+		}
+	}()
+
 	stop := svc.Run()
 
 	sugar.Info("Logger service is running!")
 
-	svc.Listen(stop, topics)
+	svc.Listen(stop)
 }
 
 func mustLoadConfig(path string) *config.LoggerConfig {
@@ -107,7 +109,17 @@ func mustLoadConfig(path string) *config.LoggerConfig {
 	return cfg
 }
 
-func mustInitSubscriber(ctx context.Context, addr, exchange, kind, queue string) (pubsub.Subscriber, func() error) {
+func mustInitSubscriber(ctx context.Context, cfg *config.LoggerConfig) (pubsub.Subscriber, func() error) {
+	switch cfg.PubSub.Driver {
+	case config.PubSubDriverRedis:
+		return mustInitRedisSubscriber(ctx, cfg.RedisStreams.Addr, cfg.RedisStreams.Stream, cfg.RedisStreams.Group, cfg.RedisStreams.Consumer)
+	default:
+		cfg.PubSub.Driver = config.PubSubDriverRabbitMQ
+		return mustInitRabbitMQSubscriber(ctx, cfg.RabbitMQ.Addr, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.Kind, cfg.RabbitMQ.Queue)
+	}
+}
+
+func mustInitRabbitMQSubscriber(ctx context.Context, addr, exchange, kind, queue string) (pubsub.Subscriber, func() error) {
 	rabbit := <-utils.RedialRabbitMQ(ctx, addr)
 
 	if rabbit == nil {
@@ -123,20 +135,26 @@ func mustInitSubscriber(ctx context.Context, addr, exchange, kind, queue string)
 	return sub, func() error { return rabbit.Close() }
 }
 
-func mustInitSheetsService(ctx context.Context) *sheets.Service {
-	b, err := os.ReadFile(os.Getenv("SHEETS_CREDENTIALS"))
+func mustInitRedisSubscriber(ctx context.Context, addr, stream, group, consumer string) (pubsub.Subscriber, func() error) {
+	client, err := utils.ConnectRedis(ctx, addr)
 	if err != nil {
-		sugar.Fatalf("Unable to read client secret file: %v", err)
+		sugar.Fatal(err)
+	}
+
+	if consumer == "" {
+		consumer, _ = os.Hostname()
 	}
 
-	config, err := google.JWTConfigFromJSON(b, "https://www.googleapis.com/auth/spreadsheets")
+	sub, err := redisps.NewSubscriber(client, stream, group, consumer)
 	if err != nil {
-		sugar.Fatalf("Unable to parse client secret file to config: %v", err)
+		sugar.Fatal(err)
 	}
 
-	client := config.Client(ctx)
+	return sub, func() error { return client.Close() }
+}
 
-	srv, err := sheets.NewService(ctx, option.WithHTTPClient(client))
+func mustInitSheetsService(ctx context.Context) *sheets.Service {
+	srv, err := utils.ConnectSheets(ctx, os.Getenv("SHEETS_CREDENTIALS"))
 	if err != nil {
 		sugar.Fatalf("Unable to retrieve Sheets client: %v", err)
 	}
@@ -144,21 +162,127 @@ func mustInitSheetsService(ctx context.Context) *sheets.Service {
 	return srv
 }
 
-func mustInitStudyEventHandler(ctx context.Context, s *sheets.Service) pubsub.Handler {
+func mustInitSinks(ctx context.Context, cfg *config.LoggerConfig) ([]event.Sink, func() error) {
+	var sinks []event.Sink
+
+	closeFiles := func() error { return nil }
+
+	// google sheets sink is enabled only if credentials are given
+	if os.Getenv("SHEETS_CREDENTIALS") != "" {
+		sinks = append(sinks, mustInitSheetsSink(mustInitSheetsService(ctx)))
+	}
+
+	if cfg.Sinks.File.Dir != "" {
+		sink, err := event.NewFileSink(cfg.Sinks.File.Dir, event.FileFormat(cfg.Sinks.File.Format))
+		if err != nil {
+			sugar.Fatal(err)
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.Sinks.Markdown.Dir != "" {
+		sink, err := event.NewMarkdownSink(cfg.Sinks.Markdown.Dir)
+		if err != nil {
+			sugar.Fatal(err)
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.Sinks.Webhook.Enabled {
+		// events carry only ids of webhooks, secrets are read from the study
+		tx, txClose := mustInitTx(ctx, cfg.MongoDB.URI, cfg.MongoDB.DBName)
+		closeFiles = txClose
+
+		sugar.Info("Connected to MongoDB!")
+
+		opts := []event.WebhookOptsFunc{
+			event.WithWebhookResolver(event.NewStudyWebhookResolver(tx)),
+			event.WithDeliveryErrorHandler(func(err error) {
+				sugar.Errorw("Failed to deliver webhook", "error", err)
+			}),
+		}
+
+		if cfg.Sinks.Webhook.MaxRetries > 0 {
+			opts = append(opts, event.WithWebhookRetries(cfg.Sinks.Webhook.MaxRetries))
+		}
+
+		if path := cfg.Sinks.Webhook.DeliveryLog; path != "" {
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				sugar.Fatal(err)
+			}
+
+			opts = append(opts, event.WithDeliveryLog(f))
+			closeFiles = func() error { return errors.Join(f.Close(), txClose()) }
+		}
+
+		sinks = append(sinks, event.NewWebhookSink(opts...))
+
+		sugar.Info("Webhook sink is ready!")
+	}
+
+	if len(sinks) == 0 {
+		sugar.Fatal("No export sink is configured")
+	}
+
+	return sinks, closeFiles
+}
+
+func mustInitSheetsSink(s *sheets.Service) event.Sink {
 	progressSheetID, _ := strconv.ParseInt(os.Getenv("PROGRESS_SHEET_ID"), 10, 64)
 
-	var opts []event.HandlerOptsFunc
+	var opts []event.SheetsOptsFunc
 
 	if progressSheetID != 0 {
 		opts = append(opts, event.WithProgressSheetID(progressSheetID))
 	}
 
-	h, err := event.New(ctx, s, os.Getenv("SPREADSHEET_ID"), opts...)
+	// used for guilds which have not set their own spreadsheet
+	if spreadsheetID := os.Getenv("SPREADSHEET_ID"); spreadsheetID != "" {
+		opts = append(opts, event.WithDefaultSpreadsheetID(spreadsheetID))
+	}
+
+	// progress rows are written in batch to stay within the quota of sheets api
+	batchSize, err := strconv.Atoi(os.Getenv("PROGRESS_BATCH_SIZE"))
+	if err != nil || batchSize < 1 {
+		batchSize = 20
+	}
+
+	flushInterval, err := time.ParseDuration(os.Getenv("PROGRESS_FLUSH_INTERVAL"))
+	if err != nil || flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+
+	// rows failed to flush are kept up to the limit while the api is unavailable
+	maxBuffered, err := strconv.Atoi(os.Getenv("PROGRESS_MAX_BUFFERED"))
+	if err != nil || maxBuffered < batchSize {
+		maxBuffered = 1000
+	}
+
+	opts = append(opts,
+		event.WithProgressBatch(batchSize, flushInterval),
+		event.WithMaxBufferedRows(maxBuffered),
+		event.WithFlushErrorHandler(func(err error) {
+			sugar.Errorw("Failed to flush progress rows", "error", err)
+		}),
+	)
+
+	sink := event.NewSheetsSink(event.NewSpreadsheetClient(s), opts...)
+
+	sugar.Info("Sheets service is ready!")
+
+	return sink
+}
+
+func mustInitTx(ctx context.Context, uri, dbname string) (repository.Tx, func() error) {
+	mongoClient, err := utils.ConnectMongoDB(ctx, uri)
 	if err != nil {
 		sugar.Fatal(err)
 	}
 
-	return h
+	return mongo.NewMongoTx(mongoClient, mongo.WithDBName(dbname)), func() error { return mongoClient.Disconnect(context.Background()) }
 }
 
 func mustSetTimezone(tz string) {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"net/http"
 	"os"
 	"time"
 
@@ -9,25 +10,36 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	_ "github.com/joho/godotenv/autoload"
+	"github.com/piatoss3612/my-study-bot/internal/api"
 	"github.com/piatoss3612/my-study-bot/internal/bot"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command/admin"
+	"github.com/piatoss3612/my-study-bot/internal/bot/command/attendance"
+	"github.com/piatoss3612/my-study-bot/internal/bot/command/export"
+	"github.com/piatoss3612/my-study-bot/internal/bot/command/facilitator"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command/feedback"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command/help"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command/info"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command/profile"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command/reflection"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command/registration"
+	"github.com/piatoss3612/my-study-bot/internal/bot/command/schedule"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command/submit"
 	"github.com/piatoss3612/my-study-bot/internal/cache"
 	"github.com/piatoss3612/my-study-bot/internal/cache/redis"
 	"github.com/piatoss3612/my-study-bot/internal/config"
 	"github.com/piatoss3612/my-study-bot/internal/pubsub"
 	"github.com/piatoss3612/my-study-bot/internal/pubsub/rabbitmq"
+	redisps "github.com/piatoss3612/my-study-bot/internal/pubsub/redis"
+	studyattendance "github.com/piatoss3612/my-study-bot/internal/study/attendance"
+	"github.com/piatoss3612/my-study-bot/internal/study/calendar"
+	"github.com/piatoss3612/my-study-bot/internal/study/event"
+	"github.com/piatoss3612/my-study-bot/internal/study/material"
 	"github.com/piatoss3612/my-study-bot/internal/study/repository"
 	"github.com/piatoss3612/my-study-bot/internal/study/repository/mongo"
 	"github.com/piatoss3612/my-study-bot/internal/study/service"
 	"github.com/piatoss3612/my-study-bot/internal/utils"
+	"github.com/piatoss3612/my-study-bot/internal/web"
 	"go.uber.org/zap"
 )
 
@@ -70,10 +82,10 @@ func run() {
 
 	sugar.Info("Study cache is ready!")
 
-	pub, pubClose := mustInitPublisher(ctx, cfg.RabbitMQ.Addr, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.Kind)
+	pub, pubClose := mustInitPublisher(ctx, cfg)
 	defer func() {
 		_ = pubClose()
-		sugar.Info("Disconnected from RabbitMQ!")
+		sugar.Infof("Disconnected from %s!", cfg.PubSub.Driver)
 	}()
 
 	sugar.Info("Study/Event publisher is ready!")
@@ -81,10 +93,61 @@ func run() {
 	svc := service.New(tx)
 	sugar.Info("Study service is ready!")
 
-	cmdReg := registerCommands(svc, pub, cache)
+	var scheduleOpts []schedule.ScheduleOptsFunc
+
+	if cfg.Calendar.BaseURL != "" {
+		scheduleOpts = append(scheduleOpts, schedule.WithFeedBaseURL(cfg.Calendar.BaseURL))
+	}
+
+	var submitOpts []submit.SubmitOptsFunc
+
+	// submitted links are probed only if enabled
+	if cfg.Submission.Probe {
+		var probeOpts []material.ProbeOptsFunc
+
+		if cfg.Submission.ProbeTimeout > 0 {
+			probeOpts = append(probeOpts, material.WithProbeClient(&http.Client{Timeout: cfg.Submission.ProbeTimeout}))
+		}
+
+		submitOpts = append(submitOpts, submit.WithProber(material.NewProber(probeOpts...)))
+	}
+
+	// attendance is tracked by voice states of the presentation channel
+	att := attendance.NewAttendanceCommand(svc, studyattendance.NewTracker(), sugar,
+		attendance.WithThreshold(cfg.Attendance.Threshold), attendance.WithAutoApply(cfg.Attendance.AutoApply))
+
+	adminOpts := append(mustInitAdminOpts(ctx), admin.WithAttendanceReviewer(att))
+
+	cmdReg := registerCommands(svc, pub, cache, scheduleOpts, submitOpts, adminOpts...)
+	att.Register(cmdReg)
+
 	handler := command.NewHandler(cmdReg.HandleFuncs())
 
-	b := bot.New(mustOpenDiscordSession(cfg.Discord.BotToken), sugar)
+	// calendar feed is authorized by the token of each study
+	botOpts := []bot.BotOptsFunc{
+		bot.WithHTTPHandler(calendar.Prefix, calendar.NewHandler(svc)),
+		bot.WithVoiceStateHandler(att.VoiceStateUpdate),
+	}
+
+	// api is served only if tokens are given
+	if len(cfg.API.Tokens) > 0 {
+		botOpts = append(botOpts, bot.WithHTTPHandler(api.Prefix, api.NewHandler(svc, api.TokenAuthorizer(cfg.API.Tokens))))
+		sugar.Info("Study API is ready!")
+	}
+
+	// dashboard is served only if oauth2 client is given
+	if cfg.Dashboard.ClientID != "" {
+		dashboard := web.NewDashboard(svc, web.Config{
+			ClientID:     cfg.Dashboard.ClientID,
+			ClientSecret: cfg.Dashboard.ClientSecret,
+			RedirectURL:  cfg.Dashboard.RedirectURL,
+		}, sugar)
+
+		botOpts = append(botOpts, bot.WithHTTPHandler(web.Prefix, dashboard))
+		sugar.Info("Study dashboard is ready!")
+	}
+
+	b := bot.New(mustOpenDiscordSession(cfg.Discord.BotToken), sugar, botOpts...)
 
 	stop, err := b.Run()
 	if err != nil {
@@ -139,7 +202,17 @@ func mustInitStudyCache(ctx context.Context, addr string, ttl time.Duration) cac
 	return redis.NewCache(cache)
 }
 
-func mustInitPublisher(ctx context.Context, addr, exchange, kind string) (pubsub.Publisher, func() error) {
+func mustInitPublisher(ctx context.Context, cfg *config.StudyConfig) (pubsub.Publisher, func() error) {
+	switch cfg.PubSub.Driver {
+	case config.PubSubDriverRedis:
+		return mustInitRedisPublisher(ctx, cfg.RedisStreams.Addr, cfg.RedisStreams.Stream, cfg.RedisStreams.MaxLen)
+	default:
+		cfg.PubSub.Driver = config.PubSubDriverRabbitMQ
+		return mustInitRabbitMQPublisher(ctx, cfg.RabbitMQ.Addr, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.Kind)
+	}
+}
+
+func mustInitRabbitMQPublisher(ctx context.Context, addr, exchange, kind string) (pubsub.Publisher, func() error) {
 	rabbit := <-utils.RedialRabbitMQ(ctx, addr)
 
 	if rabbit == nil {
@@ -154,6 +227,26 @@ func mustInitPublisher(ctx context.Context, addr, exchange, kind string) (pubsub
 	return pub, func() error { return rabbit.Close() }
 }
 
+func mustInitRedisPublisher(ctx context.Context, addr, stream string, maxLen int64) (pubsub.Publisher, func() error) {
+	client, err := utils.ConnectRedis(ctx, addr)
+	if err != nil {
+		sugar.Fatal(err)
+	}
+
+	var opts []redisps.PublisherOptsFunc
+
+	if maxLen > 0 {
+		opts = append(opts, redisps.WithMaxLen(maxLen))
+	}
+
+	pub, err := redisps.NewPublisher(client, stream, opts...)
+	if err != nil {
+		sugar.Fatal(err)
+	}
+
+	return pub, func() error { return client.Close() }
+}
+
 func mustOpenDiscordSession(token string) *discordgo.Session {
 	sess, err := discordgo.New("Bot " + token)
 	if err != nil {
@@ -163,17 +256,38 @@ func mustOpenDiscordSession(token string) *discordgo.Session {
 	return sess
 }
 
-func registerCommands(svc service.Service, pub pubsub.Publisher, cache cache.Cache) command.Registerer {
+func mustInitAdminOpts(ctx context.Context) []admin.AdminOptsFunc {
+	var opts []admin.AdminOptsFunc
+
+	// syncing round sheet is enabled only if credentials are given
+	if path := os.Getenv("SHEETS_CREDENTIALS"); path != "" {
+		srv, err := utils.ConnectSheets(ctx, path)
+		if err != nil {
+			sugar.Fatalf("Unable to retrieve Sheets client: %v", err)
+		}
+
+		opts = append(opts, admin.WithRoundSheetReader(event.NewRoundSheetReader(event.NewSpreadsheetClient(srv))))
+
+		sugar.Info("Round sheet reader is ready!")
+	}
+
+	return opts
+}
+
+func registerCommands(svc service.Service, pub pubsub.Publisher, cache cache.Cache, scheduleOpts []schedule.ScheduleOptsFunc, submitOpts []submit.SubmitOptsFunc, adminOpts ...admin.AdminOptsFunc) command.Registerer {
 	reg := command.NewRegisterer()
 
-	admin.NewAdminCommand(svc, pub, sugar).Register(reg)
+	admin.NewAdminCommand(svc, pub, sugar, adminOpts...).Register(reg)
 	help.NewHelpCommand().Register(reg)
 	profile.NewProfileCommand(sugar).Register(reg)
 	info.NewInfoCommand(svc, cache).Register(reg)
 	registration.NewRegistrationCommand(svc).Register(reg)
-	submit.NewSubmitCommand(svc).Register(reg)
+	submit.NewSubmitCommand(svc, sugar, submitOpts...).Register(reg)
 	feedback.NewFeedbackCommand(svc).Register(reg)
 	reflection.NewReflectionCommand(svc).Register(reg)
+	export.NewExportCommand(svc).Register(reg)
+	schedule.NewScheduleCommand(svc, scheduleOpts...).Register(reg)
+	facilitator.NewFacilitatorCommand(svc, sugar).Register(reg)
 
 	return reg
 }
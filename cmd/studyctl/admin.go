@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/bot/effect"
+	"github.com/piatoss3612/my-study-bot/internal/config"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"go.uber.org/zap"
+)
+
+// operations over the study service run with operator privilege, manager checks are skipped
+type operation func(ctx context.Context, cfg *config.StudyConfig, svc service.Service) error
+
+func withService(cfgPath string, op operation) error {
+	cfg, err := loadConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeoutContext()
+	defer cancel()
+
+	tx, close, err := connectTx(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = close() }()
+
+	return op(ctx, cfg, service.New(tx))
+}
+
+func requireGuild(fs *flag.FlagSet, args []string) (string, error) {
+	guildID := fs.String("guild", "", "id of the guild")
+
+	_ = fs.Parse(args)
+
+	if *guildID == "" {
+		return "", errors.New("-guild is required")
+	}
+
+	return *guildID, nil
+}
+
+func runShow(args []string) error {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+
+	guildID, err := requireGuild(fs, args)
+	if err != nil {
+		return err
+	}
+
+	return withService(*cfgPath, func(ctx context.Context, _ *config.StudyConfig, svc service.Service) error {
+		gs, err := svc.GetStudy(ctx, guildID)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintf(w, "id\t%s\n", gs.ID)
+		fmt.Fprintf(w, "guild\t%s\n", gs.GuildID)
+		fmt.Fprintf(w, "manager\t%s\n", gs.ManagerID)
+		fmt.Fprintf(w, "notice channel\t%s\n", gs.NoticeChannelID)
+		fmt.Fprintf(w, "reflection channel\t%s\n", gs.ReflectionChannelID)
+		fmt.Fprintf(w, "presentation channel\t%s\n", gs.PresentationChannelID)
+		fmt.Fprintf(w, "spreadsheet\t%s\n", gs.SpreadsheetURL)
+		fmt.Fprintf(w, "stage\t%s (%d)\n", gs.CurrentStage, gs.CurrentStage)
+		fmt.Fprintf(w, "ongoing round\t%s\n", gs.OngoingRoundID)
+		fmt.Fprintf(w, "total round\t%d\n", gs.TotalRound)
+		fmt.Fprintf(w, "created\t%s\n", gs.CreatedAt.Format(time.RFC3339))
+		fmt.Fprintf(w, "updated\t%s\n", gs.UpdatedAt.Format(time.RFC3339))
+
+		return w.Flush()
+	})
+}
+
+func runRounds(args []string) error {
+	fs := flag.NewFlagSet("rounds", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+
+	guildID, err := requireGuild(fs, args)
+	if err != nil {
+		return err
+	}
+
+	return withService(*cfgPath, func(ctx context.Context, _ *config.StudyConfig, svc service.Service) error {
+		rounds, err := svc.GetRounds(ctx, guildID)
+		if err != nil {
+			return err
+		}
+
+		sort.Slice(rounds, func(i, j int) bool { return rounds[i].Number < rounds[j].Number })
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(w, "NUMBER\tID\tTITLE\tSTAGE\tREGISTERED\tATTENDED\tCREATED")
+
+		for _, r := range rounds {
+			var registered, attended int
+
+			for _, m := range r.Members {
+				if m.Registered {
+					registered++
+				}
+
+				if m.Attended {
+					attended++
+				}
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%s\n",
+				r.Number, r.ID, r.Title, r.Stage, registered, attended, r.CreatedAt.Format(time.RFC3339))
+		}
+
+		return w.Flush()
+	})
+}
+
+func runStage(args []string) error {
+	fs := flag.NewFlagSet("stage", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+	rollback := fs.Bool("rollback", false, "roll back to the previous stage instead of moving to the next one")
+	publish := fs.Bool("publish", true, "publish progress events like the bot")
+	discord := fs.Bool("discord", true, "apply changes to discord like the bot, such as the scheduled event of the presentation")
+
+	guildID, err := requireGuild(fs, args)
+	if err != nil {
+		return err
+	}
+
+	return withService(*cfgPath, func(ctx context.Context, cfg *config.StudyConfig, svc service.Service) error {
+		update, validators := service.MoveStage, []service.UpdateValidator{service.ValidateToCheckOngoingRound}
+		if *rollback {
+			update = service.RollbackStage
+			validators = append(validators, service.ValidateToRollbackStage)
+		}
+
+		gs, gr, err := svc.UpdateRound(ctx, &service.UpdateParams{GuildID: guildID}, update, validators...)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("round %d is now at stage %s\n", gr.Number, gr.Stage)
+
+		if *discord {
+			if err := applyStageEffects(svc, cfg, *gs, *gr); err != nil {
+				return err
+			}
+		}
+
+		if !*publish {
+			return nil
+		}
+
+		evts := []study.Event{}
+
+		if gr.Stage.IsFinished() {
+			evt, err := roundFinishedEvent(*gr)
+			if err != nil {
+				return err
+			}
+
+			evts = append(evts, evt)
+		}
+
+		evt, err := study.NewEvent(study.EventTopicStudyRoundProgress, fmt.Sprintf("%s: %s", gr.Title, gr.Stage.String()))
+		if err != nil {
+			return err
+		}
+
+		evts = append(evts, evt)
+
+		return publishEvents(ctx, cfg, gs, evts...)
+	})
+}
+
+func runSetManager(args []string) error {
+	fs := flag.NewFlagSet("set-manager", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+	managerID := fs.String("user", "", "id of the new manager")
+
+	guildID, err := requireGuild(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *managerID == "" {
+		return errors.New("-user is required")
+	}
+
+	return withService(*cfgPath, func(ctx context.Context, _ *config.StudyConfig, svc service.Service) error {
+		gs, err := svc.UpdateStudy(ctx, &service.UpdateParams{
+			GuildID:   guildID,
+			ManagerID: *managerID,
+		}, service.UpdateManagerID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("manager of guild %s is set to %s\n", gs.GuildID, gs.ManagerID)
+
+		return nil
+	})
+}
+
+func runSetChannel(args []string) error {
+	fs := flag.NewFlagSet("set-channel", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+	kind := fs.String("kind", "", "kind of the channel: notice, reflection or presentation")
+	channelID := fs.String("channel", "", "id of the channel")
+
+	guildID, err := requireGuild(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *channelID == "" {
+		return errors.New("-channel is required")
+	}
+
+	var update service.UpdateFunc
+
+	switch *kind {
+	case "notice":
+		update = service.UpdateNoticeChannelID
+	case "reflection":
+		update = service.UpdateReflectionChannelID
+	case "presentation":
+		update = service.UpdatePresentationChannelID
+	default:
+		return fmt.Errorf("unknown channel kind: %q", *kind)
+	}
+
+	return withService(*cfgPath, func(ctx context.Context, _ *config.StudyConfig, svc service.Service) error {
+		_, err := svc.UpdateStudy(ctx, &service.UpdateParams{
+			GuildID:   guildID,
+			ChannelID: *channelID,
+		}, update)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s channel of guild %s is set to %s\n", *kind, guildID, *channelID)
+
+		return nil
+	})
+}
+
+func runAttend(args []string) error {
+	fs := flag.NewFlagSet("attend", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+	memberID := fs.String("member", "", "id of the speaker")
+
+	guildID, err := requireGuild(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *memberID == "" {
+		return errors.New("-member is required")
+	}
+
+	return withService(*cfgPath, func(ctx context.Context, _ *config.StudyConfig, svc service.Service) error {
+		_, gr, err := svc.UpdateRound(ctx, &service.UpdateParams{
+			GuildID:  guildID,
+			MemberID: *memberID,
+		}, service.CheckSpeakerAttendance, service.ValidateToCheckAttendance)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("attendance of %s is confirmed in round %d\n", *memberID, gr.Number)
+
+		return nil
+	})
+}
+
+func runRepublish(args []string) error {
+	fs := flag.NewFlagSet("republish", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+	number := fs.Int("round", 0, "number of the round")
+	topic := fs.String("topic", study.EventTopicStudyRoundFinished.String(), "topic of the event to republish")
+
+	guildID, err := requireGuild(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *number == 0 {
+		return errors.New("-round is required")
+	}
+
+	return withService(*cfgPath, func(ctx context.Context, cfg *config.StudyConfig, svc service.Service) error {
+		gs, err := svc.GetStudy(ctx, guildID)
+		if err != nil {
+			return err
+		}
+
+		rounds, err := svc.GetRounds(ctx, guildID)
+		if err != nil {
+			return err
+		}
+
+		var gr *study.Round
+
+		for _, r := range rounds {
+			if int(r.Number) == *number {
+				gr = r
+			}
+		}
+
+		if gr == nil {
+			return study.ErrRoundNotFound
+		}
+
+		var evt study.Event
+
+		// events are built in the same way as the bot
+		switch study.EventTopic(*topic) {
+		case study.EventTopicStudyRoundCreated:
+			evt, err = study.NewEvent(study.EventTopicStudyRoundCreated,
+				fmt.Sprintf("스터디 라운드가 생성되었습니다.\n제목: %s\n참여자: %d명", gr.Title, len(gr.Members)))
+		case study.EventTopicStudyRoundProgress:
+			evt, err = study.NewEvent(study.EventTopicStudyRoundProgress, fmt.Sprintf("%s: %s", gr.Title, gr.Stage.String()))
+		case study.EventTopicStudyRoundFinished:
+			if !gr.Stage.IsFinished() {
+				return errors.Join(study.ErrInvalidStage, fmt.Errorf("round %d is not finished", gr.Number))
+			}
+			evt, err = roundFinishedEvent(*gr)
+		default:
+			err = study.ErrUnknownEventTopic
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return publishEvents(ctx, cfg, gs, evt)
+	})
+}
+
+// discord side of the stage move, same as the bot does
+func applyStageEffects(svc service.Service, cfg *config.StudyConfig, gs study.Study, gr study.Round) error {
+	s, err := connectDiscord(cfg)
+	if err != nil {
+		return err
+	}
+
+	l, err := zap.NewDevelopment()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Sync() }()
+
+	effects := effect.NewEffects(svc, l.Sugar())
+	effects.SyncPresentationEvent(s, gs, gr)
+	effects.SyncSpeakerThreads(s, gs, gr)
+
+	return nil
+}
+
+func roundFinishedEvent(r study.Round) (study.Event, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return study.Event{}, err
+	}
+
+	return study.NewEvent(study.EventTopicStudyRoundFinished, "", b)
+}
+
+// events are exported to the spreadsheet and webhooks of the guild
+func publishEvents(ctx context.Context, cfg *config.StudyConfig, gs *study.Study, evts ...study.Event) error {
+	pub, close, err := connectPublisher(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = close() }()
+
+	for _, evt := range evts {
+		evt.SetStudy(*gs)
+
+		if err := pub.Publish(ctx, evt.Topic.String(), evt); err != nil {
+			return err
+		}
+
+		fmt.Printf("published %s\n", evt.Topic)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/piatoss3612/my-study-bot/internal/study/backup"
+)
+
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+	guildID := fs.String("guild", "", "id of the guild to back up")
+	out := fs.String("out", "", "path of the archive, stdout if empty")
+
+	_ = fs.Parse(args)
+
+	if *guildID == "" {
+		return errors.New("-guild is required")
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeoutContext()
+	defer cancel()
+
+	tx, close, err := connectTx(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = close() }()
+
+	a, err := backup.Backup(ctx, tx, *guildID)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+
+		w = f
+	}
+
+	if err := a.Write(w); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "backed up study of guild %s with %d rounds\n", a.GuildID, len(a.Rounds))
+
+	return nil
+}
+
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	cfgPath := configFlag(fs)
+	in := fs.String("in", "", "path of the archive")
+	guildID := fs.String("guild", "", "id of the target guild, the guild of the archive if empty")
+	managerID := fs.String("manager", "", "id of the manager in the target guild")
+	overwrite := fs.Bool("overwrite", false, "update study and rounds existing in the target guild, rounds not in the archive are rejected")
+	dryRun := fs.Bool("dry-run", false, "print differences without writing")
+
+	_ = fs.Parse(args)
+
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	a, err := backup.Read(f)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeoutContext()
+	defer cancel()
+
+	tx, close, err := connectTx(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = close() }()
+
+	plan, err := backup.Plan(ctx, tx, a, backup.RestoreOptions{
+		GuildID:   *guildID,
+		ManagerID: *managerID,
+		Overwrite: *overwrite,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, line := range plan.Diff {
+		fmt.Println(line)
+	}
+
+	if len(plan.Diff) == 0 {
+		fmt.Println("no differences")
+	}
+
+	if *dryRun {
+		return nil
+	}
+
+	s, err := backup.Apply(ctx, tx, plan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "restored study %s of guild %s with %d rounds\n", s.ID, s.GuildID, len(plan.Rounds))
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/piatoss3612/my-study-bot/internal/config"
+	"github.com/piatoss3612/my-study-bot/internal/pubsub"
+	"github.com/piatoss3612/my-study-bot/internal/pubsub/rabbitmq"
+	redisps "github.com/piatoss3612/my-study-bot/internal/pubsub/redis"
+	"github.com/piatoss3612/my-study-bot/internal/study/repository"
+	"github.com/piatoss3612/my-study-bot/internal/study/repository/mongo"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+// subcommand runs with its own flag set
+type subcommand struct {
+	usage string
+	run   func(args []string) error
+}
+
+var subcommands = map[string]subcommand{
+	"show": {
+		usage: "show study of a guild",
+		run:   runShow,
+	},
+	"rounds": {
+		usage: "list rounds of a guild",
+		run:   runRounds,
+	},
+	"stage": {
+		usage: "move ongoing round to the next stage or roll back to the previous one",
+		run:   runStage,
+	},
+	"set-manager": {
+		usage: "set manager of a study",
+		run:   runSetManager,
+	},
+	"set-channel": {
+		usage: "set notice, reflection or presentation channel of a study",
+		run:   runSetChannel,
+	},
+	"attend": {
+		usage: "mark attendance of a speaker in the ongoing round",
+		run:   runAttend,
+	},
+	"republish": {
+		usage: "republish events of a round",
+		run:   runRepublish,
+	},
+	"backup": {
+		usage: "dump study and rounds of a guild to a json archive",
+		run:   runBackup,
+	},
+	"restore": {
+		usage: "restore study and rounds from a json archive",
+		run:   runRestore,
+	},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := subcommands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	b := &strings.Builder{}
+	b.WriteString("usage: studyctl <command> [flags]\n\ncommands:\n")
+
+	for _, name := range names {
+		fmt.Fprintf(b, "  %-10s %s\n", name, subcommands[name].usage)
+	}
+
+	fmt.Fprint(os.Stderr, b.String())
+}
+
+// config file is shared with cmd/study
+func configFlag(fs *flag.FlagSet) *string {
+	return fs.String("config", os.Getenv("CONFIG_FILE"), "path of the config file of study bot")
+}
+
+func loadConfig(path string) (*config.StudyConfig, error) {
+	return config.NewStudyConfig(path)
+}
+
+func connectTx(ctx context.Context, cfg *config.StudyConfig) (repository.Tx, func() error, error) {
+	client, err := utils.ConnectMongoDB(ctx, cfg.MongoDB.URI)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mongo.NewMongoTx(client, mongo.WithDBName(cfg.MongoDB.DBName)), func() error { return client.Disconnect(context.Background()) }, nil
+}
+
+// publisher is chosen by the driver like cmd/study
+func connectPublisher(ctx context.Context, cfg *config.StudyConfig) (pubsub.Publisher, func() error, error) {
+	switch cfg.PubSub.Driver {
+	case config.PubSubDriverRedis:
+		client, err := utils.ConnectRedis(ctx, cfg.RedisStreams.Addr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var opts []redisps.PublisherOptsFunc
+
+		if cfg.RedisStreams.MaxLen > 0 {
+			opts = append(opts, redisps.WithMaxLen(cfg.RedisStreams.MaxLen))
+		}
+
+		pub, err := redisps.NewPublisher(client, cfg.RedisStreams.Stream, opts...)
+		if err != nil {
+			_ = client.Close()
+			return nil, nil, err
+		}
+
+		return pub, func() error { return client.Close() }, nil
+	default:
+		rabbit := <-utils.RedialRabbitMQ(ctx, cfg.RabbitMQ.Addr)
+		if rabbit == nil {
+			return nil, nil, errors.New("failed to connect to RabbitMQ")
+		}
+
+		pub, err := rabbitmq.NewPublisher(rabbit, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.Kind)
+		if err != nil {
+			_ = rabbit.Close()
+			return nil, nil, err
+		}
+
+		return pub, func() error { return rabbit.Close() }, nil
+	}
+}
+
+// session used only for rest calls, the gateway is not opened
+func connectDiscord(cfg *config.StudyConfig) (*discordgo.Session, error) {
+	if cfg.Discord.BotToken == "" {
+		return nil, errors.New("bot token is not set")
+	}
+
+	s, err := discordgo.New("Bot " + cfg.Discord.BotToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// embeds sent by the bot are authored by its user
+	u, err := s.User("@me")
+	if err != nil {
+		return nil, err
+	}
+
+	s.State.User = u
+
+	return s, nil
+}
+
+func timeoutContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 30*time.Second)
+}
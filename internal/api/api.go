@@ -0,0 +1,218 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+)
+
+// prefix of the api routes
+const Prefix = "/api/v1/"
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrBadRequest   = errors.New("bad request")
+)
+
+// Access is the level of data of the guild the request may read
+type Access int
+
+const (
+	AccessNone    Access = iota
+	AccessMember         // data shared with every member of the guild
+	AccessManager        // also votes of the topic poll and extensions granted by the manager
+)
+
+// reports the access of the request to data of the guild
+type Authorizer func(r *http.Request, guildID string) Access
+
+type api struct {
+	svc       service.Service
+	authorize Authorizer
+}
+
+// create read-only api over the study service, access to each guild is checked by the authorizer
+//
+//	GET /api/v1/guilds/{guildID}/study
+//	GET /api/v1/guilds/{guildID}/rounds?page=1&per_page=20&order=asc
+//	GET /api/v1/guilds/{guildID}/rounds/{number}
+//	GET /api/v1/guilds/{guildID}/rounds/{number}/members?page=1&per_page=20
+//	GET /api/v1/guilds/{guildID}/stats
+func NewHandler(svc service.Service, authorize Authorizer) http.Handler {
+	return &api{
+		svc:       svc,
+		authorize: authorize,
+	}
+}
+
+func (a *api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	// guilds/{guildID}/{resource}/...
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, Prefix), "/"), "/")
+	if len(parts) < 3 || parts[0] != "guilds" || parts[1] == "" {
+		writeError(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	guildID := parts[1]
+
+	access := a.authorize(r, guildID)
+	if access == AccessNone {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="study"`)
+		writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+		return
+	}
+
+	var err error
+
+	switch {
+	case len(parts) == 3 && parts[2] == "study":
+		err = a.getStudy(w, r, guildID, access)
+	case len(parts) == 3 && parts[2] == "rounds":
+		err = a.getRounds(w, r, guildID, access)
+	case len(parts) == 4 && parts[2] == "rounds":
+		err = a.getRound(w, r, guildID, parts[3], access)
+	case len(parts) == 5 && parts[2] == "rounds" && parts[4] == "members":
+		err = a.getMembers(w, r, guildID, parts[3], access)
+	case len(parts) == 3 && parts[2] == "stats":
+		err = a.getStats(w, r, guildID)
+	default:
+		err = ErrNotFound
+	}
+
+	if err != nil {
+		writeError(w, statusCode(err), err)
+	}
+}
+
+// each guild is accessed with its own token given as bearer token of the authorization header,
+// tokens are issued by the operator of the bot so they have the access of the manager
+func TokenAuthorizer(tokens map[string]string) Authorizer {
+	return func(r *http.Request, guildID string) Access {
+		token, ok := tokens[guildID]
+		if !ok || token == "" {
+			return AccessNone
+		}
+
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return AccessNone
+		}
+
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			return AccessNone
+		}
+		return AccessManager
+	}
+}
+
+// page of items with the total number of items
+type Page struct {
+	Items      interface{} `json:"items"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"per_page"`
+	Total      int         `json:"total"`
+	TotalPages int         `json:"total_pages"`
+}
+
+type pagination struct {
+	page    int
+	perPage int
+}
+
+func parsePagination(r *http.Request) (pagination, error) {
+	p := pagination{page: 1, perPage: defaultPerPage}
+
+	q := r.URL.Query()
+
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return p, errors.Join(ErrBadRequest, errors.New("page must be a positive integer"))
+		}
+		p.page = n
+	}
+
+	if v := q.Get("per_page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPerPage {
+			return p, errors.Join(ErrBadRequest, errors.New("per_page must be between 1 and 100"))
+		}
+		p.perPage = n
+	}
+
+	return p, nil
+}
+
+// bounds of the page in a slice of the given length
+func (p pagination) bounds(total int) (int, int) {
+	start := (p.page - 1) * p.perPage
+	if start > total {
+		start = total
+	}
+
+	end := start + p.perPage
+	if end > total {
+		end = total
+	}
+
+	return start, end
+}
+
+func (p pagination) wrap(items interface{}, total int) Page {
+	return Page{
+		Items:      items,
+		Page:       p.page,
+		PerPage:    p.perPage,
+		Total:      total,
+		TotalPages: (total + p.perPage - 1) / p.perPage,
+	}
+}
+
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNotFound),
+		errors.Is(err, study.ErrStudyNotFound),
+		errors.Is(err, study.ErrRoundNotFound),
+		errors.Is(err, study.ErrMemberNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	msg := err.Error()
+
+	// internal errors are not exposed
+	if code == http.StatusInternalServerError {
+		msg = http.StatusText(code)
+	}
+
+	writeJSON(w, code, map[string]string{"error": msg})
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+)
+
+type fakeService struct {
+	service.Service
+
+	studies map[string]*study.Study
+	rounds  map[string][]*study.Round
+}
+
+func (f *fakeService) GetStudy(_ context.Context, guildID string) (*study.Study, error) {
+	s, ok := f.studies[guildID]
+	if !ok {
+		return nil, study.ErrStudyNotFound
+	}
+	return s, nil
+}
+
+func (f *fakeService) GetRounds(_ context.Context, guildID string) ([]*study.Round, error) {
+	return f.rounds[guildID], nil
+}
+
+func newTestHandler() http.Handler {
+	return newTestHandlerWith(TokenAuthorizer(map[string]string{"g1": "secret", "g2": "other"}))
+}
+
+func newTestHandlerWith(authorize Authorizer) http.Handler {
+	poll := study.NewTopicPoll("c1", "p1")
+	proposal := poll.Propose("채널", "u1")
+	poll.Vote("u2", proposal.ID)
+
+	svc := &fakeService{
+		studies: map[string]*study.Study{
+			"g1": {GuildID: "g1", ManagerID: "m1", TotalRound: 3, TopicPoll: &poll},
+			"g2": {GuildID: "g2"},
+		},
+		rounds: map[string][]*study.Round{},
+	}
+
+	for _, n := range []int8{3, 1, 2} {
+		r := study.NewRound()
+		r.SetGuildID("g1")
+		r.SetNumber(n)
+		r.SetStage(study.StageFinished)
+
+		a := study.NewMember()
+		a.SetName("alice")
+		a.SetRegistered(true)
+		a.SetAttended(n != 2)
+		a.SetContentURL("https://example.com")
+		a.SetExtendedUntil(time.Date(2026, 3, 7, 18, 0, 0, 0, time.UTC))
+		r.SetMember("u1", a)
+
+		b := study.NewMember()
+		b.SetName("bob")
+		b.SetReviewer("u1")
+		r.SetMember("u2", b)
+
+		svc.rounds["g1"] = append(svc.rounds["g1"], &r)
+	}
+
+	return NewHandler(svc, authorize)
+}
+
+func doRequest(t *testing.T, h http.Handler, path, token string, v interface{}) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if v != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+
+	return rec.Code
+}
+
+func TestAPIAuthorization(t *testing.T) {
+	h := newTestHandler()
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		code  int
+	}{
+		{"valid token", "/api/v1/guilds/g1/study", "secret", http.StatusOK},
+		{"missing token", "/api/v1/guilds/g1/study", "", http.StatusUnauthorized},
+		{"token of another guild", "/api/v1/guilds/g1/study", "other", http.StatusUnauthorized},
+		{"unknown guild", "/api/v1/guilds/g3/study", "secret", http.StatusUnauthorized},
+		{"unknown resource", "/api/v1/guilds/g1/unknown", "secret", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := doRequest(t, h, tt.path, tt.token, nil); code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, code)
+			}
+		})
+	}
+}
+
+func TestAPIRoundsPagination(t *testing.T) {
+	h := newTestHandler()
+
+	var page struct {
+		Items      []study.Round `json:"items"`
+		Page       int           `json:"page"`
+		Total      int           `json:"total"`
+		TotalPages int           `json:"total_pages"`
+	}
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/rounds?page=2&per_page=2", "secret", &page); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	if page.Total != 3 || page.TotalPages != 2 || page.Page != 2 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	if len(page.Items) != 1 || page.Items[0].Number != 3 {
+		t.Fatalf("expected round 3 on the second page, got %+v", page.Items)
+	}
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/rounds?per_page=101", "secret", nil); code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", code)
+	}
+
+	// study without rounds
+	if code := doRequest(t, h, "/api/v1/guilds/g2/rounds", "other", &page); code != http.StatusOK || page.Total != 0 {
+		t.Fatalf("expected empty page, got status %d and %+v", code, page)
+	}
+}
+
+func TestAPIRoundAndMembers(t *testing.T) {
+	h := newTestHandler()
+
+	var r study.Round
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/rounds/2", "secret", &r); code != http.StatusOK || r.Number != 2 {
+		t.Fatalf("expected round 2, got status %d and %+v", code, r)
+	}
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/rounds/9", "secret", nil); code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", code)
+	}
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/rounds/x", "secret", nil); code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", code)
+	}
+
+	var page struct {
+		Items []MemberResponse `json:"items"`
+	}
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/rounds/1/members", "secret", &page); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	if len(page.Items) != 2 || page.Items[0].ID != "u1" || page.Items[0].Name != "alice" {
+		t.Fatalf("unexpected members: %+v", page.Items)
+	}
+}
+
+func TestAPIStats(t *testing.T) {
+	h := newTestHandler()
+
+	var stats Stats
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/stats", "secret", &stats); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	if stats.TotalRounds != 3 || stats.FinishedRounds != 3 || len(stats.Members) != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	alice := stats.Members[0]
+
+	if alice.Registered != 3 || alice.Attended != 2 || alice.Submitted != 3 {
+		t.Fatalf("unexpected stats of alice: %+v", alice)
+	}
+
+	if alice.AttendanceRate < 0.66 || alice.AttendanceRate > 0.67 {
+		t.Fatalf("unexpected attendance rate: %f", alice.AttendanceRate)
+	}
+}
+
+func TestAPIHidesReviewers(t *testing.T) {
+	h := newTestHandler()
+
+	// feedback is anonymous, reviewers must not be shared in any response
+	for _, path := range []string{
+		"/api/v1/guilds/g1/rounds",
+		"/api/v1/guilds/g1/rounds/1",
+		"/api/v1/guilds/g1/rounds/1/members",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", path, rec.Code)
+		}
+
+		if strings.Contains(rec.Body.String(), "reviewers") {
+			t.Fatalf("%s: reviewers are shared: %s", path, rec.Body.String())
+		}
+	}
+}
+
+func TestAPIAccessOfMember(t *testing.T) {
+	access := AccessMember
+
+	h := newTestHandlerWith(func(*http.Request, string) Access { return access })
+
+	var s study.Study
+	var r study.Round
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/study", "", &s); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/rounds/1", "", &r); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	// members see the proposals but not the votes and extensions
+	if s.TopicPoll == nil || len(s.TopicPoll.Proposals) != 1 || len(s.TopicPoll.Votes) != 0 {
+		t.Fatalf("unexpected topic poll for member: %+v", s.TopicPoll)
+	}
+
+	if !r.Members["u1"].ExtendedUntil.IsZero() {
+		t.Fatalf("extension is shown to member: %+v", r.Members["u1"])
+	}
+
+	access = AccessManager
+	s, r = study.Study{}, study.Round{}
+
+	_ = doRequest(t, h, "/api/v1/guilds/g1/study", "", &s)
+	_ = doRequest(t, h, "/api/v1/guilds/g1/rounds/1", "", &r)
+
+	if s.TopicPoll == nil || s.TopicPoll.Votes["u2"] != "1" || r.Members["u1"].ExtendedUntil.IsZero() {
+		t.Fatalf("manager should see votes and extensions: %+v %+v", s.TopicPoll, r.Members["u1"])
+	}
+
+	access = AccessNone
+
+	if code := doRequest(t, h, "/api/v1/guilds/g1/study", "", nil); code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", code)
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// member with its id, members are stored in a map keyed by id
+type MemberResponse struct {
+	ID string `json:"id"`
+	study.PublicMember
+}
+
+func (a *api) getStudy(w http.ResponseWriter, r *http.Request, guildID string, access Access) error {
+	found, err := a.svc.GetStudy(r.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	// study returned by the service is not modified
+	s := *found
+
+	// webhooks and calendar token are secrets
+	s.Webhooks = nil
+	s.CalendarToken = ""
+
+	// members see the proposals but not who voted for them
+	if s.TopicPoll != nil && access < AccessManager {
+		poll := *s.TopicPoll
+		poll.Votes = nil
+		s.TopicPoll = &poll
+	}
+
+	writeJSON(w, http.StatusOK, s)
+	return nil
+}
+
+func (a *api) getRounds(w http.ResponseWriter, r *http.Request, guildID string, access Access) error {
+	p, err := parsePagination(r)
+	if err != nil {
+		return err
+	}
+
+	rounds, err := a.rounds(r, guildID)
+	if err != nil {
+		return err
+	}
+
+	switch r.URL.Query().Get("order") {
+	case "", "asc":
+	case "desc":
+		sort.Slice(rounds, func(i, j int) bool { return rounds[i].Number > rounds[j].Number })
+	default:
+		return errors.Join(ErrBadRequest, errors.New("order must be asc or desc"))
+	}
+
+	start, end := p.bounds(len(rounds))
+
+	// reviewers of the anonymous feedback are not shared
+	items := make([]study.PublicRound, 0, end-start)
+
+	for _, gr := range rounds[start:end] {
+		items = append(items, publicRound(gr, access))
+	}
+
+	writeJSON(w, http.StatusOK, p.wrap(items, len(rounds)))
+	return nil
+}
+
+func (a *api) getRound(w http.ResponseWriter, r *http.Request, guildID, number string, access Access) error {
+	gr, err := a.round(r, guildID, number)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, publicRound(*gr, access))
+	return nil
+}
+
+func (a *api) getMembers(w http.ResponseWriter, r *http.Request, guildID, number string, access Access) error {
+	p, err := parsePagination(r)
+	if err != nil {
+		return err
+	}
+
+	gr, err := a.round(r, guildID, number)
+	if err != nil {
+		return err
+	}
+
+	public := publicRound(*gr, access)
+
+	members := make([]MemberResponse, 0, len(public.Members))
+
+	for id, m := range public.Members {
+		members = append(members, MemberResponse{ID: id, PublicMember: m})
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+
+	start, end := p.bounds(len(members))
+
+	writeJSON(w, http.StatusOK, p.wrap(members[start:end], len(members)))
+	return nil
+}
+
+func (a *api) getStats(w http.ResponseWriter, r *http.Request, guildID string) error {
+	rounds, err := a.rounds(r, guildID)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, NewStats(guildID, rounds))
+	return nil
+}
+
+// round without reviewers, extensions granted by the manager are shown only to the manager
+func publicRound(gr study.Round, access Access) study.PublicRound {
+	public := study.NewPublicRound(gr)
+
+	if access < AccessManager {
+		for id, m := range public.Members {
+			m.ExtendedUntil = time.Time{}
+			public.Members[id] = m
+		}
+	}
+
+	return public
+}
+
+// rounds of the guild sorted by number, study must exist
+func (a *api) rounds(r *http.Request, guildID string) ([]study.Round, error) {
+	if _, err := a.svc.GetStudy(r.Context(), guildID); err != nil {
+		return nil, err
+	}
+
+	found, err := a.svc.GetRounds(r.Context(), guildID)
+	if err != nil && !errors.Is(err, study.ErrRoundNotFound) {
+		return nil, err
+	}
+
+	rounds := make([]study.Round, 0, len(found))
+
+	for _, gr := range found {
+		rounds = append(rounds, *gr)
+	}
+
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i].Number < rounds[j].Number })
+
+	return rounds, nil
+}
+
+func (a *api) round(r *http.Request, guildID, number string) (*study.Round, error) {
+	n, err := strconv.ParseInt(number, 10, 8)
+	if err != nil || n < 1 {
+		return nil, errors.Join(ErrBadRequest, errors.New("round number must be between 1 and 127"))
+	}
+
+	rounds, err := a.rounds(r, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, gr := range rounds {
+		if gr.Number == int8(n) {
+			return &gr, nil
+		}
+	}
+
+	return nil, study.ErrRoundNotFound
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// statistics over all rounds of a guild
+type Stats struct {
+	GuildID        string        `json:"guild_id"`
+	TotalRounds    int           `json:"total_rounds"`
+	FinishedRounds int           `json:"finished_rounds"`
+	Members        []MemberStats `json:"members"`
+}
+
+type MemberStats struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Rounds         int     `json:"rounds"`
+	Registered     int     `json:"registered"`
+	Submitted      int     `json:"submitted"`
+	Attended       int     `json:"attended"`
+	Reflections    int     `json:"reflections"`
+	AttendanceRate float64 `json:"attendance_rate"` // attended over registered
+}
+
+// rounds must be sorted by number, names are taken from the latest round
+func NewStats(guildID string, rounds []study.Round) Stats {
+	stats := Stats{
+		GuildID:     guildID,
+		TotalRounds: len(rounds),
+		Members:     []MemberStats{},
+	}
+
+	members := map[string]*MemberStats{}
+
+	for _, r := range rounds {
+		if r.Stage.IsFinished() {
+			stats.FinishedRounds++
+		}
+
+		for id, m := range r.Members {
+			ms, ok := members[id]
+			if !ok {
+				ms = &MemberStats{ID: id}
+				members[id] = ms
+			}
+
+			if m.Name != "" {
+				ms.Name = m.Name
+			}
+
+			ms.Rounds++
+
+			if m.Registered {
+				ms.Registered++
+			}
+
+			if m.ContentURL != "" {
+				ms.Submitted++
+			}
+
+			if m.Attended {
+				ms.Attended++
+			}
+
+			if m.SentReflection {
+				ms.Reflections++
+			}
+		}
+	}
+
+	for _, ms := range members {
+		if ms.Registered > 0 {
+			ms.AttendanceRate = float64(ms.Attended) / float64(ms.Registered)
+		}
+
+		stats.Members = append(stats.Members, *ms)
+	}
+
+	sort.Slice(stats.Members, func(i, j int) bool { return stats.Members[i].ID < stats.Members[j].ID })
+
+	return stats
+}
@@ -56,26 +56,55 @@ type bot struct {
 	registeredCommands []*discordgo.ApplicationCommand
 	handler            command.Handler
 
-	srv *http.Server
+	srv      *http.Server
+	handlers map[string]http.Handler
+
+	voiceStateHandlers []func(*discordgo.Session, *discordgo.VoiceStateUpdate)
 
 	sugar *zap.SugaredLogger
 }
 
-func New(sess *discordgo.Session, sugar *zap.SugaredLogger) Bot {
+type BotOptsFunc func(*bot)
+
+// serve additional handler on the metric server
+func WithHTTPHandler(pattern string, h http.Handler) BotOptsFunc {
+	return func(b *bot) {
+		b.handlers[pattern] = h
+	}
+}
+
+// handle voice state updates of guild members
+func WithVoiceStateHandler(h func(*discordgo.Session, *discordgo.VoiceStateUpdate)) BotOptsFunc {
+	return func(b *bot) {
+		b.voiceStateHandlers = append(b.voiceStateHandlers, h)
+	}
+}
+
+func New(sess *discordgo.Session, sugar *zap.SugaredLogger, opts ...BotOptsFunc) Bot {
 	b := &bot{
-		sess:  sess,
-		sugar: sugar,
+		sess:     sess,
+		handlers: map[string]http.Handler{},
+		sugar:    sugar,
 	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
 	return b.setup()
 }
 
 func (b *bot) setup() Bot {
 	b.sess.Identify.Intents = discordgo.IntentGuildMembers | discordgo.IntentGuildMessages |
-		discordgo.IntentGuilds | discordgo.IntentDirectMessages
+		discordgo.IntentGuilds | discordgo.IntentDirectMessages | discordgo.IntentGuildVoiceStates
 
 	b.sess.AddHandler(b.ready)
 	b.sess.AddHandler(b.handleApplicationCommand)
 
+	for _, h := range b.voiceStateHandlers {
+		b.sess.AddHandler(h)
+	}
+
 	metrics := prometheus.NewRegistry()
 	metrics.MustRegister(collectors.NewGoCollector())
 	metrics.MustRegister(totalRequests)
@@ -88,6 +117,10 @@ func (b *bot) setup() Bot {
 		w.WriteHeader(http.StatusOK)
 	})
 
+	for pattern, h := range b.handlers {
+		mux.Handle(pattern, h)
+	}
+
 	b.srv = &http.Server{
 		Addr:    fmt.Sprintf(":%s", metricServerPort),
 		Handler: mux,
@@ -5,12 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command"
+	"github.com/piatoss3612/my-study-bot/internal/bot/effect"
 	"github.com/piatoss3612/my-study-bot/internal/pubsub"
 	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/event"
+	"github.com/piatoss3612/my-study-bot/internal/study/material"
 	"github.com/piatoss3612/my-study-bot/internal/study/service"
 	"github.com/piatoss3612/my-study-bot/internal/utils"
 	"go.uber.org/zap"
@@ -20,21 +25,72 @@ type adminCommand struct {
 	svc service.Service
 	pub pubsub.Publisher
 
+	reader     event.RoundSheetReader
+	webhooks   event.WebhookSink
+	attendance AttendanceReviewer
+	effects    effect.Effects
+
+	mtx      *sync.Mutex
+	syncings map[string]pendingSync // guild id to changes waiting for confirmation
+
 	sugar *zap.SugaredLogger
 }
 
-func NewAdminCommand(svc service.Service, pub pubsub.Publisher, sugar *zap.SugaredLogger) command.Command {
-	return &adminCommand{
-		svc:   svc,
-		pub:   pub,
-		sugar: sugar,
+// AttendanceReviewer tracks speakers in the presentation channel
+type AttendanceReviewer interface {
+	StartAttendance(s *discordgo.Session, gs study.Study, gr study.Round)
+	ReviewAttendance(s *discordgo.Session, i *discordgo.InteractionCreate, gs study.Study, gr study.Round)
+}
+
+type AdminOptsFunc func(*adminCommand)
+
+// enable reviewing attendance of speakers when the presentation is finished
+func WithAttendanceReviewer(ar AttendanceReviewer) AdminOptsFunc {
+	return func(ac *adminCommand) {
+		ac.attendance = ar
 	}
 }
 
+// sink used to test webhooks
+func WithWebhookSink(ws event.WebhookSink) AdminOptsFunc {
+	return func(ac *adminCommand) {
+		ac.webhooks = ws
+	}
+}
+
+// enable syncing round with the edits of round sheet
+func WithRoundSheetReader(reader event.RoundSheetReader) AdminOptsFunc {
+	return func(ac *adminCommand) {
+		ac.reader = reader
+	}
+}
+
+func NewAdminCommand(svc service.Service, pub pubsub.Publisher, sugar *zap.SugaredLogger, opts ...AdminOptsFunc) command.Command {
+	ac := &adminCommand{
+		svc:      svc,
+		pub:      pub,
+		mtx:      &sync.Mutex{},
+		syncings: map[string]pendingSync{},
+		webhooks: event.NewWebhookSink(event.WithWebhookRetries(0)),
+		effects:  effect.NewEffects(svc, sugar),
+		sugar:    sugar,
+	}
+
+	for _, opt := range opts {
+		opt(ac)
+	}
+
+	return ac
+}
+
 func (ac *adminCommand) Register(reg command.Registerer) {
 	reg.RegisterCommand(adminCmd, ac.adminHandler)
 	reg.RegisterHandler(noticeModalCustomID, ac.sendNotice)
 	reg.RegisterHandler(stageMoveConfirmButton.CustomID, ac.moveRoundStageConfirm)
+	reg.RegisterHandler(roundSheetSyncConfirmButton.CustomID, ac.syncRoundSheetConfirm)
+	reg.RegisterHandler(topicPollProposeButton.CustomID, ac.showTopicProposalModal)
+	reg.RegisterHandler(topicPollModalCustomID, ac.proposeTopic)
+	reg.RegisterHandler(topicPollVoteSelectMenu.CustomID, ac.voteTopic)
 }
 
 // handle admin command
@@ -52,6 +108,8 @@ func (ac *adminCommand) adminHandler(s *discordgo.Session, i *discordgo.Interact
 	var txt string
 	var u *discordgo.User
 	var ch *discordgo.Channel
+	var topic string
+	var number int
 
 	for _, o := range options[1:] {
 		switch o.Name {
@@ -61,6 +119,10 @@ func (ac *adminCommand) adminHandler(s *discordgo.Session, i *discordgo.Interact
 			u = o.UserValue(s)
 		case "채널":
 			ch = o.ChannelValue(s)
+		case "토픽":
+			topic = o.StringValue()
+		case "숫자":
+			number = int(o.IntValue())
 		}
 	}
 
@@ -74,7 +136,7 @@ func (ac *adminCommand) adminHandler(s *discordgo.Session, i *discordgo.Interact
 	case "refresh-status":
 		err = ac.refreshBotStatus(s, i)
 	case "create-study-round":
-		err = ac.createRound(s, i, txt)
+		err = ac.createRound(s, i, txt, number)
 	case "move-round-stage":
 		err = ac.moveRoundStage(s, i)
 	case "confirm-attendance":
@@ -85,8 +147,32 @@ func (ac *adminCommand) adminHandler(s *discordgo.Session, i *discordgo.Interact
 		err = ac.setNoticeChannel(s, i, ch)
 	case "set-reflection-channel":
 		err = ac.setReflectionChannel(s, i, ch)
+	case "set-presentation-channel":
+		err = ac.setPresentationChannel(s, i, ch)
 	case "set-spreadsheet":
 		err = ac.setSpreadsheet(s, i, txt)
+	case "sync-round-sheet":
+		err = ac.syncRoundSheet(s, i, txt)
+	case "set-schedule":
+		err = ac.setSchedule(s, i, txt)
+	case "generate-timetable":
+		err = ac.generateTimetable(s, i, txt)
+	case "swap-timetable":
+		err = ac.swapTimetable(s, i, txt)
+	case "extend-submission":
+		err = ac.extendSubmission(s, i, u, txt, number)
+	case "set-material-hosts":
+		err = ac.setMaterialHosts(s, i, txt)
+	case "open-topic-poll":
+		err = ac.openTopicPoll(s, i, ch)
+	case "close-topic-poll":
+		err = ac.closeTopicPoll(s, i, number)
+	case "register-webhook":
+		err = ac.registerWebhook(s, i, txt, topic)
+	case "remove-webhook":
+		err = ac.removeWebhook(s, i, txt)
+	case "test-webhook":
+		err = ac.testWebhook(s, i, txt)
 	default:
 		err = study.ErrInvalidCommand
 	}
@@ -279,8 +365,8 @@ func (ac *adminCommand) refreshBotStatus(s *discordgo.Session, i *discordgo.Inte
 	})
 }
 
-// create round of study
-func (ac *adminCommand) createRound(s *discordgo.Session, i *discordgo.InteractionCreate, title string) error {
+// create round of study, zero max speakers means unlimited
+func (ac *adminCommand) createRound(s *discordgo.Session, i *discordgo.InteractionCreate, title string, maxSpeakers int) error {
 	manager := utils.GetGuildUserFromInteraction(i)
 	if manager == nil {
 		return study.ErrManagerNotFound
@@ -313,10 +399,11 @@ func (ac *adminCommand) createRound(s *discordgo.Session, i *discordgo.Interacti
 
 	// create a round
 	gs, err := ac.svc.NewRound(ctx, &service.NewRoundParams{
-		GuildID:   i.GuildID,
-		ManagerID: manager.ID,
-		Title:     title,
-		MemberIDs: memberIDs,
+		GuildID:     i.GuildID,
+		ManagerID:   manager.ID,
+		Title:       title,
+		MemberIDs:   memberIDs,
+		MaxSpeakers: maxSpeakers,
 	})
 	if err != nil {
 		return err
@@ -325,6 +412,10 @@ func (ac *adminCommand) createRound(s *discordgo.Session, i *discordgo.Interacti
 	embed := adminEmbed(s.State.User, "스터디 라운드 생성", fmt.Sprintf("**<%s>**가 생성되었습니다.", title))
 	description := fmt.Sprintf("스터디 라운드가 생성되었습니다.\n제목: %s\n참여자: %d명", title, len(memberIDs))
 
+	if maxSpeakers > 0 {
+		description += fmt.Sprintf("\n발표자 정원: %d명", maxSpeakers)
+	}
+
 	go func() {
 		evt, err := study.NewEvent(study.EventTopicStudyRoundCreated, description)
 		if err != nil {
@@ -333,7 +424,7 @@ func (ac *adminCommand) createRound(s *discordgo.Session, i *discordgo.Interacti
 		}
 
 		// publish an event
-		go ac.publishEvent(evt)
+		go ac.publishEvent(*gs, evt)
 	}()
 
 	// send a DM to all members
@@ -448,10 +539,15 @@ func (ac *adminCommand) moveRoundStageConfirm(s *discordgo.Session, i *discordgo
 			}
 
 			// publish an event
-			go ac.publishEvent(evt)
+			go ac.publishEvent(*gs, evt)
 		}(study.EventTopicStudyRoundFinished, "", *gr)
 	} else {
 		embed = adminEmbed(s.State.User, gr.Stage.String(), fmt.Sprintf("**<%s>**이(가) 시작되었습니다.", gr.Stage.String()))
+
+		// speakers are noticed of their turns
+		if gr.Stage.IsPresentationStarted() && len(gr.Timetable) > 0 {
+			embed.Fields = append(embed.Fields, utils.TimetableFields("발표 순서", *gr)...)
+		}
 	}
 
 	go func(topic study.EventTopic, desc string) {
@@ -462,9 +558,15 @@ func (ac *adminCommand) moveRoundStageConfirm(s *discordgo.Session, i *discordgo
 		}
 
 		// publish an event
-		go ac.publishEvent(evt)
+		go ac.publishEvent(*gs, evt)
 	}(study.EventTopicStudyRoundProgress, fmt.Sprintf("%s: %s", gr.Title, gr.Stage.String()))
 
+	// create or update the scheduled event of the presentation
+	go ac.effects.SyncPresentationEvent(s, *gs, *gr)
+
+	// create or archive discussion threads of the speakers
+	go ac.effects.SyncSpeakerThreads(s, *gs, *gr)
+
 	// send a DM to all members
 	go ac.sendDMsToAllMember(s, embed, i.GuildID)
 
@@ -483,13 +585,28 @@ func (ac *adminCommand) moveRoundStageConfirm(s *discordgo.Session, i *discordgo
 	}
 
 	// send a response message
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: "스터디 라운드가 이동되었습니다.",
 			Flags:   discordgo.MessageFlagsEphemeral,
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	if ac.attendance != nil {
+		switch {
+		case gr.Stage.IsPresentationStarted():
+			go ac.attendance.StartAttendance(s, *gs, *gr)
+		case gr.Stage.IsPresentationFinished():
+			// review is sent as a follow-up of the response
+			go ac.attendance.ReviewAttendance(s, i, *gs, *gr)
+		}
+	}
+
+	return nil
 }
 
 // check attendance
@@ -565,7 +682,7 @@ func (ac *adminCommand) registerRecordedContent(s *discordgo.Session, i *discord
 		}
 
 		// publish an event
-		go ac.publishEvent(evt)
+		go ac.publishEvent(*gs, evt)
 	}()
 
 	// send a DM to all members
@@ -674,7 +791,7 @@ func (ac *adminCommand) setSpreadsheet(s *discordgo.Session, i *discordgo.Intera
 		GuildID:    i.GuildID,
 		ManagerID:  manager.ID,
 		ContentURL: url,
-	}, service.SetSpreadsheetURL, service.ValidateToCheckManager)
+	}, service.SetSpreadsheetURL, service.ValidateToCheckManager, service.ValidateToSetSpreadsheetURL)
 	if err != nil {
 		return err
 	}
@@ -688,3 +805,47 @@ func (ac *adminCommand) setSpreadsheet(s *discordgo.Session, i *discordgo.Intera
 		},
 	})
 }
+
+// set allowed hosts of materials, "기본" restores the defaults
+func (ac *adminCommand) setMaterialHosts(s *discordgo.Session, i *discordgo.InteractionCreate, txt string) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	var hosts []string
+
+	if strings.TrimSpace(txt) != "기본" {
+		var err error
+
+		hosts, err = material.ParseHosts(txt)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := ac.svc.UpdateStudy(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		Hosts:     hosts,
+	}, service.UpdateMaterialHosts, service.ValidateToCheckManager)
+	if err != nil {
+		return err
+	}
+
+	if len(hosts) == 0 {
+		hosts = material.DefaultHosts
+	}
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("발표 자료 허용 도메인이 설정되었습니다.\n%s", strings.Join(hosts, ", ")),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
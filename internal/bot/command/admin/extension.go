@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+// extend the submission deadline of the member until the given time or for the given hours, "취소" revokes the extension
+func (ac *adminCommand) extendSubmission(s *discordgo.Session, i *discordgo.InteractionCreate, u *discordgo.User, txt string, hours int) error {
+	if u == nil {
+		return study.ErrUserNotFound
+	}
+
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	until, err := parseExtension(txt, hours, time.Now(), time.Local)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, gr, err := ac.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		MemberID:  u.ID,
+		Until:     until,
+	}, service.GrantExtension,
+		service.ValidateToCheckManager, service.ValidateToCheckOngoingRound, service.ValidateToGrantExtension)
+	if err != nil {
+		return err
+	}
+
+	var msg string
+
+	if until.IsZero() {
+		msg = fmt.Sprintf("%d회차 발표 자료 제출 기한 연장이 취소되었습니다.", gr.Number)
+	} else {
+		msg = fmt.Sprintf("%d회차 발표 자료 제출 기한이 <t:%d:F>까지 연장되었습니다. 기한이 지난 제출은 지각 제출로 기록됩니다.", gr.Number, until.Unix())
+	}
+
+	embed := adminEmbed(s.State.User, "제출 기한 연장", msg)
+
+	// send a DM to the user
+	go ac.sendDMToMember(s, u, embed)
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("%s님의 %s", u.Mention(), msg),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// extended deadline from the time text or the hours from now, zero time revokes the extension
+func parseExtension(txt string, hours int, now time.Time, loc *time.Location) (time.Time, error) {
+	txt = strings.TrimSpace(txt)
+
+	switch {
+	case txt == "취소":
+		return time.Time{}, nil
+	case txt != "":
+		t, err := time.ParseInLocation(scheduleTimeLayout, txt, loc)
+		if err != nil {
+			return time.Time{}, errors.Join(study.ErrInvalidArgs, fmt.Errorf("'%s' 형식으로 시간을 입력해주세요: %s", scheduleTimeLayout, txt))
+		}
+		return t, nil
+	case hours > 0:
+		return now.Add(time.Duration(hours) * time.Hour), nil
+	default:
+		return time.Time{}, errors.Join(study.ErrRequiredArgs, fmt.Errorf("텍스트에 '%s' 형식의 기한을 입력하거나 숫자에 연장할 시간을 입력해주세요", scheduleTimeLayout))
+	}
+}
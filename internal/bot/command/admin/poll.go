@@ -0,0 +1,343 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+// post a topic poll to the channel, members propose and vote for the title of the next round
+func (ac *adminCommand) openTopicPoll(s *discordgo.Session, i *discordgo.InteractionCreate, ch *discordgo.Channel) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// get study
+	gs, err := ac.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	// check manager
+	if !gs.IsManager(manager.ID) {
+		return study.ErrNotManager
+	}
+
+	if gs.TopicPoll != nil {
+		return study.ErrTopicPollExists
+	}
+
+	// poll is posted to the given channel, or to the notice channel
+	channelID := i.ChannelID
+	if ch != nil {
+		channelID = ch.ID
+	} else if gs.NoticeChannelID != "" {
+		channelID = gs.NoticeChannelID
+	}
+
+	poll := study.NewTopicPoll(channelID, "")
+
+	msg, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{topicPollEmbed(s.State.User, poll, false)},
+		Components: topicPollComponents(poll),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = ac.svc.UpdateStudy(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		ChannelID: channelID,
+		MessageID: msg.ID,
+	}, service.OpenTopicPoll,
+		service.ValidateToCheckManager, service.ValidateToOpenTopicPoll)
+	if err != nil {
+		// poll message without the poll is useless
+		if err := s.ChannelMessageDelete(channelID, msg.ID); err != nil {
+			ac.sugar.Errorw("failed to delete topic poll message", "error", err, "guild_id", i.GuildID)
+		}
+		return err
+	}
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("<#%s> 채널에서 주제 투표가 시작되었습니다.", channelID),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// close the topic poll and create a round titled with the winning topic
+func (ac *adminCommand) closeTopicPoll(s *discordgo.Session, i *discordgo.InteractionCreate, maxSpeakers int) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// get study
+	gs, err := ac.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	// check manager
+	if !gs.IsManager(manager.ID) {
+		return study.ErrNotManager
+	}
+
+	if gs.TopicPoll == nil {
+		return study.ErrTopicPollNotFound
+	}
+
+	poll := *gs.TopicPoll
+
+	winner, ok := poll.Winner()
+	if ok {
+		// round is created before the poll is cleared, so the poll remains if creating fails
+		if err := ac.createRound(s, i, winner.Title, maxSpeakers); err != nil {
+			return err
+		}
+	}
+
+	_, err = ac.svc.UpdateStudy(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+	}, service.CloseTopicPoll,
+		service.ValidateToCheckManager, service.ValidateToCloseTopicPoll)
+	if err != nil {
+		if ok {
+			// response is already sent by creating the round
+			ac.sugar.Errorw("failed to close topic poll", "error", err, "guild_id", i.GuildID)
+			return nil
+		}
+		return err
+	}
+
+	// show the result without components
+	_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         poll.MessageID,
+		Channel:    poll.ChannelID,
+		Embeds:     []*discordgo.MessageEmbed{topicPollEmbed(s.State.User, poll, true)},
+		Components: []discordgo.MessageComponent{},
+	})
+	if err != nil {
+		ac.sugar.Errorw("failed to edit topic poll message", "error", err, "guild_id", i.GuildID)
+	}
+
+	if ok {
+		return nil
+	}
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "제안된 주제가 없어 라운드를 생성하지 않고 주제 투표를 마감했습니다.",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// show modal to propose a topic
+func (ac *adminCommand) showTopicProposalModal(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// get study
+	gs, err := ac.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	if gs.TopicPoll == nil {
+		return study.ErrTopicPollNotFound
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: topicPollModalCustomID,
+			Title:    "주제 제안",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{topicTitleTextInput},
+				},
+			},
+		},
+	})
+}
+
+// add the proposed topic to the poll
+func (ac *adminCommand) proposeTopic(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	member := utils.GetGuildUserFromInteraction(i)
+	if member == nil {
+		return study.ErrUserNotFound
+	}
+
+	var title string
+
+	for _, c := range i.ModalSubmitData().Components {
+		row, ok := c.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+
+		for _, c := range row.Components {
+			input, ok := c.(*discordgo.TextInput)
+			if !ok {
+				continue
+			}
+
+			title = strings.TrimSpace(input.Value)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gs, err := ac.svc.UpdateStudy(ctx, &service.UpdateParams{
+		GuildID:  i.GuildID,
+		MemberID: member.ID,
+		Proposal: study.TopicProposal{Title: title},
+	}, service.ProposeTopic, service.ValidateToProposeTopic)
+	if err != nil {
+		return err
+	}
+
+	ac.refreshTopicPoll(s, gs)
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("주제 **%s**을(를) 제안했습니다.", title),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// vote for the selected topic, the previous vote of the member is replaced
+func (ac *adminCommand) voteTopic(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	member := utils.GetGuildUserFromInteraction(i)
+	if member == nil {
+		return study.ErrUserNotFound
+	}
+
+	values := i.MessageComponentData().Values
+	if len(values) == 0 {
+		return errors.Join(study.ErrRequiredArgs, errors.New("투표할 주제를 선택해주세요"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gs, err := ac.svc.UpdateStudy(ctx, &service.UpdateParams{
+		GuildID:  i.GuildID,
+		MemberID: member.ID,
+		Proposal: study.TopicProposal{ID: values[0]},
+	}, service.VoteTopic, service.ValidateToVoteTopic)
+	if err != nil {
+		return err
+	}
+
+	ac.refreshTopicPoll(s, gs)
+
+	proposal, _ := gs.TopicPoll.GetProposal(values[0])
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("주제 **%s**에 투표했습니다.", proposal.Title),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// update the poll message with the current proposals and votes
+func (ac *adminCommand) refreshTopicPoll(s *discordgo.Session, gs *study.Study) {
+	if gs.TopicPoll == nil {
+		return
+	}
+
+	poll := *gs.TopicPoll
+
+	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         poll.MessageID,
+		Channel:    poll.ChannelID,
+		Embeds:     []*discordgo.MessageEmbed{topicPollEmbed(s.State.User, poll, false)},
+		Components: topicPollComponents(poll),
+	})
+	if err != nil {
+		ac.sugar.Errorw("failed to edit topic poll message", "error", err, "guild_id", gs.GuildID)
+	}
+}
+
+func topicPollEmbed(u *discordgo.User, poll study.TopicPoll, closed bool) *discordgo.MessageEmbed {
+	b := &strings.Builder{}
+
+	if closed {
+		if winner, ok := poll.Winner(); ok {
+			fmt.Fprintf(b, "투표가 마감되었습니다. 다음 라운드의 주제는 **%s**입니다.\n\n", winner.Title)
+		} else {
+			b.WriteString("투표가 마감되었습니다. 제안된 주제가 없습니다.\n\n")
+		}
+	} else {
+		b.WriteString("다음 라운드에서 다룰 주제를 제안하고 투표해주세요. 한 사람당 한 표만 행사할 수 있으며, 다시 투표하면 이전 투표가 변경됩니다.\n\n")
+	}
+
+	if len(poll.Proposals) == 0 {
+		b.WriteString("아직 제안된 주제가 없습니다.")
+	}
+
+	for _, p := range poll.Proposals {
+		fmt.Fprintf(b, "%s. **%s** - %d표 (제안: <@%s>)\n", p.ID, p.Title, poll.Count(p.ID), p.ProposerID)
+	}
+
+	return adminEmbed(u, "다음 라운드 주제 투표", b.String())
+}
+
+// vote menu is shown only when there are proposals
+func topicPollComponents(poll study.TopicPoll) []discordgo.MessageComponent {
+	components := []discordgo.MessageComponent{}
+
+	if len(poll.Proposals) > 0 {
+		menu := topicPollVoteSelectMenu
+
+		for _, p := range poll.Proposals {
+			menu.Options = append(menu.Options, discordgo.SelectMenuOption{
+				Label: utils.Truncate(p.Title, 100),
+				Value: p.ID,
+			})
+		}
+
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{menu},
+		})
+	}
+
+	if len(poll.Proposals) < study.MaxTopicProposals {
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{topicPollProposeButton},
+		})
+	}
+
+	return components
+}
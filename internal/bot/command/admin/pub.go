@@ -7,8 +7,10 @@ import (
 	"github.com/piatoss3612/my-study-bot/internal/study"
 )
 
-// publish event to subscriber
-func (ac *adminCommand) publishEvent(evt study.Event) {
+// publish event of the study to subscriber
+func (ac *adminCommand) publishEvent(gs study.Study, evt study.Event) {
+	evt.SetStudy(gs)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+const scheduleTimeLayout = "2006-01-02 15:04"
+
+// set schedule of the ongoing round, e.g. "발표자 등록 마감=2023-06-01 23:59, 발표=2023-06-03 20:00"
+func (ac *adminCommand) setSchedule(s *discordgo.Session, i *discordgo.InteractionCreate, txt string) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	schedule, err := parseSchedule(txt, time.Local)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gs, gr, err := ac.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		Schedule:  schedule,
+	}, service.UpdateSchedule,
+		service.ValidateToCheckManager, service.ValidateToCheckOngoingRound, service.ValidateToUpdateSchedule)
+	if err != nil {
+		return err
+	}
+
+	// reflect the new schedule to the scheduled event
+	go ac.effects.SyncPresentationEvent(s, *gs, *gr)
+
+	embed := adminEmbed(s.State.User, fmt.Sprintf("%d회차 일정", gr.Number), scheduleDescription(*gr))
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// entries are separated by comma, empty time removes the entry
+func parseSchedule(txt string, loc *time.Location) ([]study.ScheduleEntry, error) {
+	var schedule []study.ScheduleEntry
+
+	for _, item := range strings.Split(txt, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, errors.Join(study.ErrInvalidArgs, fmt.Errorf("'단계=%s' 형식으로 입력해주세요", scheduleTimeLayout))
+		}
+
+		stage, ok := parseStage(strings.TrimSpace(name))
+		if !ok {
+			return nil, errors.Join(study.ErrInvalidStage, fmt.Errorf("알 수 없는 단계입니다: %s", strings.TrimSpace(name)))
+		}
+
+		var at time.Time
+
+		if value = strings.TrimSpace(value); value != "" {
+			t, err := time.ParseInLocation(scheduleTimeLayout, value, loc)
+			if err != nil {
+				return nil, errors.Join(study.ErrInvalidArgs, fmt.Errorf("'%s' 형식으로 시간을 입력해주세요: %s", scheduleTimeLayout, value))
+			}
+
+			at = t
+		}
+
+		schedule = append(schedule, study.ScheduleEntry{Stage: stage, At: at})
+	}
+
+	if len(schedule) == 0 {
+		return nil, errors.Join(study.ErrRequiredArgs, fmt.Errorf("'단계=%s' 형식으로 일정을 입력해주세요", scheduleTimeLayout))
+	}
+
+	return schedule, nil
+}
+
+// stage by its name or number
+func parseStage(name string) (study.Stage, bool) {
+	if n, err := strconv.Atoi(name); err == nil {
+		stage := study.Stage(n)
+		return stage, stage.IsSchedulable()
+	}
+
+	for stage := study.StageRegistrationOpened; stage.IsSchedulable(); stage++ {
+		if stage.String() == name {
+			return stage, true
+		}
+	}
+
+	return study.StageNone, false
+}
+
+// schedule of the round in order of stages
+func scheduleDescription(r study.Round) string {
+	if len(r.Schedule) == 0 {
+		return "설정된 일정이 없습니다."
+	}
+
+	b := &strings.Builder{}
+
+	for _, e := range r.Schedule {
+		fmt.Fprintf(b, "**%s**: <t:%d:F> (<t:%d:R>)\n", e.Stage, e.At.Unix(), e.At.Unix())
+	}
+
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+// set voice channel where the presentation is held
+func (ac *adminCommand) setPresentationChannel(s *discordgo.Session, i *discordgo.InteractionCreate, ch *discordgo.Channel) error {
+	// check if the channel is nil
+	if ch == nil {
+		return study.ErrChannelNotFound
+	}
+
+	if ch.Type != discordgo.ChannelTypeGuildVoice && ch.Type != discordgo.ChannelTypeGuildStageVoice {
+		return errors.Join(study.ErrInvalidArgs, errors.New("음성 채널 또는 스테이지 채널을 선택해주세요"))
+	}
+
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// set presentation channel
+	_, err := ac.svc.UpdateStudy(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		ChannelID: ch.ID,
+	}, service.UpdatePresentationChannelID, service.ValidateToCheckManager)
+	if err != nil {
+		return err
+	}
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("발표 음성 채널이 %s로 설정되었습니다.", ch.Mention()),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,202 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+// max length of the description of embed is 4096
+const maxSyncDescriptionLength = 3500
+
+// changes read from the round sheet waiting for confirmation of manager
+type pendingSync struct {
+	managerID string
+	roundID   string
+	changes   []study.RoundChange
+}
+
+// show changes of the round edited on the round sheet
+func (ac *adminCommand) syncRoundSheet(s *discordgo.Session, i *discordgo.InteractionCreate, txt string) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	if ac.reader == nil {
+		return errors.Join(study.ErrInvalidCommand, errors.New("스프레드시트 연동이 설정되지 않았습니다"))
+	}
+
+	number, err := strconv.ParseInt(strings.TrimSpace(txt), 10, 8)
+	if err != nil {
+		return errors.Join(study.ErrInvalidArgs, errors.New("동기화할 라운드 번호를 입력해주세요"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// get study
+	gs, err := ac.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	// check manager
+	if !gs.IsManager(manager.ID) {
+		return study.ErrNotManager
+	}
+
+	if gs.SpreadsheetURL == "" {
+		return errors.Join(study.ErrInvalidSpreadsheetURL, errors.New("스프레드시트를 먼저 설정해주세요"))
+	}
+
+	rounds, err := ac.svc.GetRounds(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	var current *study.Round
+
+	for _, r := range rounds {
+		if r.Number == int8(number) {
+			current = r
+			break
+		}
+	}
+
+	if current == nil {
+		return study.ErrRoundNotFound
+	}
+
+	edited, err := ac.reader.ReadRound(ctx, gs.SpreadsheetURL, current.Number)
+	if err != nil {
+		return err
+	}
+
+	changes := study.DiffRound(*current, edited)
+
+	if len(changes) == 0 {
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("%d 라운드 시트에 반영할 변경 사항이 없습니다.", current.Number),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+
+	// only the latest request of the guild is kept
+	ac.mtx.Lock()
+	ac.syncings[i.GuildID] = pendingSync{
+		managerID: manager.ID,
+		roundID:   current.ID,
+		changes:   changes,
+	}
+	ac.mtx.Unlock()
+
+	embed := adminEmbed(s.State.User, fmt.Sprintf("%d 라운드 시트 동기화", current.Number),
+		roundChangesDescription(changes), 16777215)
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: "적용 버튼을 누르면 변경 사항이 저장됩니다."}
+
+	// send a response with confirm button
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						roundSheetSyncConfirmButton,
+					},
+				},
+			},
+		},
+	})
+}
+
+// apply changes of the round sheet
+func (ac *adminCommand) syncRoundSheetConfirm(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	ac.mtx.Lock()
+	pending, ok := ac.syncings[i.GuildID]
+	if ok && pending.managerID == manager.ID {
+		delete(ac.syncings, i.GuildID)
+	}
+	ac.mtx.Unlock()
+
+	if !ok || pending.managerID != manager.ID {
+		return errors.Join(study.ErrInvalidRoundChange, errors.New("적용할 변경 사항이 없습니다. 동기화를 다시 요청해주세요"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// apply changes
+	gs, gr, err := ac.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		RoundID:   pending.roundID,
+		Changes:   pending.changes,
+	}, service.ApplyRoundChanges,
+		service.ValidateToCheckManager, service.ValidateToApplyRoundChanges)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		description := fmt.Sprintf("%s: 시트 변경 사항 %d건 반영", gr.Title, len(pending.changes))
+		evt, err := study.NewEvent(study.EventTopicStudyRoundProgress, description)
+		if err != nil {
+			ac.sugar.Errorw("failed to create an event", "error", err, "topic", study.EventTopicStudyRoundProgress, "description", description)
+			return
+		}
+
+		// publish an event
+		go ac.publishEvent(*gs, evt)
+	}()
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("%d 라운드에 변경 사항 %d건이 반영되었습니다.", gr.Number, len(pending.changes)),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func roundChangesDescription(changes []study.RoundChange) string {
+	sb := strings.Builder{}
+
+	for n, c := range changes {
+		var line string
+
+		if c.MemberID == "" {
+			line = fmt.Sprintf("- %s: `%s` → `%s`\n", c.Field.String(), c.Old, c.New)
+		} else {
+			line = fmt.Sprintf("- <@%s> %s: `%s` → `%s`\n", c.MemberID, c.Field.String(), c.Old, c.New)
+		}
+
+		if sb.Len()+len(line) > maxSyncDescriptionLength {
+			sb.WriteString(fmt.Sprintf("외 %d건\n", len(changes)-n))
+			break
+		}
+
+		sb.WriteString(line)
+	}
+
+	return sb.String()
+}
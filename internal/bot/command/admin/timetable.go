@@ -0,0 +1,189 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+// default time of each speaker
+const defaultSlotDuration = 15 * time.Minute
+
+// options of the timetable, e.g. "순서=랜덤, 시작=2023-06-03 20:00, 시간=15"
+type timetableOptions struct {
+	order    study.TimetableOrder
+	start    time.Time
+	duration time.Duration
+}
+
+// generate timetable of registered speakers of the ongoing round
+func (ac *adminCommand) generateTimetable(s *discordgo.Session, i *discordgo.InteractionCreate, txt string) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	opts, err := parseTimetableOptions(txt, time.Local)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// get study
+	gs, err := ac.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	if gs.OngoingRoundID == "" {
+		return study.ErrRoundNotFound
+	}
+
+	gr, err := ac.svc.GetRound(ctx, gs.OngoingRoundID)
+	if err != nil {
+		return err
+	}
+
+	// presentation starts as scheduled if not given
+	if opts.start.IsZero() {
+		start, ok := gr.ScheduleOf(study.StagePresentationStarted)
+		if !ok {
+			return errors.Join(study.ErrRequiredArgs, fmt.Errorf("발표 일정이 없습니다. '시작=%s' 형식으로 시작 시간을 입력해주세요", scheduleTimeLayout))
+		}
+
+		opts.start = start
+	}
+
+	timetable := study.NewTimetable(gr.SpeakerIDs(), opts.order, opts.start, opts.duration,
+		rand.New(rand.NewSource(time.Now().UnixNano())))
+
+	_, gr, err = ac.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		RoundID:   gr.ID,
+		Timetable: timetable,
+	}, service.UpdateTimetable,
+		service.ValidateToCheckManager, service.ValidateToCheckOngoingRound, service.ValidateToUpdateTimetable)
+	if err != nil {
+		return err
+	}
+
+	embed := adminEmbed(s.State.User, fmt.Sprintf("%d회차 발표 순서 (%s)", gr.Number, opts.order), "", 16777215)
+	embed.Fields = utils.TimetableFields("발표 순서", *gr)
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// swap turns of two speakers, e.g. "1, 3"
+func (ac *adminCommand) swapTimetable(s *discordgo.Session, i *discordgo.InteractionCreate, txt string) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	a, b, ok := strings.Cut(txt, ",")
+	if !ok {
+		return errors.Join(study.ErrInvalidArgs, errors.New("바꿀 두 발표 순서를 '1, 3' 형식으로 입력해주세요"))
+	}
+
+	first, err1 := strconv.Atoi(strings.TrimSpace(a))
+	second, err2 := strconv.Atoi(strings.TrimSpace(b))
+
+	if err1 != nil || err2 != nil {
+		return errors.Join(study.ErrInvalidArgs, errors.New("바꿀 두 발표 순서를 '1, 3' 형식으로 입력해주세요"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, gr, err := ac.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		Swap:      [2]int{first, second},
+	}, service.SwapSlots,
+		service.ValidateToCheckManager, service.ValidateToCheckOngoingRound, service.ValidateToSwapSlots)
+	if err != nil {
+		return err
+	}
+
+	embed := adminEmbed(s.State.User, fmt.Sprintf("%d회차 발표 순서", gr.Number), "", 16777215)
+	embed.Fields = utils.TimetableFields("발표 순서", *gr)
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// options are separated by comma, omitted options use defaults
+func parseTimetableOptions(txt string, loc *time.Location) (timetableOptions, error) {
+	opts := timetableOptions{
+		order:    study.TimetableOrderRegistration,
+		duration: defaultSlotDuration,
+	}
+
+	for _, item := range strings.Split(txt, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return opts, errors.Join(study.ErrInvalidArgs, errors.New("'순서=랜덤, 시작=2023-06-03 20:00, 시간=15' 형식으로 입력해주세요"))
+		}
+
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "순서":
+			switch value {
+			case study.TimetableOrderRandom.String():
+				opts.order = study.TimetableOrderRandom
+			case study.TimetableOrderRegistration.String():
+				opts.order = study.TimetableOrderRegistration
+			default:
+				return opts, errors.Join(study.ErrInvalidArgs, errors.New("순서는 '랜덤' 또는 '등록순'으로 입력해주세요"))
+			}
+		case "시작":
+			t, err := time.ParseInLocation(scheduleTimeLayout, value, loc)
+			if err != nil {
+				return opts, errors.Join(study.ErrInvalidArgs, fmt.Errorf("'%s' 형식으로 시간을 입력해주세요: %s", scheduleTimeLayout, value))
+			}
+
+			opts.start = t
+		case "시간":
+			minutes, err := strconv.Atoi(value)
+			if err != nil || minutes <= 0 {
+				return opts, errors.Join(study.ErrInvalidArgs, errors.New("발표 시간은 분 단위의 양수로 입력해주세요"))
+			}
+
+			opts.duration = time.Duration(minutes) * time.Minute
+		default:
+			return opts, errors.Join(study.ErrInvalidArgs, fmt.Errorf("알 수 없는 옵션입니다: %s", strings.TrimSpace(key)))
+		}
+	}
+
+	return opts, nil
+}
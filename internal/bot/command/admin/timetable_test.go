@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestParseTimetableOptions(t *testing.T) {
+	loc := time.FixedZone("KST", 9*60*60)
+
+	tests := []struct {
+		name string
+		txt  string
+		want timetableOptions
+		ok   bool
+	}{
+		{
+			name: "defaults",
+			txt:  "",
+			want: timetableOptions{order: study.TimetableOrderRegistration, duration: defaultSlotDuration},
+			ok:   true,
+		},
+		{
+			name: "all options",
+			txt:  "순서=랜덤, 시작=2023-06-03 20:00, 시간=20",
+			want: timetableOptions{
+				order:    study.TimetableOrderRandom,
+				start:    time.Date(2023, 6, 3, 20, 0, 0, 0, loc),
+				duration: 20 * time.Minute,
+			},
+			ok: true,
+		},
+		{
+			name: "spaces and trailing comma",
+			txt:  " 순서 = 등록순 , ",
+			want: timetableOptions{order: study.TimetableOrderRegistration, duration: defaultSlotDuration},
+			ok:   true,
+		},
+		{name: "missing value", txt: "순서", ok: false},
+		{name: "unknown order", txt: "순서=이름순", ok: false},
+		{name: "invalid start", txt: "시작=내일", ok: false},
+		{name: "zero duration", txt: "시간=0", ok: false},
+		{name: "unknown option", txt: "장소=온라인", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimetableOptions(tt.txt, loc)
+
+			if !tt.ok {
+				if !errors.Is(err, study.ErrInvalidArgs) {
+					t.Fatalf("expected ErrInvalidArgs, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.order != tt.want.order || !got.start.Equal(tt.want.start) || got.duration != tt.want.duration {
+				t.Fatalf("options = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
 )
 
+var minNumberOption float64 = 0
+
 var (
 	adminCmd = discordgo.ApplicationCommand{
 		Name:        "매니저",
@@ -52,10 +55,58 @@ var (
 						Name:  "회고 채널 설정",
 						Value: "set-reflection-channel",
 					},
+					{
+						Name:  "발표 음성 채널 설정",
+						Value: "set-presentation-channel",
+					},
 					{
 						Name:  "스프레드시트 설정",
 						Value: "set-spreadsheet",
 					},
+					{
+						Name:  "라운드 시트 동기화",
+						Value: "sync-round-sheet",
+					},
+					{
+						Name:  "라운드 일정 설정",
+						Value: "set-schedule",
+					},
+					{
+						Name:  "발표 순서 생성",
+						Value: "generate-timetable",
+					},
+					{
+						Name:  "발표 순서 변경",
+						Value: "swap-timetable",
+					},
+					{
+						Name:  "발표 자료 제출 기한 연장",
+						Value: "extend-submission",
+					},
+					{
+						Name:  "발표 자료 허용 도메인 설정",
+						Value: "set-material-hosts",
+					},
+					{
+						Name:  "주제 투표 시작",
+						Value: "open-topic-poll",
+					},
+					{
+						Name:  "주제 투표 마감",
+						Value: "close-topic-poll",
+					},
+					{
+						Name:  "웹훅 등록",
+						Value: "register-webhook",
+					},
+					{
+						Name:  "웹훅 삭제",
+						Value: "remove-webhook",
+					},
+					{
+						Name:  "웹훅 테스트",
+						Value: "test-webhook",
+					},
 				},
 				Required: true,
 			},
@@ -74,6 +125,31 @@ var (
 				Description: "채널을 선택해주세요.",
 				Type:        discordgo.ApplicationCommandOptionChannel,
 			},
+			{
+				Name:        "숫자",
+				Description: "숫자를 입력해주세요. 라운드 생성 시 발표자 정원, 제출 기한 연장 시 연장할 시간으로 사용됩니다.",
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				MinValue:    &minNumberOption,
+			},
+			{
+				Name:        "토픽",
+				Description: "웹훅으로 받을 이벤트를 선택해주세요. 선택하지 않으면 모든 이벤트를 받습니다.",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "라운드 생성",
+						Value: study.EventTopicStudyRoundCreated.String(),
+					},
+					{
+						Name:  "라운드 진행",
+						Value: study.EventTopicStudyRoundProgress.String(),
+					},
+					{
+						Name:  "라운드 종료",
+						Value: study.EventTopicStudyRoundFinished.String(),
+					},
+				},
+			},
 		},
 	}
 	noticeTextInput = discordgo.TextInput{
@@ -90,6 +166,11 @@ var (
 		Label:    "확인",
 		Style:    discordgo.SuccessButton,
 	}
+	roundSheetSyncConfirmButton = discordgo.Button{
+		CustomID: "confirm-sync-round-sheet",
+		Label:    "적용",
+		Style:    discordgo.SuccessButton,
+	}
 )
 
 const noticeModalCustomID = "notice"
@@ -112,3 +193,26 @@ func adminEmbed(u *discordgo.User, title, description string, color ...int) *dis
 
 	return embed
 }
+
+var (
+	topicPollProposeButton = discordgo.Button{
+		CustomID: "topic-poll-propose",
+		Label:    "주제 제안",
+		Style:    discordgo.PrimaryButton,
+	}
+	topicPollVoteSelectMenu = discordgo.SelectMenu{
+		CustomID:    "topic-poll-vote",
+		Placeholder: "투표할 주제를 선택해주세요.",
+	}
+	topicTitleTextInput = discordgo.TextInput{
+		CustomID:    "topic-title",
+		Label:       "주제",
+		Style:       discordgo.TextInputShort,
+		Placeholder: "다음 라운드에서 다룰 주제를 입력해주세요.",
+		Required:    true,
+		MaxLength:   100,
+		MinLength:   1,
+	}
+)
+
+const topicPollModalCustomID = "topic-poll-propose-modal"
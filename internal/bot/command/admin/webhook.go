@@ -0,0 +1,197 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/event"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+// register webhook receiving events of the topic, all topics if empty
+func (ac *adminCommand) registerWebhook(s *discordgo.Session, i *discordgo.InteractionCreate, rawURL, topic string) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	if rawURL == "" {
+		return errors.Join(study.ErrRequiredArgs, errors.New("웹훅 URL을 입력해주세요"))
+	}
+
+	var topics []study.EventTopic
+
+	if topic != "" {
+		topics = append(topics, study.EventTopic(topic))
+	}
+
+	wh, err := study.NewWebhook(strings.TrimSpace(rawURL), topics...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = ac.svc.UpdateStudy(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		Webhook:   wh,
+	}, service.AddWebhook, service.ValidateToCheckManager, service.ValidateToAddWebhook)
+	if err != nil {
+		return err
+	}
+
+	embed := adminEmbed(s.State.User, "웹훅 등록", "웹훅이 등록되었습니다. 시크릿은 요청 서명을 검증하는 데 사용되니 안전하게 보관해주세요.")
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "ID", Value: wh.ID, Inline: true},
+		{Name: "토픽", Value: webhookTopics(wh), Inline: true},
+		{Name: "URL", Value: wh.URL},
+		{Name: "시크릿", Value: fmt.Sprintf("||%s||", wh.Secret)},
+		{Name: "서명", Value: fmt.Sprintf("`%s: sha256=<HMAC-SHA256(시크릿, 본문)>`", event.WebhookSignatureHeader)},
+	}
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// remove webhook by id
+func (ac *adminCommand) removeWebhook(s *discordgo.Session, i *discordgo.InteractionCreate, id string) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return errors.Join(study.ErrRequiredArgs, errors.New("삭제할 웹훅 ID를 입력해주세요"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := ac.svc.UpdateStudy(ctx, &service.UpdateParams{
+		GuildID:   i.GuildID,
+		ManagerID: manager.ID,
+		Webhook:   study.Webhook{ID: id},
+	}, service.RemoveWebhook, service.ValidateToCheckManager, service.ValidateToRemoveWebhook)
+	if err != nil {
+		return err
+	}
+
+	// send a response message
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("웹훅 %s이(가) 삭제되었습니다.", id),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// send ping to the webhook, all webhooks of the study if id is empty
+func (ac *adminCommand) testWebhook(s *discordgo.Session, i *discordgo.InteractionCreate, id string) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// get study
+	gs, err := ac.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	// check manager
+	if !gs.IsManager(manager.ID) {
+		return study.ErrNotManager
+	}
+
+	webhooks := gs.Webhooks
+
+	if id = strings.TrimSpace(id); id != "" {
+		wh, ok := gs.GetWebhook(id)
+		if !ok {
+			return study.ErrWebhookNotFound
+		}
+
+		webhooks = []study.Webhook{wh}
+	}
+
+	if len(webhooks) == 0 {
+		return errors.Join(study.ErrWebhookNotFound, errors.New("등록된 웹훅이 없습니다"))
+	}
+
+	// endpoints may respond slowly
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	embed := adminEmbed(s.State.User, "웹훅 테스트", fmt.Sprintf("`%s` 이벤트를 전송했습니다.", event.WebhookPingTopic))
+
+	for _, wh := range webhooks {
+		pingCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		d, err := ac.webhooks.Ping(pingCtx, gs.GuildID, wh)
+		cancel()
+
+		var result string
+
+		switch {
+		case err != nil:
+			result = fmt.Sprintf("실패: %s", err)
+		case d.Error != "":
+			result = fmt.Sprintf("실패: %s", d.Error)
+		case !d.Succeeded():
+			result = fmt.Sprintf("실패: 상태 코드 %d", d.StatusCode)
+		default:
+			result = fmt.Sprintf("성공: 상태 코드 %d (%s)", d.StatusCode, d.Duration)
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s (%s)", wh.ID, webhookTopics(wh)),
+			Value: fmt.Sprintf("%s\n%s", wh.URL, result),
+		})
+	}
+
+	embeds := []*discordgo.MessageEmbed{embed}
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &embeds,
+	})
+	return err
+}
+
+func webhookTopics(wh study.Webhook) string {
+	if len(wh.Topics) == 0 {
+		return "전체"
+	}
+
+	topics := make([]string, 0, len(wh.Topics))
+
+	for _, t := range wh.Topics {
+		topics = append(topics, t.String())
+	}
+
+	return strings.Join(topics, ", ")
+}
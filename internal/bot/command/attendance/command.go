@@ -0,0 +1,296 @@
+package attendance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/bot/command"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/attendance"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+	"go.uber.org/zap"
+)
+
+// AttendanceCommand tracks speakers in the presentation channel and confirms their attendance
+type AttendanceCommand interface {
+	command.Command
+
+	// start tracking when the presentation is started
+	StartAttendance(s *discordgo.Session, gs study.Study, gr study.Round)
+	// review tracked time when the presentation is finished, i is the interaction of the manager
+	ReviewAttendance(s *discordgo.Session, i *discordgo.InteractionCreate, gs study.Study, gr study.Round)
+	// handler of voice state updates
+	VoiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpdate)
+}
+
+// speakers waiting for confirmation of manager
+type pendingReview struct {
+	managerID string
+	roundID   string
+	memberIDs []string
+}
+
+type attendanceCommand struct {
+	svc     service.Service
+	tracker attendance.Tracker
+
+	threshold time.Duration
+	autoApply bool
+
+	mtx     *sync.Mutex
+	pending map[string]pendingReview // guild id to speakers to be confirmed
+
+	sugar *zap.SugaredLogger
+}
+
+type AttendanceOptsFunc func(*attendanceCommand)
+
+// time to be spent in the presentation channel to be confirmed
+func WithThreshold(d time.Duration) AttendanceOptsFunc {
+	return func(ac *attendanceCommand) {
+		if d > 0 {
+			ac.threshold = d
+		}
+	}
+}
+
+// confirm attendance without review of manager
+func WithAutoApply(autoApply bool) AttendanceOptsFunc {
+	return func(ac *attendanceCommand) {
+		ac.autoApply = autoApply
+	}
+}
+
+func NewAttendanceCommand(svc service.Service, tracker attendance.Tracker, sugar *zap.SugaredLogger, opts ...AttendanceOptsFunc) AttendanceCommand {
+	ac := &attendanceCommand{
+		svc:       svc,
+		tracker:   tracker,
+		threshold: DefaultThreshold,
+		mtx:       &sync.Mutex{},
+		pending:   map[string]pendingReview{},
+		sugar:     sugar,
+	}
+
+	for _, opt := range opts {
+		opt(ac)
+	}
+
+	return ac
+}
+
+func (ac *attendanceCommand) Register(reg command.Registerer) {
+	reg.RegisterHandler(approveButton.CustomID, ac.approveAttendance)
+}
+
+func (ac *attendanceCommand) StartAttendance(s *discordgo.Session, gs study.Study, gr study.Round) {
+	if gs.PresentationChannelID == "" {
+		return
+	}
+
+	ac.tracker.Start(gs.GuildID, gr.ID)
+	ac.joinConnected(s, gs, gr)
+}
+
+// members already in the presentation channel are joined
+func (ac *attendanceCommand) joinConnected(s *discordgo.Session, gs study.Study, gr study.Round) {
+	guild, err := s.State.Guild(gs.GuildID)
+	if err != nil {
+		ac.sugar.Errorw("failed to get guild from state", "error", err, "guild_id", gs.GuildID)
+		return
+	}
+
+	now := time.Now()
+
+	for _, vs := range guild.VoiceStates {
+		if vs.ChannelID == gs.PresentationChannelID {
+			ac.tracker.Join(gs.GuildID, gr.ID, vs.UserID, now)
+		}
+	}
+}
+
+func (ac *attendanceCommand) VoiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpdate) {
+	if v.GuildID == "" || (v.Member != nil && v.Member.User != nil && v.Member.User.Bot) {
+		return
+	}
+
+	// mute, deafen or streaming does not change the channel
+	if v.BeforeUpdate != nil && v.BeforeUpdate.ChannelID == v.ChannelID {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gs, err := ac.svc.GetStudy(ctx, v.GuildID)
+	if err != nil {
+		if !errors.Is(err, study.ErrStudyNotFound) {
+			ac.sugar.Errorw("failed to get study", "error", err, "guild_id", v.GuildID)
+		}
+		return
+	}
+
+	// only time spent during the presentation is tracked
+	if !gs.CurrentStage.IsPresentationStarted() || gs.PresentationChannelID == "" || gs.OngoingRoundID == "" {
+		return
+	}
+
+	gr := study.Round{ID: gs.OngoingRoundID}
+
+	// tracking is lost when the bot is restarted, only one of concurrent updates starts it again
+	if ac.tracker.StartIfNotTracking(gs.GuildID, gr.ID) {
+		ac.joinConnected(s, *gs, gr)
+	}
+
+	if v.ChannelID == gs.PresentationChannelID {
+		ac.tracker.Join(gs.GuildID, gr.ID, v.UserID, time.Now())
+		return
+	}
+
+	ac.tracker.Leave(gs.GuildID, gr.ID, v.UserID, time.Now())
+}
+
+func (ac *attendanceCommand) ReviewAttendance(s *discordgo.Session, i *discordgo.InteractionCreate, gs study.Study, gr study.Round) {
+	results := attendance.Review(gr, ac.tracker.Finish(gs.GuildID, gr.ID, time.Now()), ac.threshold)
+	if len(results) == 0 || gs.PresentationChannelID == "" {
+		return
+	}
+
+	proposed := attendance.Proposed(results)
+
+	params := &discordgo.WebhookParams{
+		Flags: discordgo.MessageFlagsEphemeral,
+	}
+
+	switch {
+	case len(proposed) == 0:
+		params.Embeds = []*discordgo.MessageEmbed{
+			attendanceEmbed(s.State.User, "발표 출석 확인", reviewDescription(results, ac.threshold)),
+		}
+	case ac.autoApply:
+		confirmed, err := ac.confirm(gs.GuildID, gs.ManagerID, gr.ID, proposed)
+		if err != nil {
+			ac.sugar.Errorw("failed to confirm attendance", "error", err, "guild_id", gs.GuildID, "round_id", gr.ID)
+		}
+
+		for n, r := range results {
+			if confirmed[r.MemberID] {
+				results[n].Attended = true
+			}
+		}
+
+		params.Embeds = []*discordgo.MessageEmbed{
+			attendanceEmbed(s.State.User, "발표 출석 자동 확정", reviewDescription(results, ac.threshold)),
+		}
+	default:
+		// only the latest review of the guild is kept
+		ac.mtx.Lock()
+		ac.pending[gs.GuildID] = pendingReview{
+			managerID: gs.ManagerID,
+			roundID:   gr.ID,
+			memberIDs: proposed,
+		}
+		ac.mtx.Unlock()
+
+		embed := attendanceEmbed(s.State.User, "발표 출석 검토", reviewDescription(results, ac.threshold))
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("출석 확정 버튼을 누르면 발표자 %d명의 출석이 확정됩니다.", len(proposed))}
+
+		params.Embeds = []*discordgo.MessageEmbed{embed}
+		params.Components = []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{approveButton},
+			},
+		}
+	}
+
+	// review is shown only to the manager who finished the presentation
+	if _, err := s.FollowupMessageCreate(i.Interaction, true, params); err != nil {
+		ac.sugar.Errorw("failed to send attendance review", "error", err, "guild_id", gs.GuildID, "round_id", gr.ID)
+	}
+}
+
+// confirm attendance of speakers proposed by the review
+func (ac *attendanceCommand) approveAttendance(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	ac.mtx.Lock()
+	pending, ok := ac.pending[i.GuildID]
+	if ok && pending.managerID == manager.ID {
+		delete(ac.pending, i.GuildID)
+	}
+	ac.mtx.Unlock()
+
+	if !ok || pending.managerID != manager.ID {
+		return errors.Join(study.ErrInvalidArgs, errors.New("확정할 출석 정보가 없습니다"))
+	}
+
+	confirmed, err := ac.confirm(i.GuildID, manager.ID, pending.roundID, pending.memberIDs)
+	if err != nil {
+		return err
+	}
+
+	// remove the button from the review
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    fmt.Sprintf("발표자 %d명의 출석이 확정되었습니다.", len(confirmed)),
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// check attendance of each member, confirmed members are returned even if it fails
+func (ac *attendanceCommand) confirm(guildID, managerID, roundID string, memberIDs []string) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	confirmed := map[string]bool{}
+
+	for _, id := range memberIDs {
+		_, _, err := ac.svc.UpdateRound(ctx, &service.UpdateParams{
+			GuildID:   guildID,
+			ManagerID: managerID,
+			MemberID:  id,
+			RoundID:   roundID,
+		}, service.CheckSpeakerAttendance,
+			service.ValidateToCheckManager, service.ValidateToCheckAttendance)
+		if err != nil {
+			return confirmed, err
+		}
+
+		confirmed[id] = true
+	}
+
+	return confirmed, nil
+}
+
+func reviewDescription(results []attendance.Result, threshold time.Duration) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "발표 채널에 %s 이상 참여한 발표자의 출석을 확정합니다.\n\n", threshold)
+
+	for _, r := range results {
+		var mark string
+
+		switch {
+		case r.Attended:
+			mark = "✅"
+		case r.Qualified:
+			mark = "☑️"
+		default:
+			mark = "❌"
+		}
+
+		fmt.Fprintf(b, "%s <@%s> (%s): %s\n", mark, r.MemberID, r.Name, r.Spent.Truncate(time.Second))
+	}
+
+	return b.String()
+}
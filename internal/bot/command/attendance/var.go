@@ -0,0 +1,29 @@
+package attendance
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// default time to be spent in the presentation channel to be confirmed
+const DefaultThreshold = 10 * time.Minute
+
+var approveButton = discordgo.Button{
+	CustomID: "approve-voice-attendance",
+	Label:    "출석 확정",
+	Style:    discordgo.SuccessButton,
+}
+
+func attendanceEmbed(u *discordgo.User, title, description string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    u.Username,
+			IconURL: u.AvatarURL(""),
+		},
+		Title:       title,
+		Description: description,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Color:       16777215,
+	}
+}
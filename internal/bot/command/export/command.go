@@ -0,0 +1,113 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/bot/command"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/event"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+type exportCommand struct {
+	svc service.Service
+}
+
+func NewExportCommand(svc service.Service) command.Command {
+	return &exportCommand{
+		svc: svc,
+	}
+}
+
+func (ec *exportCommand) Register(reg command.Registerer) {
+	reg.RegisterCommand(cmd, ec.exportRounds)
+}
+
+// export rounds as a file attachment
+func (ec *exportCommand) exportRounds(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	user := utils.GetGuildUserFromInteraction(i)
+	if user == nil {
+		return study.ErrUserNotFound
+	}
+
+	var format event.ExportFormat
+	var number int64
+
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "형식":
+			format = event.ExportFormat(option.StringValue())
+		case "라운드":
+			number = option.IntValue()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// get the study
+	gs, err := ec.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	// only manager can export all rounds
+	if number == 0 && !gs.IsManager(user.ID) {
+		return study.ErrNotManager
+	}
+
+	rounds, err := ec.svc.GetRounds(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	selected := make([]study.Round, 0, len(rounds))
+
+	for _, r := range rounds {
+		if number == 0 || int64(r.Number) == number {
+			selected = append(selected, *r)
+		}
+	}
+
+	if len(selected) == 0 {
+		return study.ErrRoundNotFound
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Number < selected[j].Number })
+
+	buf := &bytes.Buffer{}
+
+	if err := event.ExportRounds(buf, format, selected...); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("rounds.%s", format)
+	content := fmt.Sprintf("전체 라운드(%d개)를 내보냈습니다.", len(selected))
+
+	if number != 0 {
+		name = fmt.Sprintf("round-%03d.%s", number, format)
+		content = fmt.Sprintf("%d 라운드를 내보냈습니다.", number)
+	}
+
+	// send response with the file
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Files: []*discordgo.File{
+				{
+					Name:        name,
+					ContentType: format.ContentType(),
+					Reader:      buf,
+				},
+			},
+		},
+	})
+}
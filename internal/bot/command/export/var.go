@@ -0,0 +1,40 @@
+package export
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study/event"
+)
+
+var cmd = discordgo.ApplicationCommand{
+	Name:        "내보내기",
+	Description: "스터디 라운드 정보를 파일로 내보냅니다. 전체 라운드는 매니저만 내보낼 수 있습니다.",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "형식",
+			Description: "파일 형식을 선택해주세요.",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{
+					Name:  "CSV",
+					Value: string(event.ExportFormatCSV),
+				},
+				{
+					Name:  "JSON",
+					Value: string(event.ExportFormatJSON),
+				},
+				{
+					Name:  "Markdown",
+					Value: string(event.ExportFormatMarkdown),
+				},
+			},
+			Required: true,
+		},
+		{
+			Name:        "라운드",
+			Description: "내보낼 라운드 번호를 입력해주세요. 입력하지 않으면 전체 라운드를 내보냅니다.",
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			MinValue:    func() *float64 { v := 1.0; return &v }(),
+			MaxValue:    127,
+		},
+	},
+}
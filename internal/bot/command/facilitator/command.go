@@ -0,0 +1,355 @@
+package facilitator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/bot/command"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/facilitator"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+	"go.uber.org/zap"
+)
+
+// presentation being led in the channel of the panel
+type live struct {
+	sess      *facilitator.Session
+	title     string
+	managerID string
+	channelID string
+
+	gen    int // increased whenever timers are reset, stale timers are ignored
+	timers []*time.Timer
+}
+
+func (l *live) stopTimers() {
+	for _, t := range l.timers {
+		t.Stop()
+	}
+	l.timers = nil
+	l.gen++
+}
+
+type facilitatorCommand struct {
+	svc service.Service
+
+	mtx   *sync.Mutex
+	lives map[string]*live // guild id to presentation
+
+	sugar *zap.SugaredLogger
+}
+
+func NewFacilitatorCommand(svc service.Service, sugar *zap.SugaredLogger) command.Command {
+	return &facilitatorCommand{
+		svc:   svc,
+		mtx:   &sync.Mutex{},
+		lives: map[string]*live{},
+		sugar: sugar,
+	}
+}
+
+func (fc *facilitatorCommand) Register(reg command.Registerer) {
+	reg.RegisterCommand(cmd, fc.openPanel)
+	reg.RegisterHandler(nextButton.CustomID, fc.nextSpeaker)
+	reg.RegisterHandler(extendButton.CustomID, fc.extendSpeaker)
+	reg.RegisterHandler(skipButton.CustomID, fc.skipSpeaker)
+	reg.RegisterHandler(endButton.CustomID, fc.endPresentation)
+}
+
+// open the panel in the channel, previous panel of the guild is replaced
+func (fc *facilitatorCommand) openPanel(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gs, err := fc.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	// check manager
+	if !gs.IsManager(manager.ID) {
+		return study.ErrNotManager
+	}
+
+	if !gs.CurrentStage.IsPresentationStarted() {
+		return errors.Join(study.ErrInvalidStage, errors.New("발표 단계에서만 진행 패널을 열 수 있습니다"))
+	}
+
+	gr, err := fc.svc.GetRound(ctx, gs.OngoingRoundID)
+	if err != nil {
+		return err
+	}
+
+	sess := facilitator.NewSession(*gr)
+	if len(sess.Turns) == 0 {
+		return errors.Join(study.ErrMemberNotRegistered, errors.New("등록된 발표자가 없습니다"))
+	}
+
+	fc.mtx.Lock()
+	if prev, ok := fc.lives[i.GuildID]; ok {
+		prev.stopTimers()
+	}
+	l := &live{
+		sess:      sess,
+		title:     fmt.Sprintf("%d회차 발표 진행", gr.Number),
+		managerID: manager.ID,
+		channelID: i.ChannelID,
+	}
+	fc.lives[i.GuildID] = l
+	embed := panelEmbed(s.State.User, l.title, panelDescription(sess))
+	fc.mtx.Unlock()
+
+	// send the panel to the channel
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: panelComponents(),
+		},
+	})
+}
+
+// action of the panel returns the finished turn if exists
+type panelAction func(*facilitator.Session) (facilitator.Turn, bool, error)
+
+func (fc *facilitatorCommand) nextSpeaker(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return fc.control(s, i, func(sess *facilitator.Session) (facilitator.Turn, bool, error) {
+		finished, ok := sess.Next(time.Now())
+		return finished, ok, nil
+	})
+}
+
+func (fc *facilitatorCommand) skipSpeaker(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return fc.control(s, i, func(sess *facilitator.Session) (facilitator.Turn, bool, error) {
+		skipped, ok := sess.Skip(time.Now())
+		return skipped, ok, nil
+	})
+}
+
+func (fc *facilitatorCommand) extendSpeaker(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return fc.control(s, i, func(sess *facilitator.Session) (facilitator.Turn, bool, error) {
+		if !sess.Extend(extendDuration) {
+			return facilitator.Turn{}, false, errors.Join(study.ErrInvalidArgs, errors.New("발표 중인 발표자가 없습니다"))
+		}
+		return facilitator.Turn{}, false, nil
+	})
+}
+
+func (fc *facilitatorCommand) endPresentation(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return fc.control(s, i, func(sess *facilitator.Session) (facilitator.Turn, bool, error) {
+		finished, ok := sess.End()
+		return finished, ok, nil
+	})
+}
+
+// apply the action to the presentation of the guild and update the panel
+func (fc *facilitatorCommand) control(s *discordgo.Session, i *discordgo.InteractionCreate, action panelAction) error {
+	manager := utils.GetGuildUserFromInteraction(i)
+	if manager == nil {
+		return study.ErrManagerNotFound
+	}
+
+	fc.mtx.Lock()
+
+	l, ok := fc.lives[i.GuildID]
+	if !ok || l.managerID != manager.ID {
+		fc.mtx.Unlock()
+		return errors.Join(study.ErrNotManager, errors.New("진행 중인 발표가 없습니다. /진행 명령어로 패널을 열어주세요"))
+	}
+
+	prev, wasSpeaking := l.sess.Current()
+
+	finished, ok, err := action(l.sess)
+	if err != nil {
+		fc.mtx.Unlock()
+		return err
+	}
+
+	current, speaking := l.sess.Current()
+
+	// timers are reset when the speaker or the budget is changed
+	if wasSpeaking != speaking || prev.MemberID != current.MemberID || prev.Budget != current.Budget {
+		fc.resetTimers(s, i.GuildID, l)
+	}
+
+	done := l.sess.Done()
+	if done {
+		l.stopTimers()
+		delete(fc.lives, i.GuildID)
+	}
+
+	roundID := l.sess.RoundID
+	embed := panelEmbed(s.State.User, l.title, panelDescription(l.sess))
+
+	fc.mtx.Unlock()
+
+	// attendance is confirmed when the turn is completed, the result is reported after the panel is updated
+	var confirmed chan error
+
+	if ok && finished.State == facilitator.TurnCompleted {
+		confirmed = make(chan error, 1)
+
+		go func() {
+			confirmed <- fc.confirmAttendance(i.GuildID, manager.ID, roundID, finished.MemberID)
+		}()
+	}
+
+	// announce the new speaker
+	if speaking && (!wasSpeaking || prev.MemberID != current.MemberID) {
+		msg := fmt.Sprintf("🎤 <@%s>님의 발표를 시작합니다. 발표 시간은 %d분입니다.", current.MemberID, int(current.Budget.Minutes()))
+		if _, err := s.ChannelMessageSend(i.ChannelID, msg); err != nil {
+			fc.sugar.Errorw("failed to announce speaker", "error", err, "guild_id", i.GuildID, "member_id", current.MemberID)
+		}
+	}
+
+	components := panelComponents()
+	if done {
+		components = []discordgo.MessageComponent{}
+		embed.Title = strings.Replace(l.title, "진행", "종료", 1)
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+
+	if confirmed != nil {
+		go fc.reportAttendance(s, i, finished.MemberID, confirmed)
+	}
+
+	return err
+}
+
+// warn the speaker before and when the time is up, mutex should be held
+func (fc *facilitatorCommand) resetTimers(s *discordgo.Session, guildID string, l *live) {
+	l.stopTimers()
+
+	turn, ok := l.sess.Current()
+	if !ok {
+		return
+	}
+
+	gen := l.gen
+	deadline := turn.Deadline()
+
+	if time.Until(deadline) > warningBefore {
+		l.timers = append(l.timers, time.AfterFunc(time.Until(deadline.Add(-warningBefore)), func() {
+			fc.warn(s, guildID, gen, fmt.Sprintf("⏰ <@%s>님, 발표 시간이 %d분 남았습니다.", turn.MemberID, int(warningBefore.Minutes())))
+		}))
+	}
+
+	l.timers = append(l.timers, time.AfterFunc(time.Until(deadline), func() {
+		fc.warn(s, guildID, gen, fmt.Sprintf("⌛ <@%s>님, 발표 시간이 종료되었습니다. 발표를 마무리해주세요.", turn.MemberID))
+	}))
+}
+
+func (fc *facilitatorCommand) warn(s *discordgo.Session, guildID string, gen int, msg string) {
+	fc.mtx.Lock()
+	l, ok := fc.lives[guildID]
+	stale := !ok || l.gen != gen
+	fc.mtx.Unlock()
+
+	if stale {
+		return
+	}
+
+	if _, err := s.ChannelMessageSend(l.channelID, msg); err != nil {
+		fc.sugar.Errorw("failed to warn speaker", "error", err, "guild_id", guildID)
+	}
+}
+
+func (fc *facilitatorCommand) confirmAttendance(guildID, managerID, roundID, memberID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _, err := fc.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID:   guildID,
+		ManagerID: managerID,
+		MemberID:  memberID,
+		RoundID:   roundID,
+	}, service.CheckSpeakerAttendance,
+		service.ValidateToCheckManager, service.ValidateToCheckAttendance)
+	if err != nil {
+		fc.sugar.Errorw("failed to confirm attendance", "error", err, "guild_id", guildID, "member_id", memberID)
+		return err
+	}
+
+	fc.sugar.Infow("attendance confirmed by facilitator", "guild_id", guildID, "member_id", memberID)
+	return nil
+}
+
+// let the manager know that the attendance is not recorded
+func (fc *facilitatorCommand) reportAttendance(s *discordgo.Session, i *discordgo.InteractionCreate, memberID string, confirmed <-chan error) {
+	err := <-confirmed
+	if err == nil {
+		return
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Content: fmt.Sprintf("⚠️ <@%s>님의 발표 참여를 기록하지 못했습니다: %s\n'/매니저 발표자 참여 확정' 명령어로 다시 기록해주세요.", memberID, err.Error()),
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		fc.sugar.Errorw("failed to report attendance failure", "error", err, "guild_id", i.GuildID, "member_id", memberID)
+	}
+}
+
+func panelComponents() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{nextButton, extendButton, skipButton, endButton},
+		},
+	}
+}
+
+// turns of the presentation with the current speaker, turns beyond the length limit of discord are summarized
+func panelDescription(sess *facilitator.Session) string {
+	b := &strings.Builder{}
+
+	if turn, ok := sess.Current(); ok {
+		fmt.Fprintf(b, "**현재 발표자**: <@%s> (종료 <t:%d:R>)\n\n", turn.MemberID, turn.Deadline().Unix())
+	} else if !sess.Done() {
+		b.WriteString("다음 발표자 버튼을 눌러 발표를 시작하세요.\n\n")
+	}
+
+	for n, turn := range sess.Turns {
+		var mark string
+
+		switch turn.State {
+		case facilitator.TurnSpeaking:
+			mark = "🎤"
+		case facilitator.TurnCompleted:
+			mark = "✅"
+		case facilitator.TurnSkipped:
+			mark = "⏭️"
+		default:
+			mark = "⏳"
+		}
+
+		line := fmt.Sprintf("%s %d. <@%s> (%d분)\n", mark, n+1, turn.MemberID, int(turn.Budget.Minutes()))
+
+		if utf8.RuneCountInString(b.String())+utf8.RuneCountInString(line) > maxPanelDescriptionLength {
+			fmt.Fprintf(b, "… 외 %d명", len(sess.Turns)-n)
+			break
+		}
+
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
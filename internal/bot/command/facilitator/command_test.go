@@ -0,0 +1,51 @@
+package facilitator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/facilitator"
+)
+
+func TestPanelDescriptionLength(t *testing.T) {
+	tests := []struct {
+		speakers  int
+		truncated bool
+	}{
+		{3, false},
+		{300, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d speakers", tt.speakers), func(t *testing.T) {
+			r := study.NewRound()
+
+			for n := 0; n < tt.speakers; n++ {
+				r.Timetable = append(r.Timetable, study.Slot{
+					MemberID: fmt.Sprintf("%019d", n),
+					Order:    n + 1,
+					Duration: 10 * time.Minute,
+				})
+			}
+
+			desc := panelDescription(facilitator.NewSession(r))
+
+			if utf8.RuneCountInString(desc) > 4096 {
+				t.Fatalf("description exceeds the embed limit: %d", utf8.RuneCountInString(desc))
+			}
+
+			if strings.Contains(desc, "… 외") != tt.truncated {
+				t.Fatalf("unexpected summary of turns:\n%s", desc)
+			}
+
+			// first speaker is always shown
+			if !strings.Contains(desc, fmt.Sprintf("<@%019d>", 0)) {
+				t.Fatalf("first speaker is missing:\n%s", desc)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package facilitator
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// time given by the extend button
+	extendDuration = 5 * time.Minute
+	// speaker is warned before the time is up
+	warningBefore = 2 * time.Minute
+	// description of the panel is kept under the embed limit of discord, 4096 characters
+	maxPanelDescriptionLength = 4000
+)
+
+var (
+	cmd = discordgo.ApplicationCommand{
+		Name:        "진행",
+		Description: "발표 진행 패널을 엽니다. 매니저만 사용할 수 있습니다.",
+	}
+	nextButton = discordgo.Button{
+		CustomID: "facilitator-next",
+		Label:    "다음 발표자",
+		Style:    discordgo.PrimaryButton,
+	}
+	extendButton = discordgo.Button{
+		CustomID: "facilitator-extend",
+		Label:    "5분 연장",
+		Style:    discordgo.SecondaryButton,
+	}
+	skipButton = discordgo.Button{
+		CustomID: "facilitator-skip",
+		Label:    "건너뛰기",
+		Style:    discordgo.SecondaryButton,
+	}
+	endButton = discordgo.Button{
+		CustomID: "facilitator-end",
+		Label:    "발표 종료",
+		Style:    discordgo.DangerButton,
+	}
+)
+
+func panelEmbed(u *discordgo.User, title, description string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    u.Username,
+			IconURL: u.AvatarURL(""),
+		},
+		Title:       title,
+		Description: description,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Color:       16777215,
+	}
+}
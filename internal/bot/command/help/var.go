@@ -108,7 +108,7 @@ func HelpStudyEmbed(u *discordgo.User) *discordgo.MessageEmbed {
 			},
 			{
 				Name:  "발표-자료-제출",
-				Value: "발표 자료 제출",
+				Value: "발표 자료 링크 또는 파일 제출 (여러 개 제출 가능)",
 			},
 			{
 				Name:  "피드백",
@@ -118,6 +118,18 @@ func HelpStudyEmbed(u *discordgo.User) *discordgo.MessageEmbed {
 				Name:  "발표회고",
 				Value: "발표회고 작성",
 			},
+			{
+				Name:  "일정",
+				Value: "라운드 일정 확인 및 캘린더 구독 링크 받기",
+			},
+			{
+				Name:  "내보내기",
+				Value: "라운드 정보를 CSV, JSON, Markdown 파일로 내보내기 (전체 라운드는 매니저 전용)",
+			},
+			{
+				Name:  "진행",
+				Value: "발표 진행 패널 열기 (매니저 전용)",
+			},
 		},
 	}
 }
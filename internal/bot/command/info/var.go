@@ -2,10 +2,12 @@ package info
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
 )
 
 var (
@@ -71,7 +73,7 @@ func studyInfoEmbed(u *discordgo.User, s *study.Study) *discordgo.MessageEmbed {
 }
 
 func studyRoundInfoEmbed(u *discordgo.User, r *study.Round) *discordgo.MessageEmbed {
-	return &discordgo.MessageEmbed{
+	embed := &discordgo.MessageEmbed{
 		Author: &discordgo.MessageEmbedAuthor{
 			Name:    u.Username,
 			IconURL: u.AvatarURL(""),
@@ -104,9 +106,19 @@ func studyRoundInfoEmbed(u *discordgo.User, r *study.Round) *discordgo.MessageEm
 					return r.ContentURL
 				}()),
 			},
+			{
+				Name:   "발표자 정원",
+				Value:  fmt.Sprintf("```%s```", speakerCapacity(r)),
+				Inline: true,
+			},
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
+
+	// timetable may take more than one field
+	embed.Fields = append(embed.Fields, utils.TimetableFields("발표 순서", *r)...)
+
+	return embed
 }
 
 func speakerInfoEmbed(u *discordgo.User, m study.Member) *discordgo.MessageEmbed {
@@ -161,7 +173,31 @@ func speakerInfoEmbed(u *discordgo.User, m study.Member) *discordgo.MessageEmbed
 					if m.ContentURL == "" {
 						return "```미등록```"
 					}
-					return fmt.Sprintf("```%s```", m.ContentURL)
+					if len(m.Materials) == 0 {
+						return fmt.Sprintf("```%s```", m.ContentURL)
+					}
+
+					lines := make([]string, 0, len(m.Materials))
+
+					for _, mat := range m.Materials {
+						line := mat.URL
+						if mat.IsAttachment() {
+							line = fmt.Sprintf("[%s](%s)", mat.Filename, mat.URL)
+						}
+						if !mat.SubmittedAt.IsZero() {
+							line += fmt.Sprintf(" (<t:%d:f>)", mat.SubmittedAt.Unix())
+						}
+						if mat.Late {
+							line += " ⚠️ 지각 제출"
+						}
+						lines = append(lines, line)
+					}
+
+					if !m.ExtendedUntil.IsZero() {
+						lines = append(lines, fmt.Sprintf("제출 기한 연장: <t:%d:f>", m.ExtendedUntil.Unix()))
+					}
+
+					return strings.Join(lines, "\n")
 				}(),
 			},
 		},
@@ -177,3 +213,12 @@ func errorEmbed(msg string) *discordgo.MessageEmbed {
 		Color:       0xff0000,
 	}
 }
+
+// registered speakers with the cap and the waitlist
+func speakerCapacity(r *study.Round) string {
+	if r.MaxSpeakers == 0 {
+		return fmt.Sprintf("%d명 (제한 없음)", r.SpeakerCount())
+	}
+
+	return fmt.Sprintf("%d/%d명 (대기 %d명)", r.SpeakerCount(), r.MaxSpeakers, len(r.Waitlist))
+}
@@ -3,6 +3,7 @@ package registration
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -26,6 +27,7 @@ func (rc *registrationCmd) Register(reg command.Registerer) {
 	reg.RegisterCommand(registerCmd, rc.register)
 	reg.RegisterCommand(changeCmd, rc.showChangeModal)
 	reg.RegisterHandler(changeModalCustomID, rc.submitChangeModal)
+	reg.RegisterCommand(cancelCmd, rc.cancelRegistration)
 }
 
 // register as speaker for presentation
@@ -54,7 +56,7 @@ func (rc *registrationCmd) register(s *discordgo.Session, i *discordgo.Interacti
 	defer cancel()
 
 	// register as speaker
-	_, _, err := rc.svc.UpdateRound(ctx, &service.UpdateParams{
+	_, gr, err := rc.svc.UpdateRound(ctx, &service.UpdateParams{
 		GuildID:    i.GuildID,
 		MemberID:   user.ID,
 		MemberName: name,
@@ -65,15 +67,21 @@ func (rc *registrationCmd) register(s *discordgo.Session, i *discordgo.Interacti
 		return err
 	}
 
+	embed := registrationEmbed(s.State.User, "등록 완료", "발표자 등록이 완료되었습니다.")
+
+	// round is full
+	if pos := gr.WaitlistPosition(user.ID); pos > 0 {
+		embed = registrationEmbed(s.State.User, "대기 등록 완료",
+			fmt.Sprintf("발표자 정원(%d명)이 가득 차 대기자로 등록되었습니다. 현재 대기 순번은 %d번입니다.\n자리가 생기면 자동으로 발표자로 등록되고 DM으로 알려드립니다.", gr.MaxSpeakers, pos))
+	}
+
 	// send response
 	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: user.Mention(),
 			Flags:   discordgo.MessageFlagsEphemeral,
-			Embeds: []*discordgo.MessageEmbed{
-				registrationEmbed(s.State.User, "등록 완료", "발표자 등록이 완료되었습니다."),
-			},
+			Embeds:  []*discordgo.MessageEmbed{embed},
 		},
 	})
 }
@@ -224,3 +232,55 @@ func (rc *registrationCmd) submitChangeModal(s *discordgo.Session, i *discordgo.
 		},
 	})
 }
+
+// cancel registration or leave the waitlist, the next member in the waitlist is promoted
+func (rc *registrationCmd) cancelRegistration(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	user := utils.GetGuildUserFromInteraction(i)
+	if user == nil {
+		return study.ErrUserNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	params := &service.UpdateParams{
+		GuildID:  i.GuildID,
+		MemberID: user.ID,
+	}
+
+	_, after, err := rc.svc.UpdateRound(ctx, params, service.CancelRegistration, service.ValidateToCancelRegistration)
+	if err != nil {
+		return err
+	}
+
+	// notify members promoted in the same transaction
+	for _, id := range params.Promoted {
+		go rc.sendPromotedDM(s, id, *after)
+	}
+
+	// send response
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: user.Mention(),
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Embeds: []*discordgo.MessageEmbed{
+				registrationEmbed(s.State.User, "등록 취소 완료", "발표자 등록이 취소되었습니다."),
+			},
+		},
+	})
+}
+
+func (rc *registrationCmd) sendPromotedDM(s *discordgo.Session, memberID string, r study.Round) {
+	ch, err := s.UserChannelCreate(memberID)
+	if err != nil {
+		return
+	}
+
+	member, _ := r.GetMember(memberID)
+
+	embed := registrationEmbed(s.State.User, "발표자 등록 확정",
+		fmt.Sprintf("대기 중이던 **%d회차 %s** 발표자 자리가 생겨 발표자로 등록되었습니다.\n발표 주제: %s", r.Number, r.Title, member.Subject))
+
+	_, _ = s.ChannelMessageSendEmbed(ch.ID, embed)
+}
@@ -31,6 +31,11 @@ var (
 		Description: "발표자 등록 정보를 변경합니다.",
 	}
 
+	cancelCmd = discordgo.ApplicationCommand{
+		Name:        "발표자-등록-취소",
+		Description: "발표자 등록 또는 대기를 취소합니다.",
+	}
+
 	changeModalCustomID = "registration-change-modal"
 )
 
@@ -0,0 +1,179 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/bot/command"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/calendar"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+type scheduleCommand struct {
+	svc         service.Service
+	feedBaseURL string
+}
+
+type ScheduleOptsFunc func(*scheduleCommand)
+
+// public address of the http server serving the calendar feed
+func WithFeedBaseURL(url string) ScheduleOptsFunc {
+	return func(sc *scheduleCommand) {
+		sc.feedBaseURL = url
+	}
+}
+
+func NewScheduleCommand(svc service.Service, opts ...ScheduleOptsFunc) command.Command {
+	sc := &scheduleCommand{
+		svc: svc,
+	}
+
+	for _, opt := range opts {
+		opt(sc)
+	}
+
+	return sc
+}
+
+func (sc *scheduleCommand) Register(reg command.Registerer) {
+	reg.RegisterCommand(cmd, sc.showSchedule)
+}
+
+// show upcoming schedule with the calendar file and the feed link
+func (sc *scheduleCommand) showSchedule(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	user := utils.GetGuildUserFromInteraction(i)
+	if user == nil {
+		return study.ErrUserNotFound
+	}
+
+	var reissue bool
+
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Name == "재발급" {
+			reissue = option.BoolValue()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// get the study
+	gs, err := sc.svc.GetStudy(ctx, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	// only manager can reissue the token
+	if reissue && !gs.IsManager(user.ID) {
+		return study.ErrNotManager
+	}
+
+	// token is issued on the first request
+	if gs.CalendarToken == "" || reissue {
+		token, err := newToken()
+		if err != nil {
+			return err
+		}
+
+		gs, err = sc.svc.UpdateStudy(ctx, &service.UpdateParams{
+			GuildID: i.GuildID,
+			Token:   token,
+		}, service.UpdateCalendarToken, service.ValidateToUpdateCalendarToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	rounds, err := sc.svc.GetRounds(ctx, i.GuildID)
+	if err != nil && !errors.Is(err, study.ErrRoundNotFound) {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := calendar.WriteFeed(buf, i.GuildID, rounds); err != nil {
+		return err
+	}
+
+	embed := scheduleEmbed(s.State.User, upcomingDescription(rounds, time.Now()))
+
+	if sc.feedBaseURL != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "캘린더 구독",
+			Value: fmt.Sprintf("캘린더 앱에서 아래 주소를 구독하면 일정이 자동으로 갱신됩니다. 링크는 공유하지 말아주세요.\n%s", calendar.FeedURL(sc.feedBaseURL, gs.GuildID, gs.CalendarToken)),
+		})
+	}
+
+	// send response with the calendar file
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+			Files: []*discordgo.File{
+				{
+					Name:        "study.ics",
+					ContentType: calendar.ContentType,
+					Reader:      buf,
+				},
+			},
+		},
+	})
+}
+
+type upcoming struct {
+	number int8
+	entry  study.ScheduleEntry
+}
+
+// scheduled stages from now in order of time
+func upcomingDescription(rounds []*study.Round, now time.Time) string {
+	var entries []upcoming
+
+	for _, r := range rounds {
+		for _, e := range r.Schedule {
+			if !e.At.Before(now) {
+				entries = append(entries, upcoming{number: r.Number, entry: e})
+			}
+		}
+	}
+
+	if len(entries) == 0 {
+		return "예정된 일정이 없습니다."
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].entry.At.Before(entries[j].entry.At) })
+
+	if len(entries) > maxUpcoming {
+		entries = entries[:maxUpcoming]
+	}
+
+	b := &strings.Builder{}
+
+	for _, u := range entries {
+		at := u.entry.At.Unix()
+		fmt.Fprintf(b, "**[%d회차] %s**: <t:%d:F> (<t:%d:R>)\n", u.number, u.entry.Stage, at, at)
+	}
+
+	return b.String()
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
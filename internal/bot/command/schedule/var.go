@@ -0,0 +1,35 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// max number of upcoming entries shown in the embed
+const maxUpcoming = 10
+
+var cmd = discordgo.ApplicationCommand{
+	Name:        "일정",
+	Description: "라운드 일정을 확인하고 캘린더 파일과 구독 링크를 받습니다.",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "재발급",
+			Description: "캘린더 구독 링크를 재발급합니다. 기존 링크는 더 이상 사용할 수 없습니다. 매니저만 사용할 수 있습니다.",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+		},
+	},
+}
+
+func scheduleEmbed(u *discordgo.User, description string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    u.Username,
+			IconURL: u.AvatarURL(""),
+		},
+		Title:       "스터디 일정",
+		Description: description,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Color:       16777215,
+	}
+}
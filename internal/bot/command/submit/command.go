@@ -1,28 +1,66 @@
 package submit
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"net/url"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/piatoss3612/my-study-bot/internal/bot/command"
 	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/material"
 	"github.com/piatoss3612/my-study-bot/internal/study/service"
 	"github.com/piatoss3612/my-study-bot/internal/utils"
+	"go.uber.org/zap"
 )
 
 type submitCommand struct {
-	svc service.Service
+	svc    service.Service
+	prober material.Prober
+	hasher material.Hasher
+
+	sugar *zap.SugaredLogger
+}
+
+// messenger is the part of the discord session used to keep and share materials
+type messenger interface {
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEditEmbed(channelID, messageID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageDelete(channelID, messageID string, options ...discordgo.RequestOption) error
+}
+
+type SubmitOptsFunc func(*submitCommand)
+
+// check that submitted materials are reachable
+func WithProber(p material.Prober) SubmitOptsFunc {
+	return func(sc *submitCommand) {
+		sc.prober = p
+	}
 }
 
-func NewSubmitCommand(svc service.Service) command.Command {
-	return &submitCommand{
-		svc: svc,
+// hasher used to download attachments and compute their checksums
+func WithHasher(h material.Hasher) SubmitOptsFunc {
+	return func(sc *submitCommand) {
+		sc.hasher = h
 	}
 }
 
+func NewSubmitCommand(svc service.Service, sugar *zap.SugaredLogger, opts ...SubmitOptsFunc) command.Command {
+	sc := &submitCommand{
+		svc:    svc,
+		hasher: material.NewHasher(),
+		sugar:  sugar,
+	}
+
+	for _, opt := range opts {
+		opt(sc)
+	}
+
+	return sc
+}
+
 func (sc *submitCommand) Register(reg command.Registerer) {
 	reg.RegisterCommand(cmd, sc.submitContent)
 }
@@ -34,48 +72,281 @@ func (sc *submitCommand) submitContent(s *discordgo.Session, i *discordgo.Intera
 		return study.ErrUserNotFound
 	}
 
+	data := i.ApplicationCommandData()
+
 	// get content
-	var content string
+	var link string
+	var attachment *discordgo.MessageAttachment
 
-	for _, option := range i.ApplicationCommandData().Options {
+	for _, option := range data.Options {
 		switch option.Name {
 		case "링크":
-			content = option.StringValue()
+			link = option.StringValue()
+		case "파일":
+			if id, ok := option.Value.(string); ok && data.Resolved != nil {
+				attachment = data.Resolved.Attachments[id]
+			}
 		}
 	}
 
-	if content == "" {
-		return errors.Join(study.ErrRequiredArgs, errors.New("발표 자료 링크는 필수 입력 사항입니다"))
+	if (link == "") == (attachment == nil) {
+		return errors.Join(study.ErrRequiredArgs, errors.New("발표 자료 링크 또는 파일 중 하나를 입력해주세요"))
 	}
 
-	_, err := url.Parse(content)
+	if attachment != nil {
+		return sc.submitAttachment(s, i, user, attachment)
+	}
+
+	return sc.submitLink(s, i, user, link)
+}
+
+func (sc *submitCommand) submitLink(s *discordgo.Session, i *discordgo.InteractionCreate, user *discordgo.User, link string) error {
+	// probing the link may take longer than the response deadline
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
 	if err != nil {
-		return errors.Join(study.ErrInvalidArgs, err)
+		return err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	gs, gr, preview, err := sc.saveLink(ctx, i.GuildID, user.ID, link)
+	if err != nil {
+		sc.editError(s, i, err)
+		return nil
+	}
+
+	mat := lastMaterial(gr, user.ID)
+
+	embed := previewEmbed(s.State.User, user, gr.Title, preview)
+	markLate(embed, mat)
+
+	sc.share(s, gs, gr, user.ID, embed, nil)
+
+	content := user.Mention()
+	embeds := []*discordgo.MessageEmbed{
+		submitEmbed(s.State.User, "제출 완료", completionMessage(mat), preview.URL),
+		embed,
+	}
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+		Embeds:  &embeds,
+	})
+	return err
+}
+
+// check and probe the link and add it to the materials of the speaker
+func (sc *submitCommand) saveLink(ctx context.Context, guildID, userID, link string) (*study.Study, *study.Round, material.Preview, error) {
+	gs, err := sc.svc.GetStudy(ctx, guildID)
+	if err != nil {
+		return nil, nil, material.Preview{}, err
+	}
+
+	// check the link against the allowlist of the study
+	u, err := material.Check(link, gs.MaterialHosts)
+	if err != nil {
+		return nil, nil, material.Preview{}, err
+	}
+
+	preview := material.NewPreview(u)
+
+	if sc.prober != nil {
+		preview, err = sc.prober.Probe(ctx, u)
+		if err != nil {
+			return nil, nil, material.Preview{}, err
+		}
+	}
+
 	// set content
-	_, _, err = sc.svc.UpdateRound(ctx, &service.UpdateParams{
-		GuildID:    i.GuildID,
-		MemberID:   user.ID,
-		ContentURL: content,
+	_, gr, err := sc.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID:  guildID,
+		MemberID: userID,
+		Material: study.Material{URL: u.String()},
 	},
 		service.SubmitMemberContent, service.ValidateToSubmitMemberContent)
 	if err != nil {
+		return nil, nil, material.Preview{}, err
+	}
+
+	return gs, gr, preview, nil
+}
+
+// deferred response should be edited to show the error, responding again fails
+// so the error is logged here instead of being returned to the bot
+func (sc *submitCommand) editError(s *discordgo.Session, i *discordgo.InteractionCreate, err error) {
+	sc.sugar.Errorw("failed to submit material", "error", err, "guild_id", i.GuildID)
+
+	embeds := []*discordgo.MessageEmbed{errorEmbed(err)}
+	if _, editErr := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds}); editErr != nil {
+		sc.sugar.Errorw("failed to edit deferred response", "error", editErr, "cause", err, "guild_id", i.GuildID)
+	}
+}
+
+func (sc *submitCommand) submitAttachment(s *discordgo.Session, i *discordgo.InteractionCreate, user *discordgo.User, attachment *discordgo.MessageAttachment) error {
+	if err := material.CheckAttachment(attachment.Filename, int64(attachment.Size)); err != nil {
 		return err
 	}
 
-	// send response
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+	// downloading the attachment may take longer than the response deadline
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: user.Mention(),
-			Flags:   discordgo.MessageFlagsEphemeral,
-			Embeds: []*discordgo.MessageEmbed{
-				submitEmbed(s.State.User, "제출 완료", "발표 자료가 제출되었습니다.", content),
-			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	gs, gr, mat, kept, err := sc.saveAttachment(ctx, s, i.GuildID, user.ID, attachment)
+	if err != nil {
+		sc.editError(s, i, err)
+		return nil
+	}
+
+	embed := attachmentEmbed(s.State.User, user, gr.Title, mat)
+	markLate(embed, mat)
+
+	sc.share(s, gs, gr, user.ID, embed, kept)
+
+	content := user.Mention()
+	embeds := []*discordgo.MessageEmbed{
+		submitEmbed(s.State.User, "제출 완료", completionMessage(mat), mat.URL),
+		embed,
+	}
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+		Embeds:  &embeds,
+	})
+	return err
+}
+
+// validate the submission, keep a copy of the attachment and add it to the materials of the speaker,
+// the message keeping the copy is returned if uploaded
+func (sc *submitCommand) saveAttachment(ctx context.Context, s messenger, guildID, userID string, attachment *discordgo.MessageAttachment) (*study.Study, *study.Round, study.Material, *discordgo.Message, error) {
+	gs, err := sc.svc.GetStudy(ctx, guildID)
+	if err != nil {
+		return nil, nil, study.Material{}, nil, err
+	}
+
+	gr, err := sc.svc.GetRound(ctx, gs.OngoingRoundID)
+	if err != nil {
+		return nil, nil, study.Material{}, nil, err
+	}
+
+	params := &service.UpdateParams{
+		GuildID:  guildID,
+		MemberID: userID,
+		Material: study.Material{
+			URL:         attachment.URL,
+			Filename:    attachment.Filename,
+			Size:        int64(attachment.Size),
+			ContentType: attachment.ContentType,
 		},
+	}
+
+	// stage, limit and duplicated urls are checked before downloading the file,
+	// duplicated checksums are checked again by the update
+	if err := service.ValidateToSubmitMemberContent(gs, gr, params); err != nil {
+		return nil, nil, study.Material{}, nil, err
+	}
+
+	file, err := sc.hasher.Download(ctx, attachment.URL)
+	if err != nil {
+		return nil, nil, study.Material{}, nil, err
+	}
+
+	params.Material.Checksum = file.Checksum
+
+	// urls of the interaction attachments are signed and expire
+	kept := sc.upload(s, gs, gr, userID, attachment, file)
+	if kept != nil {
+		params.Material.URL = kept.Attachments[0].URL
+	}
+
+	_, gr, err = sc.svc.UpdateRound(ctx, params, service.SubmitMemberContent, service.ValidateToSubmitMemberContent)
+	if err != nil {
+		if kept != nil {
+			if err := s.ChannelMessageDelete(kept.ChannelID, kept.ID); err != nil {
+				sc.sugar.Errorw("failed to delete uploaded attachment", "error", err, "guild_id", guildID, "channel_id", kept.ChannelID)
+			}
+		}
+		return nil, nil, study.Material{}, nil, err
+	}
+
+	// submission time and lateness are recorded by the service
+	return gs, gr, lastMaterial(gr, userID), kept, nil
+}
+
+// upload the file to the thread of the speaker or the notice channel, nil if not uploaded
+func (sc *submitCommand) upload(s messenger, gs *study.Study, gr *study.Round, userID string, attachment *discordgo.MessageAttachment, file material.File) *discordgo.Message {
+	channelID := gs.NoticeChannelID
+	if member, ok := gr.GetMember(userID); ok && member.ThreadID != "" {
+		channelID = member.ThreadID
+	}
+
+	if channelID == "" {
+		sc.sugar.Warnw("no channel to keep the attachment, interaction url is saved", "guild_id", gs.GuildID, "member_id", userID)
+		return nil
+	}
+
+	msg, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Files: []*discordgo.File{{
+			Name:        attachment.Filename,
+			ContentType: attachment.ContentType,
+			Reader:      bytes.NewReader(file.Data),
+		}},
 	})
+	if err != nil || len(msg.Attachments) == 0 {
+		sc.sugar.Errorw("failed to upload attachment, interaction url is saved", "error", err, "guild_id", gs.GuildID, "channel_id", channelID)
+		return nil
+	}
+
+	return msg
+}
+
+// share the material in the discussion thread of the speaker and the notice channel,
+// the embed is added to the message keeping the attachment instead of being sent again to its channel
+func (sc *submitCommand) share(s messenger, gs *study.Study, gr *study.Round, userID string, embed *discordgo.MessageEmbed, kept *discordgo.Message) {
+	send := func(channelID string) error {
+		if kept != nil && kept.ChannelID == channelID {
+			_, err := s.ChannelMessageEditEmbed(kept.ChannelID, kept.ID, embed)
+			return err
+		}
+
+		_, err := s.ChannelMessageSendEmbed(channelID, embed)
+		return err
+	}
+
+	// submission is saved even if the channels are not available
+	if member, ok := gr.GetMember(userID); ok && member.ThreadID != "" {
+		if err := send(member.ThreadID); err != nil {
+			sc.sugar.Errorw("failed to share material in speaker thread", "error", err, "guild_id", gs.GuildID, "thread_id", member.ThreadID)
+		}
+	}
+
+	if gs.NoticeChannelID != "" {
+		if err := send(gs.NoticeChannelID); err != nil {
+			sc.sugar.Errorw("failed to share material in notice channel", "error", err, "guild_id", gs.GuildID, "channel_id", gs.NoticeChannelID)
+		}
+	}
+}
+
+// material added by the latest submission of the member
+func lastMaterial(gr *study.Round, userID string) study.Material {
+	member, ok := gr.GetMember(userID)
+	if !ok || len(member.Materials) == 0 {
+		return study.Material{}
+	}
+	return member.Materials[len(member.Materials)-1]
 }
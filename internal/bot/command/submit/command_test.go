@@ -0,0 +1,251 @@
+package submit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/material"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"go.uber.org/zap"
+)
+
+type fakeService struct {
+	service.Service
+
+	study *study.Study
+	round *study.Round
+}
+
+func (f *fakeService) GetStudy(_ context.Context, guildID string) (*study.Study, error) {
+	if f.study.GuildID != guildID {
+		return nil, study.ErrStudyNotFound
+	}
+	s := *f.study
+	return &s, nil
+}
+
+func (f *fakeService) GetRound(_ context.Context, roundID string) (*study.Round, error) {
+	if f.round.ID != roundID {
+		return nil, study.ErrRoundNotFound
+	}
+	r := copyRound(*f.round)
+	return &r, nil
+}
+
+func (f *fakeService) UpdateRound(_ context.Context, params *service.UpdateParams, update service.UpdateFunc, validators ...service.UpdateValidator) (*study.Study, *study.Round, error) {
+	s := *f.study
+	r := copyRound(*f.round)
+
+	for _, validate := range validators {
+		if err := validate(&s, &r, params); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	update(&s, &r, params)
+
+	f.round = &r
+	return &s, &r, nil
+}
+
+func copyRound(r study.Round) study.Round {
+	members := make(map[string]study.Member, len(r.Members))
+	for id, m := range r.Members {
+		members[id] = m
+	}
+	r.Members = members
+	return r
+}
+
+type fakeHasher struct {
+	checksum  string
+	downloads int
+}
+
+func (f *fakeHasher) Checksum(_ context.Context, _ string) (string, error) {
+	return f.checksum, nil
+}
+
+func (f *fakeHasher) Download(_ context.Context, _ string) (material.File, error) {
+	f.downloads++
+	return material.File{Data: []byte("slides"), Checksum: f.checksum}, nil
+}
+
+type fakeMessenger struct {
+	messages map[string]int // channel id -> number of messages
+	edits    map[string]int
+	deleted  []string
+	next     int
+}
+
+func newFakeMessenger() *fakeMessenger {
+	return &fakeMessenger{messages: map[string]int{}, edits: map[string]int{}}
+}
+
+func (f *fakeMessenger) send(channelID string) *discordgo.Message {
+	f.next++
+	f.messages[channelID]++
+	id := fmt.Sprintf("msg%d", f.next)
+	return &discordgo.Message{ID: id, ChannelID: channelID}
+}
+
+func (f *fakeMessenger) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	msg := f.send(channelID)
+	for _, file := range data.Files {
+		msg.Attachments = append(msg.Attachments, &discordgo.MessageAttachment{
+			URL:      fmt.Sprintf("https://cdn.discordapp.com/attachments/%s/%s/%s", channelID, msg.ID, file.Name),
+			Filename: file.Name,
+		})
+	}
+	return msg, nil
+}
+
+func (f *fakeMessenger) ChannelMessageSendEmbed(channelID string, _ *discordgo.MessageEmbed, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return f.send(channelID), nil
+}
+
+func (f *fakeMessenger) ChannelMessageEditEmbed(channelID, messageID string, _ *discordgo.MessageEmbed, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	f.edits[channelID]++
+	return &discordgo.Message{ID: messageID, ChannelID: channelID}, nil
+}
+
+func (f *fakeMessenger) ChannelMessageDelete(channelID, messageID string, _ ...discordgo.RequestOption) error {
+	f.deleted = append(f.deleted, channelID+"/"+messageID)
+	return nil
+}
+
+func newTestSubmit(stage study.Stage, threadID string, materials ...study.Material) (*submitCommand, *fakeService, *fakeHasher) {
+	s := study.New()
+	s.GuildID = "g1"
+	s.NoticeChannelID = "notice"
+	s.OngoingRoundID = "r1"
+	s.CurrentStage = stage
+
+	r := study.NewRound()
+	r.SetID("r1")
+	r.SetGuildID("g1")
+
+	m := study.NewMember()
+	m.SetRegistered(true)
+	m.ThreadID = threadID
+	for _, mat := range materials {
+		m.AddMaterial(mat)
+	}
+	r.SetMember("u1", m)
+
+	svc := &fakeService{study: &s, round: &r}
+	hasher := &fakeHasher{checksum: "abc"}
+
+	sc := NewSubmitCommand(svc, zap.NewNop().Sugar(), WithHasher(hasher)).(*submitCommand)
+	return sc, svc, hasher
+}
+
+func TestSaveAttachment(t *testing.T) {
+	attachment := &discordgo.MessageAttachment{
+		URL:         "https://cdn.discordapp.com/ephemeral-attachments/1/2/slides.pdf?ex=signed",
+		Filename:    "slides.pdf",
+		Size:        6,
+		ContentType: "application/pdf",
+	}
+
+	tests := []struct {
+		name      string
+		stage     study.Stage
+		threadID  string
+		materials []study.Material
+		err       error
+		downloads int
+		messages  map[string]int // messages left in the channels
+		edits     map[string]int
+	}{
+		{
+			name:     "not submission stage",
+			stage:    study.StageRegistrationOpened,
+			threadID: "thread",
+			err:      study.ErrInvalidStage,
+			messages: map[string]int{},
+			edits:    map[string]int{},
+		},
+		{
+			name:      "speaker thread",
+			stage:     study.StageSubmissionOpened,
+			threadID:  "thread",
+			downloads: 1,
+			messages:  map[string]int{"thread": 1, "notice": 1},
+			edits:     map[string]int{"thread": 1},
+		},
+		{
+			name:      "no speaker thread",
+			stage:     study.StageSubmissionOpened,
+			downloads: 1,
+			messages:  map[string]int{"notice": 1},
+			edits:     map[string]int{"notice": 1},
+		},
+		{
+			name:      "duplicated checksum",
+			stage:     study.StageSubmissionOpened,
+			threadID:  "thread",
+			materials: []study.Material{{URL: "https://example.com/slides.pdf", Checksum: "abc"}},
+			err:       study.ErrInvalidArgs,
+			downloads: 1,
+			messages:  map[string]int{"thread": 1},
+			edits:     map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, svc, hasher := newTestSubmit(tt.stage, tt.threadID, tt.materials...)
+			m := newFakeMessenger()
+
+			gs, gr, mat, kept, err := sc.saveAttachment(context.Background(), m, "g1", "u1", attachment)
+
+			if hasher.downloads != tt.downloads {
+				t.Fatalf("expected %d downloads, got %d", tt.downloads, hasher.downloads)
+			}
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+
+				// uploaded copy is removed when the submission is rejected
+				if kept != nil || len(m.deleted) != m.messages["thread"]+m.messages["notice"] {
+					t.Fatalf("uploaded attachment is not deleted: %v", m.deleted)
+				}
+
+				member, _ := svc.round.GetMember("u1")
+				if len(member.Materials) != len(tt.materials) {
+					t.Fatalf("rejected material is saved: %v", member.Materials)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mat.URL != kept.Attachments[0].URL || mat.Checksum != "abc" {
+				t.Fatalf("expected uploaded attachment to be saved, got %+v", mat)
+			}
+
+			sc.share(m, gs, gr, "u1", &discordgo.MessageEmbed{}, kept)
+
+			if len(m.deleted) != 0 {
+				t.Fatalf("unexpected deletes: %v", m.deleted)
+			}
+
+			if fmt.Sprint(m.messages) != fmt.Sprint(tt.messages) {
+				t.Fatalf("expected messages %v, got %v", tt.messages, m.messages)
+			}
+
+			if fmt.Sprint(m.edits) != fmt.Sprint(tt.edits) {
+				t.Fatalf("expected edits %v, got %v", tt.edits, m.edits)
+			}
+		})
+	}
+}
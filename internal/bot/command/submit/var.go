@@ -1,20 +1,27 @@
 package submit
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/material"
 )
 
 var cmd = discordgo.ApplicationCommand{
 	Name:        "발표-자료-제출",
-	Description: "발표 자료를 제출합니다.",
+	Description: "발표 자료 링크 또는 파일을 제출합니다. 여러 개를 제출할 수 있습니다.",
 	Options: []*discordgo.ApplicationCommandOption{
 		{
 			Name:        "링크",
 			Description: "발표 자료 링크를 입력해주세요.",
 			Type:        discordgo.ApplicationCommandOptionString,
-			Required:    true,
+		},
+		{
+			Name:        "파일",
+			Description: "발표 자료 파일을 첨부해주세요. (pdf, ppt, pptx, key, odp, md, zip / 25MB 이하)",
+			Type:        discordgo.ApplicationCommandOptionAttachment,
 		},
 	},
 }
@@ -37,3 +44,84 @@ func submitEmbed(u *discordgo.User, title, description string, url ...string) *d
 
 	return embed
 }
+
+// rich preview of the submitted material
+func previewEmbed(u, speaker *discordgo.User, roundTitle string, p material.Preview) *discordgo.MessageEmbed {
+	embed := submitEmbed(u, fmt.Sprintf("[%s] %s님의 발표 자료", roundTitle, speaker.Username), p.URL, p.URL)
+
+	embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: speaker.AvatarURL("")}
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "종류", Value: p.Kind, Inline: true},
+		{Name: "도메인", Value: p.Host, Inline: true},
+	}
+
+	if p.ContentType != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "형식", Value: p.ContentType, Inline: true})
+	}
+
+	if p.ContentLength >= 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "크기", Value: formatSize(p.ContentLength), Inline: true})
+	}
+
+	if p.Probed() {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "링크 접근 확인됨"}
+	}
+
+	return embed
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
+
+// preview of the submitted attachment
+func attachmentEmbed(u, speaker *discordgo.User, roundTitle string, m study.Material) *discordgo.MessageEmbed {
+	embed := submitEmbed(u, fmt.Sprintf("[%s] %s님의 발표 자료", roundTitle, speaker.Username), m.Filename, m.URL)
+
+	embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: speaker.AvatarURL("")}
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "종류", Value: "파일", Inline: true},
+		{Name: "크기", Value: formatSize(m.Size), Inline: true},
+	}
+
+	if m.ContentType != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "형식", Value: m.ContentType, Inline: true})
+	}
+
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("SHA-256 %s", m.Checksum)}
+
+	return embed
+}
+
+func errorEmbed(err error) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "오류",
+		Description: err.Error(),
+		Color:       0xff0000,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
+
+func completionMessage(m study.Material) string {
+	if m.Late {
+		return "발표 자료가 제출되었습니다. 기한이 지난 제출로 기록되었습니다."
+	}
+	return "발표 자료가 제출되었습니다."
+}
+
+// show that the material is submitted after the deadline
+func markLate(embed *discordgo.MessageEmbed, m study.Material) {
+	if !m.Late {
+		return
+	}
+
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "제출 상태", Value: "⚠️ 지각 제출", Inline: true})
+	embed.Color = 0xffa500
+}
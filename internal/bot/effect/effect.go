@@ -0,0 +1,62 @@
+package effect
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"go.uber.org/zap"
+)
+
+// Effects applies changes of the round to discord, shared by the bot and the operator cli
+type Effects interface {
+	// create or update the scheduled event of the presentation as the round moves
+	SyncPresentationEvent(s *discordgo.Session, gs study.Study, gr study.Round)
+	// create discussion threads of the speakers when the registration is closed and archive them when the round is finished
+	SyncSpeakerThreads(s *discordgo.Session, gs study.Study, gr study.Round)
+}
+
+type effects struct {
+	svc service.Service
+
+	mtx   *sync.Mutex
+	locks map[string]*sync.Mutex // guild id to lock held while applying effects
+
+	sugar *zap.SugaredLogger
+}
+
+func NewEffects(svc service.Service, sugar *zap.SugaredLogger) Effects {
+	return &effects{
+		svc:   svc,
+		mtx:   &sync.Mutex{},
+		locks: map[string]*sync.Mutex{},
+		sugar: sugar,
+	}
+}
+
+func (e *effects) latestRound(roundID string) (*study.Round, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return e.svc.GetRound(ctx, roundID)
+}
+
+// effects of the same guild are applied one at a time
+func (e *effects) lock(guildID string) func() {
+	e.mtx.Lock()
+
+	l, ok := e.locks[guildID]
+	if !ok {
+		l = &sync.Mutex{}
+		e.locks[guildID] = l
+	}
+
+	e.mtx.Unlock()
+
+	l.Lock()
+
+	return l.Unlock
+}
@@ -0,0 +1,228 @@
+package effect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+const (
+	maxEventNameLength        = 100
+	maxEventDescriptionLength = 1000
+
+	// start of the event when the presentation is not scheduled
+	defaultPresentationLead = 24 * time.Hour
+	// start of the event should be in the future when it is created
+	minPresentationLead = time.Minute
+)
+
+// serialized per guild so that concurrent moves of the round do not create duplicated events
+func (e *effects) SyncPresentationEvent(s *discordgo.Session, gs study.Study, gr study.Round) {
+	unlock := e.lock(gs.GuildID)
+	defer unlock()
+
+	// id of the event may be saved by the effects applied while waiting for the lock
+	latest, err := e.latestRound(gr.ID)
+	if err != nil {
+		e.sugar.Errorw("failed to get round", "error", err, "guild_id", gs.GuildID, "round_id", gr.ID)
+		return
+	}
+
+	gr = *latest
+
+	// event is created when the round reaches the submission stage
+	if gr.Stage < study.StageSubmissionOpened {
+		return
+	}
+
+	if gr.ScheduledEventID == "" {
+		// event is not needed anymore
+		if gr.Stage > study.StagePresentationStarted {
+			return
+		}
+
+		if gs.PresentationChannelID == "" {
+			e.sugar.Infow("presentation channel is not set, skip creating scheduled event", "guild_id", gs.GuildID, "round_id", gr.ID)
+			return
+		}
+
+		evt, err := e.createPresentationEvent(s, gs, gr)
+		if err != nil {
+			e.sugar.Errorw("failed to create scheduled event", "error", err, "guild_id", gs.GuildID, "round_id", gr.ID)
+			return
+		}
+
+		gr.SetScheduledEventID(evt.ID)
+
+		// activate the event below if the presentation already started
+		if gr.Stage < study.StagePresentationStarted {
+			return
+		}
+	}
+
+	current, err := s.GuildScheduledEvent(gs.GuildID, gr.ScheduledEventID, false)
+	if err != nil {
+		e.sugar.Errorw("failed to get scheduled event", "error", err, "guild_id", gs.GuildID, "event_id", gr.ScheduledEventID)
+		return
+	}
+
+	params, ok := presentationEventUpdate(current.Status, gr, time.Now())
+	if !ok {
+		return
+	}
+
+	if _, err := s.GuildScheduledEventEdit(gs.GuildID, current.ID, params); err != nil {
+		e.sugar.Errorw("failed to update scheduled event", "error", err, "guild_id", gs.GuildID, "event_id", current.ID)
+		return
+	}
+
+	e.sugar.Infow("scheduled event updated", "guild_id", gs.GuildID, "event_id", current.ID, "stage", gr.Stage.String())
+}
+
+// create the scheduled event and save its id to the round
+func (e *effects) createPresentationEvent(s *discordgo.Session, gs study.Study, gr study.Round) (*discordgo.GuildScheduledEvent, error) {
+	entityType := discordgo.GuildScheduledEventEntityTypeVoice
+
+	ch, err := s.State.Channel(gs.PresentationChannelID)
+	if err != nil {
+		ch, err = s.Channel(gs.PresentationChannelID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ch.Type == discordgo.ChannelTypeGuildStageVoice {
+		entityType = discordgo.GuildScheduledEventEntityTypeStageInstance
+	}
+
+	start, end := presentationEventTime(gr, time.Now())
+
+	evt, err := s.GuildScheduledEventCreate(gs.GuildID, &discordgo.GuildScheduledEventParams{
+		ChannelID:          ch.ID,
+		Name:               presentationEventName(gr),
+		Description:        presentationEventDescription(gr),
+		ScheduledStartTime: &start,
+		ScheduledEndTime:   end,
+		PrivacyLevel:       discordgo.GuildScheduledEventPrivacyLevelGuildOnly,
+		EntityType:         entityType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _, err = e.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID: gs.GuildID,
+		RoundID: gr.ID,
+		EventID: evt.ID,
+	}, service.UpdateScheduledEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	e.sugar.Infow("scheduled event created", "guild_id", gs.GuildID, "round_id", gr.ID, "event_id", evt.ID)
+
+	return evt, nil
+}
+
+// changes of the scheduled event for the stage of the round, false if nothing to change
+func presentationEventUpdate(status discordgo.GuildScheduledEventStatus, gr study.Round, now time.Time) (*discordgo.GuildScheduledEventParams, bool) {
+	switch status {
+	case discordgo.GuildScheduledEventStatusScheduled:
+		switch {
+		case gr.Stage < study.StagePresentationStarted:
+			start, end := presentationEventTime(gr, now)
+
+			return &discordgo.GuildScheduledEventParams{
+				Name:               presentationEventName(gr),
+				Description:        presentationEventDescription(gr),
+				ScheduledStartTime: &start,
+				ScheduledEndTime:   end,
+			}, true
+		case gr.Stage == study.StagePresentationStarted:
+			return &discordgo.GuildScheduledEventParams{
+				Name:        presentationEventName(gr),
+				Description: presentationEventDescription(gr),
+				Status:      discordgo.GuildScheduledEventStatusActive,
+			}, true
+		default:
+			// scheduled event cannot be completed without being started
+			return &discordgo.GuildScheduledEventParams{
+				Status: discordgo.GuildScheduledEventStatusCanceled,
+			}, true
+		}
+	case discordgo.GuildScheduledEventStatusActive:
+		if gr.Stage > study.StagePresentationStarted {
+			return &discordgo.GuildScheduledEventParams{
+				Status: discordgo.GuildScheduledEventStatusCompleted,
+			}, true
+		}
+	}
+
+	// completed or canceled event cannot be changed
+	return nil, false
+}
+
+// start and end of the presentation, end is nil if not scheduled
+func presentationEventTime(gr study.Round, now time.Time) (time.Time, *time.Time) {
+	start, ok := gr.ScheduleOf(study.StagePresentationStarted)
+
+	switch {
+	case gr.Stage >= study.StagePresentationStarted:
+		start = now.Add(minPresentationLead)
+	case !ok:
+		start = now.Add(defaultPresentationLead)
+	case start.Before(now.Add(minPresentationLead)):
+		start = now.Add(minPresentationLead)
+	}
+
+	if end, ok := gr.ScheduleOf(study.StagePresentationFinished); ok && end.After(start) {
+		return start, &end
+	}
+
+	return start, nil
+}
+
+func presentationEventName(gr study.Round) string {
+	return utils.Truncate(fmt.Sprintf("[%d회차] %s 발표", gr.Number, gr.Title), maxEventNameLength)
+}
+
+// speakers and subjects of the round
+func presentationEventDescription(gr study.Round) string {
+	b := &strings.Builder{}
+
+	for _, id := range sortedSpeakerIDs(gr) {
+		m := gr.Members[id]
+		fmt.Fprintf(b, "- %s: %s\n", m.Name, m.Subject)
+	}
+
+	if b.Len() == 0 {
+		return "등록된 발표자가 없습니다."
+	}
+
+	return utils.Truncate(strings.TrimSuffix(b.String(), "\n"), maxEventDescriptionLength)
+}
+
+// ids of registered members in ascending order
+func sortedSpeakerIDs(gr study.Round) []string {
+	ids := make([]string, 0, len(gr.Members))
+
+	for id, m := range gr.Members {
+		if m.IsRegistered() {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
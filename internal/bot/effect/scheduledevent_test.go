@@ -0,0 +1,146 @@
+package effect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func testRound(stage study.Stage, schedule map[study.Stage]time.Time) study.Round {
+	r := study.NewRound()
+	r.SetNumber(2)
+	r.SetTitle("동시성")
+	r.SetStage(stage)
+
+	for st, at := range schedule {
+		r.SetSchedule(st, at)
+	}
+
+	return r
+}
+
+func TestPresentationEventTime(t *testing.T) {
+	now := time.Date(2023, 6, 3, 12, 0, 0, 0, time.UTC)
+	start := now.Add(48 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	tests := []struct {
+		name     string
+		stage    study.Stage
+		schedule map[study.Stage]time.Time
+		start    time.Time
+		end      *time.Time
+	}{
+		{
+			name:  "not scheduled",
+			stage: study.StageSubmissionOpened,
+			start: now.Add(defaultPresentationLead),
+		},
+		{
+			name:     "scheduled start and end",
+			stage:    study.StageSubmissionOpened,
+			schedule: map[study.Stage]time.Time{study.StagePresentationStarted: start, study.StagePresentationFinished: end},
+			start:    start,
+			end:      &end,
+		},
+		{
+			name:     "scheduled start in the past",
+			stage:    study.StageSubmissionOpened,
+			schedule: map[study.Stage]time.Time{study.StagePresentationStarted: now.Add(-time.Hour)},
+			start:    now.Add(minPresentationLead),
+		},
+		{
+			name:     "presentation already started",
+			stage:    study.StagePresentationStarted,
+			schedule: map[study.Stage]time.Time{study.StagePresentationStarted: start},
+			start:    now.Add(minPresentationLead),
+		},
+		{
+			name:     "end before start is ignored",
+			stage:    study.StageSubmissionOpened,
+			schedule: map[study.Stage]time.Time{study.StagePresentationStarted: start, study.StagePresentationFinished: start.Add(-time.Minute)},
+			start:    start,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd := presentationEventTime(testRound(tt.stage, tt.schedule), now)
+
+			if !gotStart.Equal(tt.start) {
+				t.Errorf("start = %v, want %v", gotStart, tt.start)
+			}
+
+			switch {
+			case tt.end == nil && gotEnd != nil:
+				t.Errorf("end = %v, want nil", *gotEnd)
+			case tt.end != nil && (gotEnd == nil || !gotEnd.Equal(*tt.end)):
+				t.Errorf("end = %v, want %v", gotEnd, *tt.end)
+			}
+		})
+	}
+}
+
+func TestPresentationEventUpdate(t *testing.T) {
+	now := time.Date(2023, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		status      discordgo.GuildScheduledEventStatus
+		stage       study.Stage
+		ok          bool
+		want        discordgo.GuildScheduledEventStatus
+		rescheduled bool
+	}{
+		{"scheduled before presentation", discordgo.GuildScheduledEventStatusScheduled, study.StageSubmissionClosed, true, 0, true},
+		{"scheduled at presentation", discordgo.GuildScheduledEventStatusScheduled, study.StagePresentationStarted, true, discordgo.GuildScheduledEventStatusActive, false},
+		{"scheduled after presentation", discordgo.GuildScheduledEventStatusScheduled, study.StagePresentationFinished, true, discordgo.GuildScheduledEventStatusCanceled, false},
+		{"active at presentation", discordgo.GuildScheduledEventStatusActive, study.StagePresentationStarted, false, 0, false},
+		{"active after presentation", discordgo.GuildScheduledEventStatusActive, study.StagePresentationFinished, true, discordgo.GuildScheduledEventStatusCompleted, false},
+		{"completed", discordgo.GuildScheduledEventStatusCompleted, study.StageFinished, false, 0, false},
+		{"canceled", discordgo.GuildScheduledEventStatusCanceled, study.StageSubmissionOpened, false, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, ok := presentationEventUpdate(tt.status, testRound(tt.stage, nil), now)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			if params.Status != tt.want {
+				t.Errorf("status = %v, want %v", params.Status, tt.want)
+			}
+
+			if rescheduled := params.ScheduledStartTime != nil; rescheduled != tt.rescheduled {
+				t.Errorf("rescheduled = %v, want %v", rescheduled, tt.rescheduled)
+			}
+		})
+	}
+}
+
+func TestPresentationEventDescription(t *testing.T) {
+	r := testRound(study.StageSubmissionOpened, nil)
+
+	if got := presentationEventDescription(r); got != "등록된 발표자가 없습니다." {
+		t.Fatalf("unexpected description without speakers: %q", got)
+	}
+
+	for _, id := range []string{"2", "1"} {
+		m := study.NewMember()
+		m.SetName("user" + id)
+		m.SetSubject("subject" + id)
+		m.SetRegistered(true)
+		r.SetMember(id, m)
+	}
+
+	if got, want := presentationEventDescription(r), "- user1: subject1\n- user2: subject2"; got != want {
+		t.Fatalf("description = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+package effect
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"github.com/piatoss3612/my-study-bot/internal/utils"
+)
+
+const (
+	maxThreadNameLength = 100
+
+	// threads are archived by the bot when the round is finished
+	threadAutoArchiveDuration = 10080 // minutes, 7 days
+)
+
+// serialized per guild so that concurrent moves of the round do not create duplicated threads
+func (e *effects) SyncSpeakerThreads(s *discordgo.Session, gs study.Study, gr study.Round) {
+	if !gr.Stage.IsRegistrationClosed() && !gr.Stage.IsFinished() {
+		return
+	}
+
+	unlock := e.lock(gs.GuildID)
+	defer unlock()
+
+	// ids of the threads may be saved by the effects applied while waiting for the lock
+	latest, err := e.latestRound(gr.ID)
+	if err != nil {
+		e.sugar.Errorw("failed to get round", "error", err, "guild_id", gs.GuildID, "round_id", gr.ID)
+		return
+	}
+
+	switch {
+	case latest.Stage.IsRegistrationClosed():
+		e.createSpeakerThreads(s, gs, *latest)
+	case latest.Stage.IsFinished():
+		e.archiveSpeakerThreads(s, *latest)
+	}
+}
+
+// create discussion thread of each registered speaker in the notice channel
+func (e *effects) createSpeakerThreads(s *discordgo.Session, gs study.Study, gr study.Round) {
+	if gs.NoticeChannelID == "" {
+		e.sugar.Infow("notice channel is not set, skip creating speaker threads", "guild_id", gs.GuildID, "round_id", gr.ID)
+		return
+	}
+
+	threads := map[string]string{}
+
+	for _, id := range sortedSpeakerIDs(gr) {
+		m := gr.Members[id]
+
+		// thread is created only once
+		if m.ThreadID != "" {
+			continue
+		}
+
+		th, err := s.ThreadStart(gs.NoticeChannelID, speakerThreadName(gr, m), discordgo.ChannelTypeGuildPublicThread, threadAutoArchiveDuration)
+		if err != nil {
+			e.sugar.Errorw("failed to create speaker thread", "error", err, "guild_id", gs.GuildID, "member_id", id)
+			continue
+		}
+
+		threads[id] = th.ID
+
+		embed := threadEmbed(s.State.User, m.Subject,
+			fmt.Sprintf("<@%s>님의 발표에 대한 질문과 피드백을 이 스레드에 남겨주세요.", id))
+
+		if m.ContentURL != "" {
+			embed.URL = m.ContentURL
+		}
+
+		if _, err := s.ChannelMessageSendEmbed(th.ID, embed); err != nil {
+			e.sugar.Errorw("failed to send a message to speaker thread", "error", err, "guild_id", gs.GuildID, "thread_id", th.ID)
+		}
+	}
+
+	if len(threads) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _, err := e.svc.UpdateRound(ctx, &service.UpdateParams{
+		GuildID: gs.GuildID,
+		RoundID: gr.ID,
+		Threads: threads,
+	}, service.UpdateThreadIDs)
+	if err != nil {
+		e.sugar.Errorw("failed to save speaker threads", "error", err, "guild_id", gs.GuildID, "round_id", gr.ID)
+		return
+	}
+
+	e.sugar.Infow("speaker threads created", "guild_id", gs.GuildID, "round_id", gr.ID, "count", len(threads))
+}
+
+// archive discussion threads of the finished round
+func (e *effects) archiveSpeakerThreads(s *discordgo.Session, gr study.Round) {
+	archived := true
+
+	for id, m := range gr.Members {
+		if m.ThreadID == "" {
+			continue
+		}
+
+		_, err := s.ChannelEditComplex(m.ThreadID, &discordgo.ChannelEdit{
+			Archived: &archived,
+		})
+		if err != nil {
+			e.sugar.Errorw("failed to archive speaker thread", "error", err, "member_id", id, "thread_id", m.ThreadID)
+		}
+	}
+}
+
+func speakerThreadName(gr study.Round, m study.Member) string {
+	name := m.Subject
+	if name == "" {
+		name = m.Name
+	}
+
+	return utils.Truncate(fmt.Sprintf("[%d회차] %s", gr.Number, name), maxThreadNameLength)
+}
+
+func threadEmbed(u *discordgo.User, title, description string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    u.Username,
+			IconURL: u.AvatarURL(""),
+		},
+		Title:       title,
+		Description: description,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Color:       16777215,
+	}
+}
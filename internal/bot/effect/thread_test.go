@@ -0,0 +1,44 @@
+package effect
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestSpeakerThreadName(t *testing.T) {
+	r := study.NewRound()
+	r.SetNumber(12)
+
+	tests := []struct {
+		name    string
+		subject string
+		member  string
+		want    string
+	}{
+		{"subject", "채널과 고루틴", "alice", "[12회차] 채널과 고루틴"},
+		{"name without subject", "", "alice", "[12회차] alice"},
+		{"truncated", strings.Repeat("가", 120), "alice", "[12회차] " + strings.Repeat("가", maxThreadNameLength-len([]rune("[12회차] "))-1) + "…"},
+		{"exactly the limit", strings.Repeat("a", maxThreadNameLength-len([]rune("[12회차] "))), "alice", "[12회차] " + strings.Repeat("a", maxThreadNameLength-len([]rune("[12회차] ")))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := study.NewMember()
+			m.SetName(tt.member)
+			m.SetSubject(tt.subject)
+
+			got := speakerThreadName(r, m)
+
+			if got != tt.want {
+				t.Errorf("name = %q, want %q", got, tt.want)
+			}
+
+			if n := utf8.RuneCountInString(got); n > maxThreadNameLength {
+				t.Errorf("name has %d characters, want at most %d", n, maxThreadNameLength)
+			}
+		})
+	}
+}
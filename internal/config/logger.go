@@ -9,6 +9,33 @@ type LoggerConfig struct {
 		Kind     string `mapstructure:"kind"`
 		Queue    string `mapstructure:"queue"`
 	} `mapstructure:"rabbitmq"`
+	RedisStreams struct {
+		Addr     string `mapstructure:"addr"`
+		Stream   string `mapstructure:"stream"`
+		Group    string `mapstructure:"group"`
+		Consumer string `mapstructure:"consumer"`
+	} `mapstructure:"redis_streams"`
+	MongoDB struct {
+		URI    string `mapstructure:"uri"`
+		DBName string `mapstructure:"db_name"`
+	} `mapstructure:"mongodb"` // used to resolve secrets of webhooks
+	PubSub struct {
+		Driver string `mapstructure:"driver"`
+	} `mapstructure:"pubsub"`
+	Sinks struct {
+		File struct {
+			Dir    string `mapstructure:"dir"`
+			Format string `mapstructure:"format"`
+		} `mapstructure:"file"`
+		Markdown struct {
+			Dir string `mapstructure:"dir"`
+		} `mapstructure:"markdown"`
+		Webhook struct {
+			Enabled     bool   `mapstructure:"enabled"`
+			DeliveryLog string `mapstructure:"delivery_log"` // path of the jsonl file of delivery attempts
+			MaxRetries  int    `mapstructure:"max_retries"`
+		} `mapstructure:"webhook"`
+	} `mapstructure:"sinks"`
 }
 
 func NewLoggerConfig(filename string) (*LoggerConfig, error) {
@@ -0,0 +1,6 @@
+package config
+
+const (
+	PubSubDriverRabbitMQ = "rabbitmq"
+	PubSubDriverRedis    = "redis"
+)
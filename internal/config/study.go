@@ -1,6 +1,10 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type StudyConfig struct {
 	Discord struct {
@@ -20,6 +24,33 @@ type StudyConfig struct {
 		Exchange string `mapstructure:"exchange"`
 		Kind     string `mapstructure:"kind"`
 	} `mapstructure:"rabbitmq"`
+	RedisStreams struct {
+		Addr   string `mapstructure:"addr"`
+		Stream string `mapstructure:"stream"`
+		MaxLen int64  `mapstructure:"max_len"`
+	} `mapstructure:"redis_streams"`
+	PubSub struct {
+		Driver string `mapstructure:"driver"`
+	} `mapstructure:"pubsub"`
+	API struct {
+		Tokens map[string]string `mapstructure:"tokens"` // guild id to token
+	} `mapstructure:"api"`
+	Dashboard struct {
+		ClientID     string `mapstructure:"client_id"`
+		ClientSecret string `mapstructure:"client_secret"`
+		RedirectURL  string `mapstructure:"redirect_url"`
+	} `mapstructure:"dashboard"`
+	Calendar struct {
+		BaseURL string `mapstructure:"base_url"` // public address of the http server
+	} `mapstructure:"calendar"`
+	Attendance struct {
+		Threshold time.Duration `mapstructure:"threshold"`  // time to be spent in the presentation channel
+		AutoApply bool          `mapstructure:"auto_apply"` // confirm without review of manager
+	} `mapstructure:"attendance"`
+	Submission struct {
+		Probe        bool          `mapstructure:"probe"`         // check that submitted links are reachable
+		ProbeTimeout time.Duration `mapstructure:"probe_timeout"` // timeout of the HEAD request
+	} `mapstructure:"submission"`
 }
 
 func NewStudyConfig(filename string) (*StudyConfig, error) {
@@ -46,29 +46,47 @@ type LoggerService struct {
 	sub    pubsub.Subscriber
 	mapper pubsub.Mapper
 
-	srv *http.Server
+	srv        *http.Server
+	collectors []prometheus.Collector
 
 	sugar *zap.SugaredLogger
 }
 
-func New(sub pubsub.Subscriber, mapper pubsub.Mapper, sugar *zap.SugaredLogger) *LoggerService {
+type LoggerOptsFunc func(*LoggerService)
+
+// expose additional metrics on the metric server
+func WithCollectors(collectors ...prometheus.Collector) LoggerOptsFunc {
+	return func(l *LoggerService) {
+		l.collectors = append(l.collectors, collectors...)
+	}
+}
+
+func New(sub pubsub.Subscriber, mapper pubsub.Mapper, sugar *zap.SugaredLogger, opts ...LoggerOptsFunc) *LoggerService {
 	svc := &LoggerService{
 		sub:    sub,
 		mapper: mapper,
 		sugar:  sugar,
 	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
 	return svc.setup()
 }
 
 func (l *LoggerService) setup() *LoggerService {
-	metrics := prometheus.NewRegistry()
-	metrics.MustRegister(collectors.NewGoCollector())
-	metrics.MustRegister(totalEvents)
-	metrics.MustRegister(totalErrors)
-	metrics.MustRegister(duration)
+	l.mapper.Use(l.logging(), metrics(), pubsub.Recovery())
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector())
+	registry.MustRegister(totalEvents)
+	registry.MustRegister(totalErrors)
+	registry.MustRegister(duration)
+	registry.MustRegister(l.collectors...)
 
 	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.HandlerFor(metrics, promhttp.HandlerOpts{}))
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 	mux.HandleFunc("/healthcheck", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -115,8 +133,8 @@ func (l *LoggerService) Close(ctx context.Context) error {
 	return nil
 }
 
-func (l *LoggerService) Listen(stop <-chan bool, topics []string) {
-	msgs, errs, close, err := l.sub.Subscribe(topics...)
+func (l *LoggerService) Listen(stop <-chan bool) {
+	msgs, errs, close, err := l.sub.Subscribe(l.mapper.Topics()...)
 	if err != nil {
 		l.sugar.Fatal(err)
 	}
@@ -125,26 +143,7 @@ func (l *LoggerService) Listen(stop <-chan bool, topics []string) {
 	for {
 		select {
 		case msg := <-msgs:
-			h, ok := l.mapper.Map(msg.Topic)
-			if !ok {
-				l.sugar.Errorw("Unknown event name", "event", msg.Topic)
-				continue
-			}
-
-			totalEvents.WithLabelValues(msg.Topic).Inc()
-
-			timer := prometheus.NewTimer(duration.WithLabelValues(msg.Topic))
-
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-
-			if err := h.Handle(ctx, msg.Body); err != nil {
-				totalErrors.WithLabelValues(msg.Topic).Inc()
-				l.sugar.Errorw("Failed to handle event", "event", msg.Topic, "error", err, "duration", timer.ObserveDuration().String())
-				continue
-			}
-
-			l.sugar.Infow("Successfully handled event", "event", msg.Topic, "duration", timer.ObserveDuration().String())
+			l.handle(msg)
 		case err := <-errs:
 			if err == nil {
 				continue
@@ -158,3 +157,32 @@ func (l *LoggerService) Listen(stop <-chan bool, topics []string) {
 		}
 	}
 }
+
+// handle the message and acknowledge it only if handled successfully, otherwise reject it
+func (l *LoggerService) handle(msg pubsub.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	h, ok := l.mapper.Map(msg.Topic)
+	if !ok {
+		l.sugar.Errorw("Unknown event name", "event", msg.Topic)
+
+		// unknown events would be redelivered forever
+		if err := msg.Ack(ctx); err != nil {
+			l.sugar.Errorw("Failed to acknowledge event", "event", msg.Topic, "error", err)
+		}
+		return
+	}
+
+	// errors are reported by middlewares, failed events are rejected to be redelivered
+	if err := h.Handle(ctx, msg.Body); err != nil {
+		if err := msg.Nack(ctx); err != nil {
+			l.sugar.Errorw("Failed to reject event", "event", msg.Topic, "error", err)
+		}
+		return
+	}
+
+	if err := msg.Ack(ctx); err != nil {
+		l.sugar.Errorw("Failed to acknowledge event", "event", msg.Topic, "error", err)
+	}
+}
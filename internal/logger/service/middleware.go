@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/pubsub"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// log the result of each handler
+func (l *LoggerService) logging() pubsub.Middleware {
+	return func(topic string, next pubsub.Handler) pubsub.Handler {
+		return pubsub.HandlerFunc(func(ctx context.Context, body []byte) error {
+			start := time.Now()
+
+			err := next.Handle(ctx, body)
+			if err != nil {
+				l.sugar.Errorw("Failed to handle event", "event", topic, "error", err, "duration", time.Since(start).String())
+				return err
+			}
+
+			l.sugar.Infow("Successfully handled event", "event", topic, "duration", time.Since(start).String())
+			return nil
+		})
+	}
+}
+
+// collect event count, error count and response time of each handler
+func metrics() pubsub.Middleware {
+	return func(topic string, next pubsub.Handler) pubsub.Handler {
+		return pubsub.HandlerFunc(func(ctx context.Context, body []byte) error {
+			totalEvents.WithLabelValues(topic).Inc()
+
+			timer := prometheus.NewTimer(duration.WithLabelValues(topic))
+			defer timer.ObserveDuration()
+
+			err := next.Handle(ctx, body)
+			if err != nil {
+				totalErrors.WithLabelValues(topic).Inc()
+			}
+
+			return err
+		})
+	}
+}
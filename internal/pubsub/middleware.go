@@ -0,0 +1,24 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+)
+
+// Middleware wraps a handler registered for the topic
+type Middleware func(topic string, next Handler) Handler
+
+// Recovery converts a panic in the handler into an error
+func Recovery() Middleware {
+	return func(topic string, next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, body []byte) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic recovered while handling %s: %v", topic, r)
+				}
+			}()
+
+			return next.Handle(ctx, body)
+		})
+	}
+}
@@ -2,6 +2,9 @@ package pubsub
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync"
 )
 
 type Publisher interface {
@@ -12,35 +15,204 @@ type Subscriber interface {
 	Subscribe(topics ...string) (<-chan Message, <-chan error, func(), error)
 }
 
+// Mapper routes a topic to the handlers registered with matching patterns.
+//
+// Patterns follow AMQP topic semantics: words are separated by '.',
+// '*' matches exactly one word and '#' matches zero or more words.
 type Mapper interface {
-	Register(topic string, handler Handler)
+	Register(pattern string, handlers ...Handler)
+	Use(middlewares ...Middleware)
 	Map(topic string) (Handler, bool)
+	Topics() []string
 }
 
 type Handler interface {
 	Handle(ctx context.Context, body []byte) error
 }
 
+// HandlerFunc adapts a function to Handler
+type HandlerFunc func(ctx context.Context, body []byte) error
+
+func (f HandlerFunc) Handle(ctx context.Context, body []byte) error {
+	return f(ctx, body)
+}
+
+// Consumer registers its handlers to the mapper
+type Consumer interface {
+	Register(m Mapper)
+}
+
 type Message struct {
 	Topic string
 	Body  []byte
+
+	ack  func(ctx context.Context) error
+	nack func(ctx context.Context) error
+}
+
+// message acknowledged by the given function
+func NewMessage(topic string, body []byte, ack func(ctx context.Context) error) Message {
+	return Message{
+		Topic: topic,
+		Body:  body,
+		ack:   ack,
+	}
+}
+
+// message with the function rejecting it when the handler fails
+func (m Message) WithNack(nack func(ctx context.Context) error) Message {
+	m.nack = nack
+	return m
+}
+
+// acknowledge the message after it is handled, unacknowledged messages are redelivered
+func (m Message) Ack(ctx context.Context) error {
+	if m.ack == nil {
+		return nil
+	}
+	return m.ack(ctx)
+}
+
+// reject the message after the handler fails, messages without nack are redelivered by the broker
+func (m Message) Nack(ctx context.Context) error {
+	if m.nack == nil {
+		return nil
+	}
+	return m.nack(ctx)
+}
+
+type route struct {
+	pattern  string
+	handlers []Handler
 }
 
 type mapper struct {
-	handlers map[string]Handler
+	mtx         *sync.RWMutex
+	routes      []*route
+	middlewares []Middleware
 }
 
 func NewMapper() Mapper {
 	return &mapper{
-		handlers: make(map[string]Handler),
+		mtx:    &sync.RWMutex{},
+		routes: []*route{},
+	}
+}
+
+func (m *mapper) Register(pattern string, handlers ...Handler) {
+	defer m.mtx.Unlock()
+	m.mtx.Lock()
+
+	for _, r := range m.routes {
+		if r.pattern == pattern {
+			r.handlers = append(r.handlers, handlers...)
+			return
+		}
 	}
+
+	m.routes = append(m.routes, &route{
+		pattern:  pattern,
+		handlers: handlers,
+	})
 }
 
-func (m *mapper) Register(topic string, handler Handler) {
-	m.handlers[topic] = handler
+// middlewares are applied to each handler, the first one is the outermost
+func (m *mapper) Use(middlewares ...Middleware) {
+	defer m.mtx.Unlock()
+	m.mtx.Lock()
+
+	m.middlewares = append(m.middlewares, middlewares...)
 }
 
 func (m *mapper) Map(topic string) (Handler, bool) {
-	handler, ok := m.handlers[topic]
-	return handler, ok
+	defer m.mtx.RUnlock()
+	m.mtx.RLock()
+
+	var handlers []Handler
+
+	for _, r := range m.routes {
+		if !MatchTopic(r.pattern, topic) {
+			continue
+		}
+
+		for _, h := range r.handlers {
+			handlers = append(handlers, m.wrap(topic, h))
+		}
+	}
+
+	switch len(handlers) {
+	case 0:
+		return nil, false
+	case 1:
+		return handlers[0], true
+	default:
+		return fanOut(handlers), true
+	}
+}
+
+// registered patterns in registration order
+func (m *mapper) Topics() []string {
+	defer m.mtx.RUnlock()
+	m.mtx.RLock()
+
+	topics := make([]string, 0, len(m.routes))
+
+	for _, r := range m.routes {
+		topics = append(topics, r.pattern)
+	}
+
+	return topics
+}
+
+func (m *mapper) wrap(topic string, h Handler) Handler {
+	for i := len(m.middlewares) - 1; i >= 0; i-- {
+		h = m.middlewares[i](topic, h)
+	}
+	return h
+}
+
+// fanOut calls every handler even if some of them fail
+type fanOut []Handler
+
+func (f fanOut) Handle(ctx context.Context, body []byte) error {
+	var errs []error
+
+	for _, h := range f {
+		if err := h.Handle(ctx, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// check if the topic matches the pattern
+func MatchTopic(pattern, topic string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(topic, "."))
+}
+
+// check if the pattern contains any wildcard
+func IsPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*#")
+}
+
+func matchWords(pattern, topic []string) bool {
+	if len(pattern) == 0 {
+		return len(topic) == 0
+	}
+
+	switch pattern[0] {
+	case "#":
+		// '#' consumes zero or more words
+		for i := 0; i <= len(topic); i++ {
+			if matchWords(pattern[1:], topic[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(topic) > 0 && matchWords(pattern[1:], topic[1:])
+	default:
+		return len(topic) > 0 && pattern[0] == topic[0] && matchWords(pattern[1:], topic[1:])
+	}
 }
@@ -0,0 +1,139 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{"study.round.created", "study.round.created", true},
+		{"study.round.created", "study.round.finished", false},
+		{"study.*.created", "study.round.created", true},
+		{"study.*", "study.round.created", false},
+		{"study.*.*", "study.round", false},
+		{"study.#", "study.round.created", true},
+		{"study.#", "study", true}, // '#' matches zero words
+		{"#", "study.round.created", true},
+		{"#.created", "study.round.created", true},
+		{"#.created", "created", true},
+		{"study.#.created", "study.created", true},
+		{"study.#.created", "study.round.progress", false},
+		{"*.round.#", "study.round", true},
+		{"*", "", true}, // single empty word
+	}
+
+	for _, tt := range tests {
+		if got := MatchTopic(tt.pattern, tt.topic); got != tt.want {
+			t.Errorf("MatchTopic(%q, %q) = %v, want %v", tt.pattern, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestMapperFanOutIsolatesErrors(t *testing.T) {
+	m := NewMapper()
+
+	errFirst := errors.New("first failed")
+	called := []string{}
+
+	m.Register("study.#", HandlerFunc(func(context.Context, []byte) error {
+		called = append(called, "first")
+		return errFirst
+	}))
+	m.Register("study.round.created", HandlerFunc(func(context.Context, []byte) error {
+		called = append(called, "second")
+		return nil
+	}))
+
+	h, ok := m.Map("study.round.created")
+	if !ok {
+		t.Fatal("expected handler")
+	}
+
+	err := h.Handle(context.Background(), nil)
+	if !errors.Is(err, errFirst) {
+		t.Fatalf("expected error of the first handler, got %v", err)
+	}
+
+	if strings.Join(called, ",") != "first,second" {
+		t.Fatalf("expected every handler to be called, got %v", called)
+	}
+
+	if _, ok := m.Map("other.topic"); ok {
+		t.Fatal("expected no handler for unmatched topic")
+	}
+}
+
+func TestMapperMiddlewareOrder(t *testing.T) {
+	m := NewMapper()
+
+	var order []string
+
+	mw := func(name string) Middleware {
+		return func(topic string, next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, body []byte) error {
+				order = append(order, name+":"+topic)
+				return next.Handle(ctx, body)
+			})
+		}
+	}
+
+	m.Use(mw("outer"), mw("inner"))
+	m.Register("study.#", HandlerFunc(func(context.Context, []byte) error {
+		order = append(order, "handler")
+		return nil
+	}))
+
+	h, _ := m.Map("study.round.created")
+
+	if err := h.Handle(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "outer:study.round.created,inner:study.round.created,handler"
+	if got := strings.Join(order, ","); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	h := Recovery()("study.round.created", HandlerFunc(func(context.Context, []byte) error {
+		panic("boom")
+	}))
+
+	if err := h.Handle(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected recovered error, got %v", err)
+	}
+}
+
+func TestMessageNack(t *testing.T) {
+	acked, nacked := 0, 0
+
+	msg := NewMessage("topic", nil, func(context.Context) error {
+		acked++
+		return nil
+	})
+
+	// messages without nack are left to the broker
+	if err := msg.Nack(context.Background()); err != nil || nacked != 0 {
+		t.Fatalf("unexpected nack: %v", err)
+	}
+
+	msg = msg.WithNack(func(context.Context) error {
+		nacked++
+		return nil
+	})
+
+	_ = msg.Nack(context.Background())
+	_ = msg.Ack(context.Background())
+
+	if acked != 1 || nacked != 1 {
+		t.Fatalf("expected one ack and one nack, got %d and %d", acked, nacked)
+	}
+}
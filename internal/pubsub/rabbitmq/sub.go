@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"context"
 	"errors"
 
 	"github.com/piatoss3612/my-study-bot/internal/pubsub"
@@ -83,12 +84,13 @@ func (s *subscriber) handleMessage(delivery <-chan amqp.Delivery, msgs chan<- pu
 			continue
 		}
 
-		msg := pubsub.Message{
-			Topic: topic.(string),
-			Body:  d.Body,
-		}
+		d := d
 
-		msgs <- msg
-		_ = d.Ack(false)
+		msgs <- pubsub.NewMessage(topic.(string), d.Body, func(context.Context) error {
+			return d.Ack(false)
+		}).WithNack(func(context.Context) error {
+			// failed message is requeued once, then dead-lettered or dropped by the queue
+			return d.Nack(false, !d.Redelivered)
+		})
 	}
 }
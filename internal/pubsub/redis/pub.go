@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/piatoss3612/my-study-bot/internal/pubsub"
+)
+
+const (
+	XEventTopicField = "topic"
+	XEventBodyField  = "body"
+)
+
+type PublisherOptsFunc func(*publisher)
+
+// cap the length of each stream approximately to n entries
+func WithMaxLen(n int64) PublisherOptsFunc {
+	return func(p *publisher) {
+		p.maxLen = n
+	}
+}
+
+type publisher struct {
+	client *goredis.Client
+	stream string
+	maxLen int64
+}
+
+func NewPublisher(client *goredis.Client, stream string, opts ...PublisherOptsFunc) (pubsub.Publisher, error) {
+	pub := &publisher{
+		client: client,
+		stream: stream,
+	}
+
+	for _, opt := range opts {
+		opt(pub)
+	}
+
+	return pub.setup()
+}
+
+func (p *publisher) setup() (pubsub.Publisher, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := p.client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *publisher) Publish(ctx context.Context, k string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	args := &goredis.XAddArgs{
+		Stream: streamKey(p.stream, k),
+		Values: map[string]interface{}{
+			XEventTopicField: k,
+			XEventBodyField:  body,
+		},
+	}
+
+	if p.maxLen > 0 {
+		args.MaxLen = p.maxLen
+		args.Approx = true
+	}
+
+	return p.client.XAdd(ctx, args).Err()
+}
+
+// each topic is stored in its own stream prefixed with the stream name
+func streamKey(stream, topic string) string {
+	return fmt.Sprintf("%s:%s", stream, topic)
+}
@@ -0,0 +1,412 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/piatoss3612/my-study-bot/internal/pubsub"
+)
+
+var (
+	ErrMissingXEventTopicField = errors.New("missing topic field")
+	ErrMissingXEventBodyField  = errors.New("missing body field")
+)
+
+var (
+	defaultTimeout              = 5 * time.Second
+	defaultBlock                = 2 * time.Second
+	defaultMinIdle              = 1 * time.Minute
+	defaultClaimInterval        = 30 * time.Second
+	defaultRescanInterval       = 10 * time.Second
+	defaultCount          int64 = 10
+)
+
+type SubscriberOptsFunc func(*subscriber)
+
+// block duration of a single XREADGROUP call
+func WithBlock(d time.Duration) SubscriberOptsFunc {
+	return func(s *subscriber) {
+		s.block = d
+	}
+}
+
+// idle time after which pending entries of other consumers are reclaimed
+func WithMinIdle(d time.Duration) SubscriberOptsFunc {
+	return func(s *subscriber) {
+		s.minIdle = d
+	}
+}
+
+// interval between XAUTOCLAIM runs
+func WithClaimInterval(d time.Duration) SubscriberOptsFunc {
+	return func(s *subscriber) {
+		s.claimInterval = d
+	}
+}
+
+// interval between scans for new streams matching the patterns
+func WithRescanInterval(d time.Duration) SubscriberOptsFunc {
+	return func(s *subscriber) {
+		s.rescanInterval = d
+	}
+}
+
+type subscriber struct {
+	client   *goredis.Client
+	stream   string
+	group    string
+	consumer string
+
+	block          time.Duration
+	minIdle        time.Duration
+	claimInterval  time.Duration
+	rescanInterval time.Duration
+	count          int64
+}
+
+func NewSubscriber(client *goredis.Client, stream, group, consumer string, opts ...SubscriberOptsFunc) (pubsub.Subscriber, error) {
+	sub := &subscriber{
+		client:         client,
+		stream:         stream,
+		group:          group,
+		consumer:       consumer,
+		block:          defaultBlock,
+		minIdle:        defaultMinIdle,
+		claimInterval:  defaultClaimInterval,
+		rescanInterval: defaultRescanInterval,
+		count:          defaultCount,
+	}
+
+	for _, opt := range opts {
+		opt(sub)
+	}
+
+	return sub.setup()
+}
+
+func (s *subscriber) setup() (pubsub.Subscriber, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := s.client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *subscriber) Subscribe(topics ...string) (<-chan pubsub.Message, <-chan error, func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	keys, err := s.resolveKeys(ctx, topics)
+	if err != nil {
+		cancel()
+		return nil, nil, nil, err
+	}
+
+	if err := s.createGroups(ctx, keys); err != nil {
+		cancel()
+		return nil, nil, nil, err
+	}
+
+	msgs := make(chan pubsub.Message)
+	errs := make(chan error)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		s.handleMessage(ctx, topics, keys, msgs, errs)
+	}()
+
+	return msgs, errs, func() {
+		cancel()
+		wg.Wait()
+		close(msgs)
+		close(errs)
+	}, nil
+}
+
+// create consumer groups reading from the beginning, streams are created if not exist
+func (s *subscriber) createGroups(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		err := s.client.XGroupCreateMkStream(ctx, key, s.group, "0").Err()
+		if err != nil && !isBusyGroupError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve topics to stream keys, patterns are matched against existing streams
+// and rescanned periodically while subscribing
+func (s *subscriber) resolveKeys(ctx context.Context, topics []string) ([]string, error) {
+	keys := make([]string, 0, len(topics))
+	seen := map[string]bool{}
+
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	prefix := streamKey(s.stream, "")
+
+	for _, topic := range topics {
+		if !pubsub.IsPattern(topic) {
+			add(streamKey(s.stream, topic))
+			continue
+		}
+
+		iter := s.client.Scan(ctx, 0, prefix+"*", 0).Iterator()
+
+		for iter.Next(ctx) {
+			key := iter.Val()
+
+			if pubsub.MatchTopic(topic, strings.TrimPrefix(key, prefix)) {
+				add(key)
+			}
+		}
+
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *subscriber) handleMessage(ctx context.Context, topics, keys []string, msgs chan<- pubsub.Message, errs chan<- error) {
+	streams := readStreams(keys)
+
+	hasPattern := false
+
+	for _, topic := range topics {
+		if pubsub.IsPattern(topic) {
+			hasPattern = true
+		}
+	}
+
+	var lastClaim time.Time
+	lastScan := time.Now()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// streams created after subscription are added if they match the patterns
+		if hasPattern && time.Since(lastScan) >= s.rescanInterval {
+			if added, err := s.rescan(ctx, topics, keys); err != nil {
+				s.sendError(ctx, errs, err)
+			} else if len(added) > 0 {
+				keys = append(keys, added...)
+				streams = readStreams(keys)
+			}
+			lastScan = time.Now()
+		}
+
+		// reclaim entries left pending by dead consumers
+		if time.Since(lastClaim) >= s.claimInterval {
+			for _, key := range keys {
+				s.reclaim(ctx, key, msgs, errs)
+			}
+			lastClaim = time.Now()
+		}
+
+		// patterns may match no stream until one is created
+		if len(keys) == 0 {
+			select {
+			case <-time.After(s.block):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		res, err := s.client.XReadGroup(ctx, &goredis.XReadGroupArgs{
+			Group:    s.group,
+			Consumer: s.consumer,
+			Streams:  streams,
+			Count:    s.count,
+			Block:    s.block,
+		}).Result()
+		if err != nil {
+			if err == goredis.Nil || ctx.Err() != nil {
+				continue
+			}
+
+			s.sendError(ctx, errs, err)
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+
+		for _, stream := range res {
+			for _, m := range stream.Messages {
+				s.deliver(ctx, stream.Stream, m, msgs, errs)
+			}
+		}
+	}
+}
+
+// new stream keys matching the topics with consumer groups created
+func (s *subscriber) rescan(ctx context.Context, topics, keys []string) ([]string, error) {
+	resolved, err := s.resolveKeys(ctx, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		known[key] = true
+	}
+
+	var added []string
+
+	for _, key := range resolved {
+		if !known[key] {
+			added = append(added, key)
+		}
+	}
+
+	if err := s.createGroups(ctx, added); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// arguments of XREADGROUP reading only new entries of the keys
+func readStreams(keys []string) []string {
+	streams := make([]string, 0, len(keys)*2)
+	streams = append(streams, keys...)
+
+	for range keys {
+		streams = append(streams, ">")
+	}
+
+	return streams
+}
+
+func (s *subscriber) reclaim(ctx context.Context, key string, msgs chan<- pubsub.Message, errs chan<- error) {
+	start := "0-0"
+
+	for {
+		claimed, next, err := s.autoClaim(ctx, key, start)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.sendError(ctx, errs, err)
+			}
+			return
+		}
+
+		for _, m := range claimed {
+			s.deliver(ctx, key, m, msgs, errs)
+		}
+
+		// scan is completed when the cursor returns to 0-0
+		if next == "0-0" || next == "" {
+			return
+		}
+
+		start = next
+	}
+}
+
+// XAUTOCLAIM is sent as a raw command since the reply of redis 7 has a third element
+// of deleted ids which the client of v8 fails to parse
+func (s *subscriber) autoClaim(ctx context.Context, key, start string) ([]goredis.XMessage, string, error) {
+	res, err := s.client.Do(ctx, "XAUTOCLAIM", key, s.group, s.consumer,
+		s.minIdle.Milliseconds(), start, "COUNT", s.count).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	reply, ok := res.([]interface{})
+	if !ok || len(reply) < 2 {
+		return nil, "", fmt.Errorf("unexpected XAUTOCLAIM reply: %v", res)
+	}
+
+	next, _ := reply[0].(string)
+
+	entries, _ := reply[1].([]interface{})
+
+	claimed := make([]goredis.XMessage, 0, len(entries))
+
+	for _, e := range entries {
+		// entries deleted while pending are nil in redis 6.2
+		fields, ok := e.([]interface{})
+		if !ok || len(fields) != 2 {
+			continue
+		}
+
+		id, _ := fields[0].(string)
+		values, _ := fields[1].([]interface{})
+
+		m := goredis.XMessage{ID: id, Values: make(map[string]interface{}, len(values)/2)}
+
+		for i := 0; i+1 < len(values); i += 2 {
+			if k, ok := values[i].(string); ok {
+				m.Values[k] = values[i+1]
+			}
+		}
+
+		claimed = append(claimed, m)
+	}
+
+	return claimed, next, nil
+}
+
+// deliver the entry to subscriber, it is acknowledged only after handled
+func (s *subscriber) deliver(ctx context.Context, key string, m goredis.XMessage, msgs chan<- pubsub.Message, errs chan<- error) {
+	topic, ok := m.Values[XEventTopicField].(string)
+	if !ok {
+		s.sendError(ctx, errs, ErrMissingXEventTopicField)
+		s.ack(ctx, key, m.ID, errs) // drop malformed entry
+		return
+	}
+
+	body, ok := m.Values[XEventBodyField].(string)
+	if !ok {
+		s.sendError(ctx, errs, ErrMissingXEventBodyField)
+		s.ack(ctx, key, m.ID, errs)
+		return
+	}
+
+	// entry left pending is reclaimed later if the handler fails
+	msg := pubsub.NewMessage(topic, []byte(body), func(ctx context.Context) error {
+		return s.client.XAck(ctx, key, s.group, m.ID).Err()
+	})
+
+	select {
+	case msgs <- msg:
+	case <-ctx.Done():
+	}
+}
+
+func (s *subscriber) ack(ctx context.Context, key, id string, errs chan<- error) {
+	// acknowledge even if the subscription is being closed
+	ackCtx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := s.client.XAck(ackCtx, key, s.group, id).Err(); err != nil {
+		s.sendError(ctx, errs, err)
+	}
+}
+
+func (s *subscriber) sendError(ctx context.Context, errs chan<- error, err error) {
+	select {
+	case errs <- err:
+	case <-ctx.Done():
+	}
+}
+
+func isBusyGroupError(err error) bool {
+	return strings.HasPrefix(err.Error(), "BUSYGROUP")
+}
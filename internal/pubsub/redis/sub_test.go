@@ -0,0 +1,240 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/piatoss3612/my-study-bot/internal/pubsub"
+)
+
+func newTestClient(t *testing.T) *goredis.Client {
+	t.Helper()
+
+	srv := miniredis.RunT(t)
+
+	client := goredis.NewClient(&goredis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func receive(t *testing.T, msgs <-chan pubsub.Message) pubsub.Message {
+	t.Helper()
+
+	select {
+	case msg := <-msgs:
+		return msg
+	case <-time.After(3 * time.Second):
+		t.Fatal("no message received")
+		return pubsub.Message{}
+	}
+}
+
+func pendingCount(t *testing.T, client *goredis.Client, key string) int64 {
+	t.Helper()
+
+	p, err := client.XPending(context.Background(), key, "logger").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p.Count
+}
+
+func TestSubscribeCreatesConsumerGroup(t *testing.T) {
+	client := newTestClient(t)
+
+	sub, err := NewSubscriber(client, "events", "logger", "c1", WithBlock(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, closeFn, err := sub.Subscribe("study.round.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFn()
+
+	// creating the same group again fails if the group exists
+	err = client.XGroupCreate(context.Background(), streamKey("events", "study.round.created"), "logger", "0").Err()
+	if err == nil || !isBusyGroupError(err) {
+		t.Fatalf("expected group logger to exist, got %v", err)
+	}
+
+	// subscribing again with the existing group is not an error
+	_, _, closeAgain, err := sub.Subscribe("study.round.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeAgain()
+}
+
+func TestMessageIsAckedOnlyAfterAck(t *testing.T) {
+	client := newTestClient(t)
+
+	pub, err := NewPublisher(client, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := NewSubscriber(client, "events", "logger", "c1", WithBlock(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, _, closeFn, err := sub.Subscribe("study.round.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFn()
+
+	if err := pub.Publish(context.Background(), "study.round.created", map[string]string{"a": "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := receive(t, msgs)
+
+	key := streamKey("events", "study.round.created")
+
+	if n := pendingCount(t, client, key); n != 1 {
+		t.Fatalf("expected entry pending before ack, got %d", n)
+	}
+
+	if err := msg.Ack(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := pendingCount(t, client, key); n != 0 {
+		t.Fatalf("expected no pending entry after ack, got %d", n)
+	}
+}
+
+func TestReclaimPendingEntries(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	key := streamKey("events", "study.round.created")
+
+	if err := client.XGroupCreateMkStream(ctx, key, "logger", "0").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.XAdd(ctx, &goredis.XAddArgs{
+		Stream: key,
+		Values: map[string]interface{}{XEventTopicField: "study.round.created", XEventBodyField: "{}"},
+	}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// dead consumer reads the entry without acknowledging it
+	if err := client.XReadGroup(ctx, &goredis.XReadGroupArgs{
+		Group:    "logger",
+		Consumer: "dead",
+		Streams:  []string{key, ">"},
+		Count:    1,
+	}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := NewSubscriber(client, "events", "logger", "c1", WithBlock(50*time.Millisecond), WithMinIdle(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, _, closeFn, err := sub.Subscribe("study.round.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFn()
+
+	msg := receive(t, msgs)
+	if msg.Topic != "study.round.created" {
+		t.Fatalf("unexpected topic %s", msg.Topic)
+	}
+
+	if err := msg.Ack(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := pendingCount(t, client, key); n != 0 {
+		t.Fatalf("expected reclaimed entry to be acked, got %d pending", n)
+	}
+}
+
+func TestMalformedEntryIsAcked(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	sub, err := NewSubscriber(client, "events", "logger", "c1", WithBlock(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, errs, closeFn, err := sub.Subscribe("study.round.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFn()
+
+	key := streamKey("events", "study.round.created")
+
+	if err := client.XAdd(ctx, &goredis.XAddArgs{
+		Stream: key,
+		Values: map[string]interface{}{XEventBodyField: "{}"},
+	}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrMissingXEventTopicField) {
+			t.Fatalf("expected ErrMissingXEventTopicField, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no error received")
+	}
+
+	// ack is sent right after the error
+	deadline := time.Now().Add(time.Second)
+	for pendingCount(t, client, key) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("malformed entry is not acked")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPatternSubscriptionFindsNewStreams(t *testing.T) {
+	client := newTestClient(t)
+
+	pub, err := NewPublisher(client, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := NewSubscriber(client, "events", "logger", "c1",
+		WithBlock(20*time.Millisecond), WithRescanInterval(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no stream matches the pattern at the time of subscription
+	msgs, _, closeFn, err := sub.Subscribe("study.#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFn()
+
+	if err := pub.Publish(context.Background(), "study.round.created", map[string]string{"a": "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := receive(t, msgs)
+	if msg.Topic != "study.round.created" {
+		t.Fatalf("unexpected topic %s", msg.Topic)
+	}
+}
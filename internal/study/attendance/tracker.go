@@ -0,0 +1,186 @@
+package attendance
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// Tracker accumulates time spent by members in the presentation channel of each guild
+type Tracker interface {
+	// start tracking the round, tracking of the previous round is dropped
+	Start(guildID, roundID string)
+	// whether the round of the guild is being tracked
+	Tracking(guildID, roundID string) bool
+	// start tracking the round unless it is being tracked, report whether it is started by the call
+	StartIfNotTracking(guildID, roundID string) bool
+	Join(guildID, roundID, userID string, at time.Time)
+	Leave(guildID, roundID, userID string, at time.Time)
+	// stop tracking the round and return time spent by each member
+	Finish(guildID, roundID string, at time.Time) map[string]time.Duration
+}
+
+type session struct {
+	roundID string
+	joined  map[string]time.Time // user id to time joined the channel
+	spent   map[string]time.Duration
+}
+
+type tracker struct {
+	mtx      *sync.Mutex
+	sessions map[string]*session // guild id to session
+}
+
+func NewTracker() Tracker {
+	return &tracker{
+		mtx:      &sync.Mutex{},
+		sessions: map[string]*session{},
+	}
+}
+
+func (t *tracker) Start(guildID, roundID string) {
+	defer t.mtx.Unlock()
+	t.mtx.Lock()
+
+	t.start(guildID, roundID)
+}
+
+func (t *tracker) Tracking(guildID, roundID string) bool {
+	defer t.mtx.Unlock()
+	t.mtx.Lock()
+
+	sess, ok := t.sessions[guildID]
+	return ok && sess.roundID == roundID
+}
+
+func (t *tracker) StartIfNotTracking(guildID, roundID string) bool {
+	defer t.mtx.Unlock()
+	t.mtx.Lock()
+
+	if sess, ok := t.sessions[guildID]; ok && sess.roundID == roundID {
+		return false
+	}
+
+	t.start(guildID, roundID)
+
+	return true
+}
+
+func (t *tracker) Join(guildID, roundID, userID string, at time.Time) {
+	defer t.mtx.Unlock()
+	t.mtx.Lock()
+
+	sess, ok := t.sessions[guildID]
+	if !ok || sess.roundID != roundID {
+		sess = t.start(guildID, roundID)
+	}
+
+	// already in the channel
+	if _, ok := sess.joined[userID]; ok {
+		return
+	}
+
+	sess.joined[userID] = at
+}
+
+func (t *tracker) Leave(guildID, roundID, userID string, at time.Time) {
+	defer t.mtx.Unlock()
+	t.mtx.Lock()
+
+	sess, ok := t.sessions[guildID]
+	if !ok || sess.roundID != roundID {
+		return
+	}
+
+	sess.leave(userID, at)
+}
+
+func (t *tracker) Finish(guildID, roundID string, at time.Time) map[string]time.Duration {
+	defer t.mtx.Unlock()
+	t.mtx.Lock()
+
+	sess, ok := t.sessions[guildID]
+	if !ok || sess.roundID != roundID {
+		return map[string]time.Duration{}
+	}
+
+	for userID := range sess.joined {
+		sess.leave(userID, at)
+	}
+
+	delete(t.sessions, guildID)
+
+	return sess.spent
+}
+
+func (t *tracker) start(guildID, roundID string) *session {
+	sess := &session{
+		roundID: roundID,
+		joined:  map[string]time.Time{},
+		spent:   map[string]time.Duration{},
+	}
+
+	t.sessions[guildID] = sess
+
+	return sess
+}
+
+func (s *session) leave(userID string, at time.Time) {
+	joined, ok := s.joined[userID]
+	if !ok {
+		return
+	}
+
+	delete(s.joined, userID)
+
+	if at.After(joined) {
+		s.spent[userID] += at.Sub(joined)
+	}
+}
+
+// Result is the attendance of a registered speaker
+type Result struct {
+	MemberID  string
+	Name      string
+	Spent     time.Duration
+	Attended  bool // attendance is already confirmed
+	Qualified bool // spent time is above the threshold
+}
+
+// attendance of registered speakers in ascending order of member id
+func Review(r study.Round, spent map[string]time.Duration, threshold time.Duration) []Result {
+	results := []Result{}
+
+	for id, m := range r.Members {
+		if !m.IsRegistered() {
+			continue
+		}
+
+		results = append(results, Result{
+			MemberID:  id,
+			Name:      m.Name,
+			Spent:     spent[id],
+			Attended:  m.Attended,
+			Qualified: spent[id] >= threshold,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].MemberID < results[j].MemberID })
+
+	return results
+}
+
+// members to be confirmed, qualified but not attended yet
+func Proposed(results []Result) []string {
+	var ids []string
+
+	for _, r := range results {
+		if r.Qualified && !r.Attended {
+			ids = append(ids, r.MemberID)
+		}
+	}
+
+	return ids
+}
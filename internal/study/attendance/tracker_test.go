@@ -0,0 +1,144 @@
+package attendance
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+	start := time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC)
+
+	tr.Start("guild", "round")
+
+	if !tr.Tracking("guild", "round") || tr.Tracking("guild", "other") {
+		t.Fatal("expected only the started round to be tracked")
+	}
+
+	tr.Join("guild", "round", "u1", start)
+	tr.Join("guild", "round", "u1", start.Add(5*time.Minute)) // already joined
+	tr.Leave("guild", "round", "u1", start.Add(10*time.Minute))
+	tr.Join("guild", "round", "u1", start.Add(20*time.Minute))
+
+	tr.Join("guild", "round", "u2", start.Add(30*time.Minute))
+	tr.Leave("guild", "round", "u3", start.Add(30*time.Minute)) // never joined
+
+	spent := tr.Finish("guild", "round", start.Add(40*time.Minute))
+
+	want := map[string]time.Duration{
+		"u1": 30 * time.Minute,
+		"u2": 10 * time.Minute,
+	}
+
+	if !reflect.DeepEqual(spent, want) {
+		t.Fatalf("expected %v, got %v", want, spent)
+	}
+
+	if tr.Tracking("guild", "round") {
+		t.Fatal("expected tracking to be finished")
+	}
+}
+
+func TestTrackerStartIfNotTracking(t *testing.T) {
+	tr := NewTracker()
+	start := time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC)
+
+	if !tr.StartIfNotTracking("guild", "round") {
+		t.Fatal("expected tracking to be started")
+	}
+
+	tr.Join("guild", "round", "u1", start)
+
+	// tracking of the same round is kept
+	if tr.StartIfNotTracking("guild", "round") {
+		t.Fatal("expected tracking not to be started again")
+	}
+
+	if spent := tr.Finish("guild", "round", start.Add(time.Minute)); spent["u1"] != time.Minute {
+		t.Fatalf("expected joined member to be kept, got %v", spent)
+	}
+
+	// only one of concurrent calls starts tracking
+	var started int32
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if tr.StartIfNotTracking("guild", "next") {
+				atomic.AddInt32(&started, 1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if started != 1 {
+		t.Fatalf("expected tracking to be started once, got %d", started)
+	}
+}
+
+func TestTrackerNewRound(t *testing.T) {
+	tr := NewTracker()
+	start := time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC)
+
+	tr.Join("guild", "r1", "u1", start)
+
+	// joining another round drops the previous one
+	tr.Join("guild", "r2", "u2", start)
+
+	if spent := tr.Finish("guild", "r1", start.Add(time.Hour)); len(spent) != 0 {
+		t.Fatalf("expected previous round to be dropped, got %v", spent)
+	}
+
+	if spent := tr.Finish("guild", "r2", start.Add(time.Hour)); spent["u2"] != time.Hour {
+		t.Fatalf("expected an hour spent, got %v", spent)
+	}
+}
+
+func TestReview(t *testing.T) {
+	r := study.NewRound()
+
+	for _, m := range []struct {
+		id         string
+		registered bool
+		attended   bool
+	}{
+		{"u1", true, false},
+		{"u2", true, true},
+		{"u3", true, false},
+		{"u4", false, false},
+	} {
+		member := study.NewMember()
+		member.SetName(m.id)
+		member.SetRegistered(m.registered)
+		member.SetAttended(m.attended)
+		r.SetMember(m.id, member)
+	}
+
+	results := Review(r, map[string]time.Duration{
+		"u1": 15 * time.Minute,
+		"u2": 20 * time.Minute,
+		"u3": 5 * time.Minute,
+		"u4": time.Hour,
+	}, 10*time.Minute)
+
+	if len(results) != 3 {
+		t.Fatalf("expected only registered speakers, got %+v", results)
+	}
+
+	if !results[0].Qualified || !results[1].Attended || results[2].Qualified {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	if proposed := Proposed(results); !reflect.DeepEqual(proposed, []string{"u1"}) {
+		t.Fatalf("expected u1 to be proposed, got %v", proposed)
+	}
+}
@@ -0,0 +1,101 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/repository"
+)
+
+// version of the archive layout, bumped on incompatible changes
+const ArchiveVersion = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported archive version")
+	ErrInvalidArchive     = errors.New("invalid archive")
+	ErrTargetExists       = errors.New("study already exists in the target guild")
+	ErrSurplusRounds      = errors.New("target guild has rounds not in the archive")
+)
+
+// Archive is a snapshot of the study and rounds of a guild
+type Archive struct {
+	Version   int           `json:"version"`
+	CreatedAt time.Time     `json:"created_at"`
+	GuildID   string        `json:"guild_id"`
+	Study     study.Study   `json:"study"`
+	Rounds    []study.Round `json:"rounds"`
+}
+
+// dump the study and rounds of the guild
+func Backup(ctx context.Context, q repository.Query, guildID string) (*Archive, error) {
+	s, err := q.FindStudy(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, study.ErrStudyNotFound
+	}
+
+	rounds, err := q.FindRounds(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Archive{
+		Version:   ArchiveVersion,
+		CreatedAt: time.Now(),
+		GuildID:   guildID,
+		Study:     *s,
+		Rounds:    make([]study.Round, 0, len(rounds)),
+	}
+
+	for _, r := range rounds {
+		a.Rounds = append(a.Rounds, *r)
+	}
+
+	sort.Slice(a.Rounds, func(i, j int) bool { return a.Rounds[i].Number < a.Rounds[j].Number })
+
+	return a, nil
+}
+
+func (a *Archive) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// read and validate archive
+func Read(r io.Reader) (*Archive, error) {
+	var a Archive
+
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return nil, errors.Join(ErrInvalidArchive, err)
+	}
+
+	if a.Version != ArchiveVersion {
+		return nil, errors.Join(ErrUnsupportedVersion, fmt.Errorf("version: %d, supported: %d", a.Version, ArchiveVersion))
+	}
+
+	if a.GuildID == "" || a.Study.GuildID != a.GuildID {
+		return nil, errors.Join(ErrInvalidArchive, errors.New("guild id of the study does not match"))
+	}
+
+	numbers := map[int8]bool{}
+
+	for _, r := range a.Rounds {
+		if numbers[r.Number] {
+			return nil, errors.Join(ErrInvalidArchive, fmt.Errorf("duplicated round number: %d", r.Number))
+		}
+
+		numbers[r.Number] = true
+	}
+
+	return &a, nil
+}
@@ -0,0 +1,364 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// in-memory repository.Tx
+type memoryTx struct {
+	studies map[string]study.Study // guild id to study
+	rounds  map[string]study.Round // round id to round
+	nextID  int
+}
+
+func newMemoryTx() *memoryTx {
+	return &memoryTx{
+		studies: map[string]study.Study{},
+		rounds:  map[string]study.Round{},
+	}
+}
+
+func (m *memoryTx) id() string {
+	m.nextID++
+	return fmt.Sprintf("id-%d", m.nextID)
+}
+
+func (m *memoryTx) FindStudy(_ context.Context, guildID string) (*study.Study, error) {
+	s, ok := m.studies[guildID]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (m *memoryTx) FindRound(_ context.Context, roundID string) (*study.Round, error) {
+	r, ok := m.rounds[roundID]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+func (m *memoryTx) FindRounds(_ context.Context, guildID string) ([]*study.Round, error) {
+	var rounds []*study.Round
+
+	for _, r := range m.rounds {
+		if r.GuildID == guildID {
+			r := r
+			rounds = append(rounds, &r)
+		}
+	}
+
+	return rounds, nil
+}
+
+func (m *memoryTx) CreateStudy(_ context.Context, s study.Study) (*study.Study, error) {
+	s.SetID(m.id())
+	m.studies[s.GuildID] = s
+	return &s, nil
+}
+
+func (m *memoryTx) UpdateStudy(_ context.Context, s study.Study) (*study.Study, error) {
+	m.studies[s.GuildID] = s
+	return &s, nil
+}
+
+func (m *memoryTx) CreateRound(_ context.Context, r study.Round) (*study.Round, error) {
+	r.SetID(m.id())
+	m.rounds[r.ID] = r
+	return &r, nil
+}
+
+func (m *memoryTx) UpdateRound(_ context.Context, r study.Round) (*study.Round, error) {
+	m.rounds[r.ID] = r
+	return &r, nil
+}
+
+func (m *memoryTx) ExecTx(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return fn(ctx)
+}
+
+func seed(t *testing.T, tx *memoryTx, guildID string, numbers ...int8) {
+	t.Helper()
+
+	s := study.New()
+	s.SetGuildID(guildID)
+	s.SetManagerID("manager")
+	s.SetNoticeChannelID("notice")
+	s.SetCurrentStage(study.StageWait)
+
+	for _, n := range numbers {
+		r := study.NewRound()
+		r.SetGuildID(guildID)
+		r.SetNumber(n)
+		r.SetTitle(fmt.Sprintf("round %d", n))
+
+		m := study.NewMember()
+		m.SetName("alice")
+		r.SetMember("100", m)
+
+		created, _ := tx.CreateRound(context.Background(), r)
+		s.SetOngoingRoundID(created.ID)
+		s.IncrementTotalRound()
+	}
+
+	if _, err := tx.CreateStudy(context.Background(), s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func backupArchive(t *testing.T, tx *memoryTx, guildID string) *Archive {
+	t.Helper()
+
+	a, err := Backup(context.Background(), tx, guildID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// round trip through json
+	buf := &bytes.Buffer{}
+	if err := a.Write(buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, err = Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return a
+}
+
+func TestRestoreToAnotherGuild(t *testing.T) {
+	tx := newMemoryTx()
+	seed(t, tx, "source", 1, 2)
+
+	a := backupArchive(t, tx, "source")
+
+	if len(a.Rounds) != 2 || a.Rounds[0].Number != 1 {
+		t.Fatalf("rounds = %+v", a.Rounds)
+	}
+
+	plan, err := Plan(context.Background(), tx, a, RestoreOptions{GuildID: "target", ManagerID: "new-manager"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// study, cleared notice channel and two rounds
+	if len(plan.Diff) != 4 {
+		t.Errorf("diff = %v, want 4 lines", plan.Diff)
+	}
+
+	// dry run does not write
+	if s, _ := tx.FindStudy(context.Background(), "target"); s != nil {
+		t.Fatal("study is written by plan")
+	}
+
+	s, err := Apply(context.Background(), tx, plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.ManagerID != "new-manager" || s.NoticeChannelID != "" {
+		t.Errorf("study = %+v", s)
+	}
+
+	// ongoing round points to the restored round
+	ongoing, _ := tx.FindRound(context.Background(), s.OngoingRoundID)
+	if ongoing == nil || ongoing.GuildID != "target" || ongoing.Number != 2 {
+		t.Errorf("ongoing round = %+v", ongoing)
+	}
+
+	rounds, _ := tx.FindRounds(context.Background(), "target")
+	if len(rounds) != 2 {
+		t.Errorf("rounds = %d, want 2", len(rounds))
+	}
+}
+
+func TestRestoreExistingGuild(t *testing.T) {
+	tx := newMemoryTx()
+	seed(t, tx, "guild", 1)
+
+	a := backupArchive(t, tx, "guild")
+	a.Rounds[0].SetTitle("edited")
+
+	if _, err := Plan(context.Background(), tx, a, RestoreOptions{}); !errors.Is(err, ErrTargetExists) {
+		t.Fatalf("err = %v, want %v", err, ErrTargetExists)
+	}
+
+	plan, err := Plan(context.Background(), tx, a, RestoreOptions{Overwrite: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.Diff) != 1 || !strings.Contains(plan.Diff[0], "edited") {
+		t.Errorf("diff = %v", plan.Diff)
+	}
+
+	if _, err := Apply(context.Background(), tx, plan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rounds, _ := tx.FindRounds(context.Background(), "guild")
+	if len(rounds) != 1 || rounds[0].Title != "edited" {
+		t.Errorf("rounds = %+v", rounds)
+	}
+}
+
+// set state of the study and its rounds bound to the guild
+func seedGuildState(t *testing.T, tx *memoryTx, guildID string) {
+	t.Helper()
+
+	s := tx.studies[guildID]
+	s.SetPresentationChannelID("stage")
+	s.SetCalendarToken("token")
+
+	poll := study.NewTopicPoll("notice", "poll")
+	s.SetTopicPoll(&poll)
+
+	w, err := study.NewWebhook("https://example.com/hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.AddWebhook(w)
+
+	tx.studies[guildID] = s
+
+	for id, r := range tx.rounds {
+		if r.GuildID != guildID {
+			continue
+		}
+
+		r.SetScheduledEventID("event")
+
+		m := r.Members["100"]
+		m.SetThreadID("thread")
+		r.SetMember("100", m)
+
+		tx.rounds[id] = r
+	}
+}
+
+func TestRestoreToAnotherGuildClearsGuildState(t *testing.T) {
+	tx := newMemoryTx()
+	seed(t, tx, "source", 1)
+	seedGuildState(t, tx, "source")
+
+	a := backupArchive(t, tx, "source")
+
+	plan, err := Plan(context.Background(), tx, a, RestoreOptions{GuildID: "target"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// dry run shows what is left behind
+	want := "- study notice channel, presentation channel, topic poll, webhooks, calendar token of the source guild"
+	if len(plan.Diff) != 3 || plan.Diff[1] != want {
+		t.Errorf("diff = %v", plan.Diff)
+	}
+
+	s, err := Apply(context.Background(), tx, plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.PresentationChannelID != "" || s.TopicPoll != nil || len(s.Webhooks) != 0 || s.CalendarToken != "" {
+		t.Errorf("state of the source guild is restored: %+v", s)
+	}
+
+	rounds, _ := tx.FindRounds(context.Background(), "target")
+	if len(rounds) != 1 || rounds[0].ScheduledEventID != "" || rounds[0].Members["100"].ThreadID != "" {
+		t.Errorf("rounds = %+v", rounds)
+	}
+
+	// archive is not modified
+	if a.Rounds[0].Members["100"].ThreadID != "thread" {
+		t.Error("thread of the archive is cleared")
+	}
+}
+
+func TestRestoreOverwriteShowsGuildStateDiff(t *testing.T) {
+	tx := newMemoryTx()
+	seed(t, tx, "source", 1)
+	seedGuildState(t, tx, "source")
+	seed(t, tx, "target", 1)
+
+	a := backupArchive(t, tx, "source")
+
+	plan, err := Plan(context.Background(), tx, a, RestoreOptions{GuildID: "target", Overwrite: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the target has no state bound to the guild, so only the notice channel is changed
+	if len(plan.Diff) != 1 || !strings.Contains(plan.Diff[0], "notice channel") {
+		t.Errorf("diff = %v", plan.Diff)
+	}
+
+	// restoring to the same guild shows the state of the archive
+	seedGuildState(t, tx, "target")
+
+	a = backupArchive(t, tx, "target")
+	s := tx.studies["target"]
+	s.SetCalendarToken("")
+	s.Webhooks = nil
+	tx.studies["target"] = s
+
+	for id, r := range tx.rounds {
+		if r.GuildID == "target" {
+			m := r.Members["100"]
+			m.SetThreadID("other")
+			r.SetMember("100", m)
+			tx.rounds[id] = r
+		}
+	}
+
+	plan, err = Plan(context.Background(), tx, a, RestoreOptions{Overwrite: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	diff := strings.Join(plan.Diff, "\n")
+
+	for _, line := range []string{
+		"~ study webhooks: \"\" -> \"" + a.Study.Webhooks[0].ID + "\"",
+		"~ study calendar token: \"\" -> \"set\"",
+		"~ round 1 member 100 thread: \"other\" -> \"thread\"",
+	} {
+		if !strings.Contains(diff, line) {
+			t.Errorf("missing %q in diff:\n%s", line, diff)
+		}
+	}
+
+	// secrets are not shown
+	if strings.Contains(diff, "token\"") || strings.Contains(diff, a.Study.Webhooks[0].Secret) {
+		t.Errorf("secret is shown in diff:\n%s", diff)
+	}
+}
+
+func TestRestoreRejectsSurplusRounds(t *testing.T) {
+	tx := newMemoryTx()
+	seed(t, tx, "source", 1)
+	seed(t, tx, "target", 1, 2, 3)
+
+	a := backupArchive(t, tx, "source")
+
+	_, err := Plan(context.Background(), tx, a, RestoreOptions{GuildID: "target", Overwrite: true})
+	if !errors.Is(err, ErrSurplusRounds) || !strings.Contains(err.Error(), "2, 3") {
+		t.Fatalf("err = %v, want %v", err, ErrSurplusRounds)
+	}
+}
+
+func TestReadUnsupportedVersion(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"version": 99, "guild_id": "g", "study": {"guild_id": "g"}}`))
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("err = %v, want %v", err, ErrUnsupportedVersion)
+	}
+}
@@ -0,0 +1,303 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/repository"
+)
+
+type RestoreOptions struct {
+	GuildID   string // target guild, the guild of the archive if empty
+	ManagerID string // overrides manager of the archive if set
+	Overwrite bool   // update existing study and rounds of the target guild, which must not have rounds missing in the archive
+}
+
+type RoundPlan struct {
+	Round    study.Round // round to write, id is set if the round exists
+	SourceID string      // id of the round in the archive
+	Create   bool
+}
+
+// RestorePlan describes writes of a restore and its differences from the target guild
+type RestorePlan struct {
+	Study       study.Study
+	CreateStudy bool
+	Rounds      []RoundPlan
+	Diff        []string
+}
+
+// plan restore of the archive into the target guild without writing anything
+func Plan(ctx context.Context, q repository.Query, a *Archive, opts RestoreOptions) (*RestorePlan, error) {
+	guildID := opts.GuildID
+	if guildID == "" {
+		guildID = a.GuildID
+	}
+
+	existing, err := q.FindStudy(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil && !opts.Overwrite {
+		return nil, errors.Join(ErrTargetExists, fmt.Errorf("guild: %s", guildID))
+	}
+
+	plan := &RestorePlan{
+		Study:       a.Study,
+		CreateStudy: existing == nil,
+	}
+
+	plan.Study.SetGuildID(guildID)
+
+	// channels, discord resources and secrets belong to the source guild
+	crossGuild := guildID != a.GuildID
+
+	var cleared []string
+
+	if crossGuild {
+		cleared = clearGuildState(&plan.Study)
+	}
+
+	if opts.ManagerID != "" {
+		plan.Study.SetManagerID(opts.ManagerID)
+	}
+
+	if existing != nil {
+		plan.Study.SetID(existing.ID)
+		plan.Diff = append(plan.Diff, diffStudy(*existing, plan.Study)...)
+	} else {
+		plan.Study.SetID("")
+		plan.Diff = append(plan.Diff, fmt.Sprintf("+ study (guild: %s, manager: %s, stage: %s, total round: %d)",
+			guildID, plan.Study.ManagerID, plan.Study.CurrentStage, plan.Study.TotalRound))
+
+		if len(cleared) > 0 {
+			plan.Diff = append(plan.Diff, fmt.Sprintf("- study %s of the source guild", strings.Join(cleared, ", ")))
+		}
+	}
+
+	// rounds of the target guild are matched by number
+	current := map[int8]*study.Round{}
+
+	if existing != nil {
+		rounds, err := q.FindRounds(ctx, guildID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rounds {
+			current[r.Number] = r
+		}
+	}
+
+	// rounds of the target not in the archive would be left with the total round of the archive
+	archived := make(map[int8]bool, len(a.Rounds))
+	for _, r := range a.Rounds {
+		archived[r.Number] = true
+	}
+
+	var surplus []string
+	for n := range current {
+		if !archived[n] {
+			surplus = append(surplus, fmt.Sprint(n))
+		}
+	}
+
+	if len(surplus) > 0 {
+		sort.Strings(surplus)
+		return nil, errors.Join(ErrSurplusRounds, fmt.Errorf("guild: %s, rounds: %s", guildID, strings.Join(surplus, ", ")))
+	}
+
+	for _, r := range a.Rounds {
+		rp := RoundPlan{Round: r, SourceID: r.ID}
+		rp.Round.SetGuildID(guildID)
+
+		if crossGuild {
+			clearRoundGuildState(&rp.Round)
+		}
+
+		if old, ok := current[r.Number]; ok {
+			rp.Round.SetID(old.ID)
+
+			changes := study.DiffRound(*old, rp.Round)
+			if old.Stage != rp.Round.Stage {
+				plan.Diff = append(plan.Diff, fmt.Sprintf("~ round %d stage: %s -> %s", r.Number, old.Stage, rp.Round.Stage))
+			}
+
+			if old.ScheduledEventID != rp.Round.ScheduledEventID {
+				plan.Diff = append(plan.Diff, fmt.Sprintf("~ round %d scheduled event: %q -> %q", r.Number, old.ScheduledEventID, rp.Round.ScheduledEventID))
+			}
+
+			for _, c := range changes {
+				plan.Diff = append(plan.Diff, fmt.Sprintf("~ round %d %s", r.Number, formatChange(c)))
+			}
+
+			for id, m := range rp.Round.Members {
+				prev, ok := old.Members[id]
+				if !ok {
+					plan.Diff = append(plan.Diff, fmt.Sprintf("~ round %d + member %s", r.Number, id))
+					continue
+				}
+
+				if prev.ThreadID != m.ThreadID {
+					plan.Diff = append(plan.Diff, fmt.Sprintf("~ round %d member %s thread: %q -> %q", r.Number, id, prev.ThreadID, m.ThreadID))
+				}
+			}
+
+			for id := range old.Members {
+				if _, ok := rp.Round.Members[id]; !ok {
+					plan.Diff = append(plan.Diff, fmt.Sprintf("~ round %d - member %s", r.Number, id))
+				}
+			}
+		} else {
+			rp.Round.SetID("")
+			rp.Create = true
+			plan.Diff = append(plan.Diff, fmt.Sprintf("+ round %d: %s (%d members)", r.Number, r.Title, len(r.Members)))
+		}
+
+		plan.Rounds = append(plan.Rounds, rp)
+	}
+
+	return plan, nil
+}
+
+// write the plan in a transaction, ids of rounds are remapped to the ids of the target
+func Apply(ctx context.Context, tx repository.Tx, plan *RestorePlan) (*study.Study, error) {
+	res, err := tx.ExecTx(ctx, func(sc context.Context) (interface{}, error) {
+		ids := map[string]string{} // source round id to target round id
+
+		for _, rp := range plan.Rounds {
+			var r *study.Round
+			var err error
+
+			if rp.Create {
+				r, err = tx.CreateRound(sc, rp.Round)
+			} else {
+				r, err = tx.UpdateRound(sc, rp.Round)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			ids[rp.SourceID] = r.ID
+		}
+
+		s := plan.Study
+
+		if s.OngoingRoundID != "" {
+			s.SetOngoingRoundID(ids[s.OngoingRoundID])
+		}
+
+		if plan.CreateStudy {
+			return tx.CreateStudy(sc, s)
+		}
+
+		return tx.UpdateStudy(sc, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*study.Study), nil
+}
+
+// clear state of the study bound to the source guild and return names of the cleared fields
+func clearGuildState(s *study.Study) []string {
+	var cleared []string
+
+	unset := func(name string, set bool, fn func()) {
+		if set {
+			cleared = append(cleared, name)
+			fn()
+		}
+	}
+
+	unset("notice channel", s.NoticeChannelID != "", func() { s.SetNoticeChannelID("") })
+	unset("reflection channel", s.ReflectionChannelID != "", func() { s.SetReflectionChannelID("") })
+	unset("presentation channel", s.PresentationChannelID != "", func() { s.SetPresentationChannelID("") })
+	unset("topic poll", s.TopicPoll != nil, func() { s.SetTopicPoll(nil) })
+	unset("webhooks", len(s.Webhooks) > 0, func() { s.Webhooks = nil })
+	unset("calendar token", s.CalendarToken != "", func() { s.SetCalendarToken("") })
+
+	return cleared
+}
+
+// scheduled event and threads of the round belong to the source guild
+func clearRoundGuildState(r *study.Round) {
+	r.SetScheduledEventID("")
+
+	members := make(map[string]study.Member, len(r.Members))
+
+	for id, m := range r.Members {
+		m.SetThreadID("")
+		members[id] = m
+	}
+
+	r.Members = members
+}
+
+func diffStudy(old, new study.Study) []string {
+	var diff []string
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"manager", old.ManagerID, new.ManagerID},
+		{"notice channel", old.NoticeChannelID, new.NoticeChannelID},
+		{"reflection channel", old.ReflectionChannelID, new.ReflectionChannelID},
+		{"presentation channel", old.PresentationChannelID, new.PresentationChannelID},
+		{"topic poll", topicPollSummary(old.TopicPoll), topicPollSummary(new.TopicPoll)},
+		{"webhooks", webhookIDs(old.Webhooks), webhookIDs(new.Webhooks)},
+		{"calendar token", secretSummary(old.CalendarToken), secretSummary(new.CalendarToken)},
+		{"spreadsheet", old.SpreadsheetURL, new.SpreadsheetURL},
+		{"stage", old.CurrentStage.String(), new.CurrentStage.String()},
+		{"total round", fmt.Sprint(old.TotalRound), fmt.Sprint(new.TotalRound)},
+	}
+
+	for _, f := range fields {
+		if f.old != f.new {
+			diff = append(diff, fmt.Sprintf("~ study %s: %q -> %q", f.name, f.old, f.new))
+		}
+	}
+
+	return diff
+}
+
+func topicPollSummary(p *study.TopicPoll) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d proposals, %d votes", len(p.Proposals), len(p.Votes))
+}
+
+// ids of the webhooks, secrets are not shown
+func webhookIDs(webhooks []study.Webhook) string {
+	ids := make([]string, 0, len(webhooks))
+
+	for _, w := range webhooks {
+		ids = append(ids, w.ID)
+	}
+
+	return strings.Join(ids, ", ")
+}
+
+// secrets are shown only whether they are set
+func secretSummary(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "set"
+}
+
+func formatChange(c study.RoundChange) string {
+	if c.MemberID == "" {
+		return fmt.Sprintf("%s: %q -> %q", c.Field, c.Old, c.New)
+	}
+	return fmt.Sprintf("member %s %s: %q -> %q", c.MemberID, c.Field, c.Old, c.New)
+}
@@ -0,0 +1,118 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+)
+
+// prefix of the feed routes
+const Prefix = "/calendar/"
+
+type handler struct {
+	svc service.Service
+}
+
+// create handler serving the calendar feed of each guild
+//
+//	GET /calendar/{guildID}.ics?token={calendar token of the study}
+//
+// calendar apps cannot send headers, so the token is given as a query parameter
+func NewHandler(svc service.Service) http.Handler {
+	return &handler{
+		svc: svc,
+	}
+}
+
+// url of the feed, the base url is the public address of the http server
+func FeedURL(baseURL, guildID, token string) string {
+	return fmt.Sprintf("%s%s%s.ics?token=%s", strings.TrimSuffix(baseURL, "/"), Prefix, guildID, token)
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	guildID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, Prefix), ".ics")
+	if !ok || guildID == "" || strings.Contains(guildID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	gs, err := h.svc.GetStudy(r.Context(), guildID)
+	if err != nil {
+		if errors.Is(err, study.ErrStudyNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+
+	if gs.CalendarToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(gs.CalendarToken)) != 1 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := Feed(r.Context(), h.svc, buf, guildID); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.ics"`, guildID))
+	_, _ = buf.WriteTo(w)
+}
+
+// write calendar of all rounds of the guild
+func Feed(ctx context.Context, svc service.Service, w io.Writer, guildID string) error {
+	found, err := svc.GetRounds(ctx, guildID)
+	if err != nil && !errors.Is(err, study.ErrRoundNotFound) {
+		return err
+	}
+
+	return WriteFeed(w, guildID, found)
+}
+
+// write calendar of the rounds already loaded
+func WriteFeed(w io.Writer, guildID string, found []*study.Round) error {
+	rounds := make([]study.Round, 0, len(found))
+
+	for _, r := range found {
+		rounds = append(rounds, *r)
+	}
+
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i].Number < rounds[j].Number })
+
+	return Write(w, "스터디 일정", Events(guildID, rounds...)...)
+}
+
+// member ids of the round in ascending order
+func sortedMemberIDs(r study.Round) []string {
+	ids := make([]string, 0, len(r.Members))
+
+	for id := range r.Members {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
@@ -0,0 +1,198 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	prodID       = "-//piatoss3612//my-study-bot//KO"
+	icsTime      = "20060102T150405Z"
+	maxLineOctet = 75
+
+	// length of events of the deadlines
+	deadlineDuration = 30 * time.Minute
+	// length of the presentation if the end is not scheduled
+	presentationDuration = 2 * time.Hour
+	// alarm before the deadlines and the presentation
+	alarmBefore = time.Hour
+)
+
+// Event is an entry of the calendar
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+	Alarm       bool
+}
+
+// events of the scheduled stages of the rounds
+func Events(guildID string, rounds ...study.Round) []Event {
+	var events []Event
+
+	for _, r := range rounds {
+		for _, e := range r.Schedule {
+			evt := Event{
+				UID:     fmt.Sprintf("%s-%d-%d@my-study-bot", guildID, r.Number, e.Stage),
+				Summary: fmt.Sprintf("[%d회차] %s", r.Number, e.Stage.String()),
+				Start:   e.At,
+				End:     e.At.Add(deadlineDuration),
+				Stamp:   r.UpdatedAt,
+				Alarm:   e.Stage != study.StageFinished,
+			}
+
+			if r.Title != "" {
+				evt.Description = r.Title
+			}
+
+			switch e.Stage {
+			case study.StagePresentationStarted:
+				evt.Summary = fmt.Sprintf("[%d회차] 발표", r.Number)
+				evt.End = e.At.Add(presentationDuration)
+
+				if end, ok := r.ScheduleOf(study.StagePresentationFinished); ok && end.After(e.At) {
+					evt.End = end
+				}
+
+				evt.Description = presentationDescription(r)
+				evt.URL = r.ContentURL
+			case study.StagePresentationFinished:
+				// covered by the presentation event
+				if _, ok := r.ScheduleOf(study.StagePresentationStarted); ok {
+					continue
+				}
+			}
+
+			events = append(events, evt)
+		}
+	}
+
+	return events
+}
+
+func presentationDescription(r study.Round) string {
+	b := &strings.Builder{}
+	b.WriteString(r.Title)
+
+	speakers := 0
+
+	for _, id := range sortedMemberIDs(r) {
+		m := r.Members[id]
+		if !m.Registered {
+			continue
+		}
+
+		if speakers == 0 {
+			b.WriteString("\n\n발표자")
+		}
+
+		speakers++
+
+		fmt.Fprintf(b, "\n- %s: %s", m.Name, m.Subject)
+	}
+
+	return b.String()
+}
+
+// write the events as an iCalendar (RFC 5545) document
+func Write(w io.Writer, name string, events ...Event) error {
+	bw := bufio.NewWriter(w)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + prodID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escape(name),
+		"X-PUBLISHED-TTL:PT1H",
+	}
+
+	for _, e := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+escape(e.UID),
+			"DTSTAMP:"+formatTime(e.Stamp),
+			"DTSTART:"+formatTime(e.Start),
+			"DTEND:"+formatTime(e.End),
+			"SUMMARY:"+escape(e.Summary),
+		)
+
+		if e.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escape(e.Description))
+		}
+
+		if e.URL != "" {
+			lines = append(lines, "URL:"+e.URL)
+		}
+
+		if e.Alarm {
+			lines = append(lines,
+				"BEGIN:VALARM",
+				"ACTION:DISPLAY",
+				"DESCRIPTION:"+escape(e.Summary),
+				fmt.Sprintf("TRIGGER:-PT%dM", int(alarmBefore.Minutes())),
+				"END:VALARM",
+			)
+		}
+
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, l := range lines {
+		if _, err := bw.WriteString(fold(l)); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(icsTime)
+}
+
+// escape text values
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// fold lines longer than 75 octets without splitting utf-8 characters, lines end with CRLF
+func fold(line string) string {
+	b := &strings.Builder{}
+
+	n := 0
+
+	for _, c := range line {
+		size := len(string(c))
+
+		if n+size > maxLineOctet {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+
+		b.WriteRune(c)
+		n += size
+	}
+
+	b.WriteString("\r\n")
+
+	return b.String()
+}
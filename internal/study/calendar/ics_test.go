@@ -0,0 +1,180 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+)
+
+func testRound() study.Round {
+	r := study.NewRound()
+	r.SetNumber(3)
+	r.SetTitle("Go, 동시성; 패턴")
+	r.SetContentURL("https://example.com/recording")
+
+	m := study.NewMember()
+	m.SetName("alice")
+	m.SetSubject("채널")
+	m.SetRegistered(true)
+	r.SetMember("u1", m)
+
+	start := time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC)
+
+	r.SetSchedule(study.StagePresentationFinished, start.Add(90*time.Minute))
+	r.SetSchedule(study.StageRegistrationClosed, start.Add(-48*time.Hour))
+	r.SetSchedule(study.StagePresentationStarted, start)
+
+	return r
+}
+
+func TestEvents(t *testing.T) {
+	r := testRound()
+
+	if r.Schedule[0].Stage != study.StageRegistrationClosed {
+		t.Fatalf("expected schedule sorted by stage, got %+v", r.Schedule)
+	}
+
+	events := Events("guild", r)
+
+	// end of the presentation is merged into the presentation event
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %+v", len(events), events)
+	}
+
+	deadline, presentation := events[0], events[1]
+
+	if deadline.Summary != "[3회차] 발표자 등록 마감" || deadline.End.Sub(deadline.Start) != deadlineDuration {
+		t.Fatalf("unexpected deadline event: %+v", deadline)
+	}
+
+	if presentation.Summary != "[3회차] 발표" || presentation.End.Sub(presentation.Start) != 90*time.Minute {
+		t.Fatalf("unexpected presentation event: %+v", presentation)
+	}
+
+	if !strings.Contains(presentation.Description, "alice: 채널") || presentation.URL != r.ContentURL {
+		t.Fatalf("unexpected presentation description: %q", presentation.Description)
+	}
+
+	if deadline.UID == presentation.UID {
+		t.Fatal("uids of events should be unique")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	evt := Event{
+		UID:         "uid@test",
+		Summary:     "[1회차] 발표",
+		Description: strings.Repeat("긴 설명, ", 20) + "\n둘째 줄",
+		Start:       time.Date(2023, 6, 3, 20, 0, 0, 0, time.FixedZone("KST", 9*60*60)),
+		End:         time.Date(2023, 6, 3, 22, 0, 0, 0, time.FixedZone("KST", 9*60*60)),
+		Alarm:       true,
+	}
+
+	if err := Write(buf, "스터디 일정", evt); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Fatalf("unexpected calendar: %q", out)
+	}
+
+	for _, want := range []string{"DTSTART:20230603T110000Z\r\n", "DTEND:20230603T130000Z\r\n", "BEGIN:VALARM\r\n", "TRIGGER:-PT60M\r\n"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in calendar", want)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctet {
+			t.Fatalf("line longer than %d octets: %q", maxLineOctet, line)
+		}
+	}
+
+	// unfold and check escaped description
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+
+	if !strings.Contains(unfolded, `DESCRIPTION:긴 설명\, `) || !strings.Contains(unfolded, `\n둘째 줄`) {
+		t.Fatalf("description is not escaped: %q", unfolded)
+	}
+}
+
+func TestWriteFeedSortsRounds(t *testing.T) {
+	first, second := testRound(), testRound()
+	first.SetNumber(1)
+
+	buf := &bytes.Buffer{}
+
+	if err := WriteFeed(buf, "guild", []*study.Round{&second, &first}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	if i, j := strings.Index(out, "[1회차]"), strings.Index(out, "[3회차]"); i < 0 || j < 0 || i > j {
+		t.Fatalf("expected rounds in order of number: %q", out)
+	}
+}
+
+type fakeService struct {
+	service.Service
+
+	study  *study.Study
+	rounds []*study.Round
+}
+
+func (f *fakeService) GetStudy(_ context.Context, guildID string) (*study.Study, error) {
+	if f.study.GuildID != guildID {
+		return nil, study.ErrStudyNotFound
+	}
+	return f.study, nil
+}
+
+func (f *fakeService) GetRounds(context.Context, string) ([]*study.Round, error) {
+	return f.rounds, nil
+}
+
+func TestHandler(t *testing.T) {
+	r := testRound()
+
+	h := NewHandler(&fakeService{
+		study:  &study.Study{GuildID: "guild", CalendarToken: "token"},
+		rounds: []*study.Round{&r},
+	})
+
+	tests := []struct {
+		target string
+		code   int
+	}{
+		{"/calendar/guild.ics?token=token", http.StatusOK},
+		{"/calendar/guild.ics?token=wrong", http.StatusUnauthorized},
+		{"/calendar/guild.ics", http.StatusUnauthorized},
+		{"/calendar/other.ics?token=token", http.StatusNotFound},
+		{"/calendar/guild?token=token", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+		if rec.Code != tt.code {
+			t.Fatalf("%s: expected status %d, got %d", tt.target, tt.code, rec.Code)
+		}
+
+		if rec.Code == http.StatusOK {
+			if rec.Header().Get("Content-Type") != ContentType || !strings.Contains(rec.Body.String(), "BEGIN:VEVENT") {
+				t.Fatalf("unexpected feed: %s", rec.Body.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package study
+
+import (
+	"sort"
+	"strconv"
+)
+
+type RoundField string
+
+const (
+	RoundFieldTitle            RoundField = "title"
+	RoundFieldContentURL       RoundField = "content_url"
+	RoundFieldMemberName       RoundField = "member_name"
+	RoundFieldMemberSubject    RoundField = "member_subject"
+	RoundFieldMemberContentURL RoundField = "member_content_url"
+	RoundFieldMemberAttended   RoundField = "member_attended"
+)
+
+func (f RoundField) String() string {
+	switch f {
+	case RoundFieldTitle:
+		return "제목"
+	case RoundFieldContentURL:
+		return "녹화 영상"
+	case RoundFieldMemberName:
+		return "이름"
+	case RoundFieldMemberSubject:
+		return "발표 주제"
+	case RoundFieldMemberContentURL:
+		return "발표 자료"
+	case RoundFieldMemberAttended:
+		return "발표 참여"
+	default:
+		return "알 수 없음"
+	}
+}
+
+func (f RoundField) IsMemberField() bool {
+	switch f {
+	case RoundFieldMemberName, RoundFieldMemberSubject, RoundFieldMemberContentURL, RoundFieldMemberAttended:
+		return true
+	default:
+		return false
+	}
+}
+
+// change of a round edited outside of the bot, member id is empty for fields of the round itself
+type RoundChange struct {
+	MemberID string     `json:"member_id,omitempty"`
+	Field    RoundField `json:"field"`
+	Old      string     `json:"old"`
+	New      string     `json:"new"`
+}
+
+// value of the field in string representation
+func (r Round) FieldValue(memberID string, field RoundField) (string, bool) {
+	if !field.IsMemberField() {
+		switch field {
+		case RoundFieldTitle:
+			return r.Title, true
+		case RoundFieldContentURL:
+			return r.ContentURL, true
+		default:
+			return "", false
+		}
+	}
+
+	m, ok := r.GetMember(memberID)
+	if !ok {
+		return "", false
+	}
+
+	switch field {
+	case RoundFieldMemberName:
+		return m.Name, true
+	case RoundFieldMemberSubject:
+		return m.Subject, true
+	case RoundFieldMemberContentURL:
+		return m.ContentURL, true
+	default:
+		return strconv.FormatBool(m.Attended), true
+	}
+}
+
+// apply the change to the round
+func (r *Round) ApplyChange(c RoundChange) error {
+	if _, ok := r.FieldValue(c.MemberID, c.Field); !ok {
+		return ErrInvalidRoundChange
+	}
+
+	switch c.Field {
+	case RoundFieldTitle:
+		r.SetTitle(c.New)
+		return nil
+	case RoundFieldContentURL:
+		r.SetContentURL(c.New)
+		return nil
+	}
+
+	m := r.Members[c.MemberID]
+
+	switch c.Field {
+	case RoundFieldMemberName:
+		m.SetName(c.New)
+	case RoundFieldMemberSubject:
+		m.SetSubject(c.New)
+	case RoundFieldMemberContentURL:
+		m.SetContentURL(c.New)
+	case RoundFieldMemberAttended:
+		attended, err := strconv.ParseBool(c.New)
+		if err != nil {
+			return ErrInvalidRoundChange
+		}
+		m.SetAttended(attended)
+	}
+
+	r.SetMember(c.MemberID, m)
+
+	return nil
+}
+
+// changes to make the current round same as the edited one, members not in the current round are ignored
+func DiffRound(current, edited Round) []RoundChange {
+	changes := []RoundChange{}
+
+	for _, f := range []RoundField{RoundFieldTitle, RoundFieldContentURL} {
+		old, _ := current.FieldValue("", f)
+		new, _ := edited.FieldValue("", f)
+
+		if old != new {
+			changes = append(changes, RoundChange{Field: f, Old: old, New: new})
+		}
+	}
+
+	ids := make([]string, 0, len(edited.Members))
+
+	for id := range edited.Members {
+		if _, ok := current.Members[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		for _, f := range []RoundField{RoundFieldMemberName, RoundFieldMemberSubject, RoundFieldMemberContentURL, RoundFieldMemberAttended} {
+			old, _ := current.FieldValue(id, f)
+			new, _ := edited.FieldValue(id, f)
+
+			if old != new {
+				changes = append(changes, RoundChange{MemberID: id, Field: f, Old: old, New: new})
+			}
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,57 @@
+package study
+
+import "time"
+
+func (m *Member) SetExtendedUntil(t time.Time) {
+	m.ExtendedUntil = t
+}
+
+// whether the deadline of the member is extended past the time
+func (m Member) HasExtension(at time.Time) bool {
+	return !m.ExtendedUntil.IsZero() && at.Before(m.ExtendedUntil)
+}
+
+// whether any material is submitted after the deadline
+func (m Member) IsLate() bool {
+	for _, mat := range m.Materials {
+		if mat.Late {
+			return true
+		}
+	}
+	return false
+}
+
+// deadline of the submission is the scheduled start of the closing stage
+func (r Round) SubmissionDeadline() (time.Time, bool) {
+	return r.ScheduleOf(StageSubmissionClosed)
+}
+
+// deadline of the member is the later of the submission deadline and the extension,
+// an extension does not make a deadline for a round without a scheduled one
+func (r Round) memberDeadline(m Member) (time.Time, bool) {
+	deadline, ok := r.SubmissionDeadline()
+	if !ok {
+		return time.Time{}, false
+	}
+	if m.ExtendedUntil.After(deadline) {
+		return m.ExtendedUntil, true
+	}
+	return deadline, true
+}
+
+// whether the member can submit at the time and whether the submission is late,
+// after the submission is closed only members with extensions can submit until the presentation is finished.
+// the submission is late after the deadline of the member, without a scheduled deadline
+// the closing of the submission is the deadline, so submissions through the extension are late
+func (r Round) SubmissionWindow(stage Stage, m Member, at time.Time) (allowed bool, late bool) {
+	deadline, ok := r.memberDeadline(m)
+
+	switch {
+	case stage == StageSubmissionOpened:
+		return true, ok && at.After(deadline)
+	case stage > StageSubmissionOpened && stage < StagePresentationFinished:
+		return m.HasExtension(at), !ok || at.After(deadline)
+	default:
+		return false, false
+	}
+}
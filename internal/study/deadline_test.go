@@ -0,0 +1,66 @@
+package study
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubmissionWindow(t *testing.T) {
+	deadline := time.Date(2026, 3, 7, 18, 0, 0, 0, time.UTC)
+
+	scheduled := Round{Schedule: []ScheduleEntry{{Stage: StageSubmissionClosed, At: deadline}}}
+	unscheduled := Round{}
+
+	none := Member{}
+	extended := Member{ExtendedUntil: deadline.Add(2 * time.Hour)}
+	shortened := Member{ExtendedUntil: deadline.Add(-2 * time.Hour)}
+
+	before := deadline.Add(-time.Hour)
+	between := deadline.Add(time.Hour)
+	after := deadline.Add(3 * time.Hour)
+
+	tests := []struct {
+		name    string
+		round   Round
+		stage   Stage
+		member  Member
+		at      time.Time
+		allowed bool
+		late    bool
+	}{
+		{"opened before deadline", scheduled, StageSubmissionOpened, none, before, true, false},
+		{"opened after deadline", scheduled, StageSubmissionOpened, none, between, true, true},
+		{"opened within extension", scheduled, StageSubmissionOpened, extended, between, true, false},
+		{"opened after extension", scheduled, StageSubmissionOpened, extended, after, true, true},
+		{"opened with extension before deadline", scheduled, StageSubmissionOpened, shortened, before, true, false},
+		{"opened after extension before deadline", scheduled, StageSubmissionOpened, shortened, between, true, true},
+		{"opened without deadline", unscheduled, StageSubmissionOpened, none, after, true, false},
+		{"opened without deadline within extension", unscheduled, StageSubmissionOpened, extended, between, true, false},
+		{"opened without deadline after extension", unscheduled, StageSubmissionOpened, extended, after, true, false},
+
+		{"closed without extension", scheduled, StageSubmissionClosed, none, between, false, true},
+		{"closed early without extension", scheduled, StageSubmissionClosed, none, before, false, false},
+		{"closed within extension", scheduled, StageSubmissionClosed, extended, between, true, false},
+		{"closed early within extension", scheduled, StageSubmissionClosed, extended, before, true, false},
+		{"closed after extension", scheduled, StageSubmissionClosed, extended, after, false, true},
+		{"closed after extension before deadline", scheduled, StageSubmissionClosed, shortened, between, false, true},
+		{"closed without deadline", unscheduled, StageSubmissionClosed, none, between, false, true},
+		{"closed without deadline within extension", unscheduled, StageSubmissionClosed, extended, between, true, true},
+		{"closed without deadline after extension", unscheduled, StageSubmissionClosed, extended, after, false, true},
+		{"extension granted after closing", scheduled, StageSubmissionClosed, Member{ExtendedUntil: after.Add(time.Hour)}, after, true, false},
+		{"extension granted after closing without deadline", unscheduled, StagePresentationStarted, Member{ExtendedUntil: after.Add(time.Hour)}, after, true, true},
+		{"presentation started within extension", scheduled, StagePresentationStarted, extended, between, true, false},
+
+		{"registration opened", scheduled, StageRegistrationOpened, extended, before, false, false},
+		{"presentation finished within extension", scheduled, StagePresentationFinished, extended, between, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, late := tt.round.SubmissionWindow(tt.stage, tt.member, tt.at)
+			if allowed != tt.allowed || late != tt.late {
+				t.Fatalf("got allowed=%v late=%v, want allowed=%v late=%v", allowed, late, tt.allowed, tt.late)
+			}
+		})
+	}
+}
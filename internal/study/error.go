@@ -31,4 +31,19 @@ var (
 	ErrNilFunc               = errors.New("함수가 nil입니다")
 	ErrUnknownEventTopic     = errors.New("알 수 없는 이벤트 토픽입니다")
 	ErrInvalidEventData      = errors.New("잘못된 이벤트 데이터입니다")
+	ErrInvalidSpreadsheetURL = errors.New("올바르지 않은 스프레드시트 URL입니다")
+	ErrInvalidRoundChange    = errors.New("적용할 수 없는 라운드 변경 사항입니다")
+	ErrRoundSheetNotFound    = errors.New("라운드 시트를 찾을 수 없습니다")
+	ErrInvalidWebhookURL     = errors.New("올바르지 않은 웹훅 URL입니다")
+	ErrWebhookNotFound       = errors.New("웹훅 정보를 찾을 수 없습니다")
+	ErrTooManyWebhooks       = errors.New("더 이상 웹훅을 등록할 수 없습니다")
+	ErrInvalidSchedule       = errors.New("올바르지 않은 일정입니다")
+	ErrInvalidTimetable      = errors.New("올바르지 않은 발표 순서입니다")
+	ErrAlreadyWaiting        = errors.New("이미 대기자로 등록되었습니다")
+	ErrTopicPollNotFound     = errors.New("진행중인 주제 투표가 없습니다")
+	ErrTopicPollExists       = errors.New("이미 진행중인 주제 투표가 있습니다")
+	ErrInvalidTopicProposal  = errors.New("올바르지 않은 주제 제안입니다")
+	ErrInvalidMaterialURL    = errors.New("허용되지 않은 발표 자료 링크입니다")
+	ErrUnreachableMaterial   = errors.New("발표 자료 링크에 접근할 수 없습니다")
+	ErrInvalidMaterialFile   = errors.New("허용되지 않은 발표 자료 파일입니다")
 )
@@ -25,10 +25,14 @@ func (t EventTopic) String() string {
 }
 
 type Event struct {
-	Topic       EventTopic `json:"topic"`
-	Description string     `json:"description"`
-	Timestamp   int64      `json:"timestamp"`
-	Data        []byte     `json:"data"`
+	ID             string     `json:"id,omitempty"` // same across redeliveries of the event
+	Topic          EventTopic `json:"topic"`
+	GuildID        string     `json:"guild_id,omitempty"`
+	SpreadsheetURL string     `json:"spreadsheet_url,omitempty"`
+	Description    string     `json:"description"`
+	Timestamp      int64      `json:"timestamp"`
+	Data           []byte     `json:"data"`
+	WebhookIDs     []string   `json:"webhook_ids,omitempty"` // secrets are resolved by the logger
 }
 
 func NewEvent(topic EventTopic, description string, data ...[]byte) (Event, error) {
@@ -36,7 +40,13 @@ func NewEvent(topic EventTopic, description string, data ...[]byte) (Event, erro
 		return Event{}, err
 	}
 
+	id, err := randomHex(16)
+	if err != nil {
+		return Event{}, err
+	}
+
 	evt := Event{
+		ID:          id,
 		Topic:       topic,
 		Description: description,
 		Timestamp:   time.Now().Unix(),
@@ -48,3 +58,26 @@ func NewEvent(topic EventTopic, description string, data ...[]byte) (Event, erro
 
 	return evt, nil
 }
+
+// set the guild the event belongs to and its export target
+func (e *Event) SetTarget(guildID, spreadsheetURL string) {
+	e.GuildID = guildID
+	e.SpreadsheetURL = spreadsheetURL
+}
+
+// set webhooks the event is delivered to, only ids are carried to keep secrets out of the broker
+func (e *Event) SetWebhooks(webhooks []Webhook) {
+	e.WebhookIDs = nil
+
+	for _, w := range webhooks {
+		if w.Subscribes(e.Topic) {
+			e.WebhookIDs = append(e.WebhookIDs, w.ID)
+		}
+	}
+}
+
+// export the event to the spreadsheet and webhooks of the study
+func (e *Event) SetStudy(s Study) {
+	e.SetTarget(s.GuildID, s.SpreadsheetURL)
+	e.SetWebhooks(s.Webhooks)
+}
@@ -0,0 +1,267 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+var (
+	defaultMaxRetries   = 5
+	defaultRetryBackoff = 1 * time.Second
+	maxRetryBackoff     = 1 * time.Minute
+	defaultMaxBuffered  = 1000
+)
+
+// number of progress rows waiting to be flushed to the spreadsheets
+var ProgressBufferDepth = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "sheets_progress_buffer_depth",
+		Help: "Number of progress rows buffered for google sheets.",
+	},
+)
+
+// number of progress rows dropped because the buffer of the spreadsheet is full
+var ProgressRowsDropped = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "sheets_progress_rows_dropped_total",
+		Help: "Number of progress rows dropped because the buffer for google sheets is full.",
+	},
+)
+
+// buffer progress rows and flush them when the size is reached or on every interval
+func WithProgressBatch(size int, interval time.Duration) SheetsOptsFunc {
+	return func(h *sheetsSink) {
+		h.batchSize = size
+		h.flushInterval = interval
+	}
+}
+
+// max retries of a write rejected by rate limit
+func WithMaxRetries(n int) SheetsOptsFunc {
+	return func(h *sheetsSink) {
+		h.maxRetries = n
+	}
+}
+
+// max progress rows buffered per spreadsheet, the oldest rows are dropped beyond it
+func WithMaxBufferedRows(n int) SheetsOptsFunc {
+	return func(h *sheetsSink) {
+		h.maxBuffered = n
+	}
+}
+
+// errors of flushes on interval are reported to the handler
+func WithFlushErrorHandler(fn func(error)) SheetsOptsFunc {
+	return func(h *sheetsSink) {
+		h.onFlushError = fn
+	}
+}
+
+// append progress row to the buffer of the spreadsheet
+func (h *sheetsSink) enqueue(ctx context.Context, spreadsheetID string, row *sheets.RowData) error {
+	h.bufMtx.Lock()
+	h.buffer[spreadsheetID] = h.capBuffer(append(h.buffer[spreadsheetID], row))
+	full := len(h.buffer[spreadsheetID]) >= h.batchSize
+	h.updateBufferDepth()
+	h.bufMtx.Unlock()
+
+	if !full {
+		return nil
+	}
+
+	return h.flush(ctx, spreadsheetID)
+}
+
+// write buffered progress rows of the spreadsheet in a single batch
+func (h *sheetsSink) flush(ctx context.Context, spreadsheetID string) error {
+	defer h.flushMtx.Unlock()
+	h.flushMtx.Lock()
+
+	h.bufMtx.Lock()
+	rows := h.buffer[spreadsheetID]
+	delete(h.buffer, spreadsheetID)
+	h.bufMtx.Unlock()
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	resp, err := h.batchUpdate(ctx, spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AppendCells: &sheets.AppendCellsRequest{
+					SheetId: h.progressSheetID,
+					Fields:  "*",
+					Rows:    rows,
+				},
+			},
+		},
+	})
+	if err == nil && resp.HTTPStatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status code: %d", resp.HTTPStatusCode)
+	}
+
+	if err != nil {
+		// put rows back in front of rows buffered meanwhile to keep the order
+		h.bufMtx.Lock()
+		h.buffer[spreadsheetID] = h.capBuffer(append(rows, h.buffer[spreadsheetID]...))
+		h.updateBufferDepth()
+		h.bufMtx.Unlock()
+
+		return err
+	}
+
+	h.bufMtx.Lock()
+	h.updateBufferDepth()
+	h.bufMtx.Unlock()
+
+	return nil
+}
+
+// flush buffers of all spreadsheets
+func (h *sheetsSink) flushAll(ctx context.Context) error {
+	h.bufMtx.Lock()
+	ids := make([]string, 0, len(h.buffer))
+	for id := range h.buffer {
+		ids = append(ids, id)
+	}
+	h.bufMtx.Unlock()
+
+	var errs []error
+
+	for _, id := range ids {
+		if err := h.flush(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h *sheetsSink) flushLoop() {
+	ticker := time.NewTicker(h.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), h.flushInterval)
+			err := h.flushAll(ctx)
+			cancel()
+
+			if err != nil && h.onFlushError != nil {
+				h.onFlushError(err)
+			}
+		}
+	}
+}
+
+// stop flushing on interval and flush remaining rows
+func (h *sheetsSink) Close(ctx context.Context) error {
+	h.closeOnce.Do(func() {
+		close(h.stop)
+	})
+
+	return h.flushAll(ctx)
+}
+
+// drop the oldest rows beyond the limit, should be called with bufMtx held
+func (h *sheetsSink) capBuffer(rows []*sheets.RowData) []*sheets.RowData {
+	if h.maxBuffered < 1 || len(rows) <= h.maxBuffered {
+		return rows
+	}
+
+	dropped := len(rows) - h.maxBuffered
+	ProgressRowsDropped.Add(float64(dropped))
+
+	return rows[dropped:]
+}
+
+// should be called with bufMtx held
+func (h *sheetsSink) updateBufferDepth() {
+	var depth int
+
+	for _, rows := range h.buffer {
+		depth += len(rows)
+	}
+
+	ProgressBufferDepth.Set(float64(depth))
+}
+
+// batch update retried on rate limit with the delay given by Retry-After header
+func (h *sheetsSink) batchUpdate(ctx context.Context, spreadsheetID string, req *sheets.BatchUpdateSpreadsheetRequest) (*sheets.BatchUpdateSpreadsheetResponse, error) {
+	backoff := defaultRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		resp, err := h.c.BatchUpdate(ctx, spreadsheetID, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		var gerr *googleapi.Error
+		if !errors.As(err, &gerr) || gerr.Code != http.StatusTooManyRequests || attempt >= h.maxRetries {
+			return nil, err
+		}
+
+		delay, ok := retryAfter(gerr.Header, time.Now())
+		if !ok {
+			delay = backoff
+			backoff = minDuration(backoff*2, maxRetryBackoff)
+		}
+
+		if err := h.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parse Retry-After header given as seconds or http date
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return minDuration(time.Duration(secs)*time.Second, maxRetryBackoff), true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return minDuration(d, maxRetryBackoff), true
+	}
+
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,266 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/api/googleapi"
+)
+
+func rateLimitError(retryAfter string) error {
+	header := http.Header{}
+	if retryAfter != "" {
+		header.Set("Retry-After", retryAfter)
+	}
+
+	return &googleapi.Error{Code: http.StatusTooManyRequests, Message: "Quota exceeded", Header: header}
+}
+
+// sink with prepared spreadsheet and recorded delays instead of sleeping
+func newBatchTestSink(t *testing.T, c *fakeSpreadsheetClient, opts ...SheetsOptsFunc) (*sheetsSink, *[]time.Duration) {
+	t.Helper()
+
+	opts = append([]SheetsOptsFunc{WithDefaultSpreadsheetID(testSpreadsheetID)}, opts...)
+	sink := NewSheetsSink(c, opts...).(*sheetsSink)
+
+	delays := &[]time.Duration{}
+	sink.sleep = func(_ context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+
+	t.Cleanup(func() {
+		_ = sink.Close(context.Background())
+	})
+
+	return sink, delays
+}
+
+func TestSheetsSinkProgressBatch(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink, _ := newBatchTestSink(t, c, WithProgressBatch(3, 0))
+
+	for i := 0; i < 2; i++ {
+		if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundProgress, "buffered")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// only the setup is written yet, header row of progress sheet
+	if got := len(c.sheet(testSpreadsheetID, defaultProgressSheetID).rows); got != 1 {
+		t.Fatalf("rows = %d, want 1", got)
+	}
+
+	if got := testutil.ToFloat64(ProgressBufferDepth); got != 2 {
+		t.Errorf("buffer depth = %v, want 2", got)
+	}
+
+	updates := c.batchUpdates
+
+	// third row fills the batch
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundProgress, "flushed")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(c.sheet(testSpreadsheetID, defaultProgressSheetID).rows); got != 4 {
+		t.Fatalf("rows = %d, want 4", got)
+	}
+
+	if got := c.batchUpdates - updates; got != 1 {
+		t.Errorf("batch updates = %d, want 1", got)
+	}
+
+	if got := testutil.ToFloat64(ProgressBufferDepth); got != 0 {
+		t.Errorf("buffer depth = %v, want 0", got)
+	}
+}
+
+func TestSheetsSinkCloseFlushesBuffer(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink, _ := newBatchTestSink(t, c, WithProgressBatch(10, time.Hour))
+
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundProgress, "buffered")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := CloseSinks(context.Background(), sink); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(c.sheet(testSpreadsheetID, defaultProgressSheetID).rows); got != 2 {
+		t.Fatalf("rows = %d, want 2", got)
+	}
+}
+
+func TestSheetsSinkFlushOnInterval(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink, _ := newBatchTestSink(t, c, WithProgressBatch(10, 10*time.Millisecond))
+
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundProgress, "buffered")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+
+	for len(c.sheet(testSpreadsheetID, defaultProgressSheetID).rows) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("buffer is not flushed on interval")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSheetsSinkRetryOnRateLimit(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink, delays := newBatchTestSink(t, c)
+
+	// prepare spreadsheet before injecting failures
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundCreated, "생성")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.fail(rateLimitError("3"), rateLimitError(""), rateLimitError(""))
+
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundProgress, "retried")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []time.Duration{3 * time.Second, defaultRetryBackoff, 2 * defaultRetryBackoff}
+
+	if len(*delays) != len(want) {
+		t.Fatalf("delays = %v, want %v", *delays, want)
+	}
+
+	for i := range want {
+		if (*delays)[i] != want[i] {
+			t.Errorf("delay %d = %v, want %v", i, (*delays)[i], want[i])
+		}
+	}
+
+	if got := len(c.sheet(testSpreadsheetID, defaultProgressSheetID).rows); got != 3 {
+		t.Fatalf("rows = %d, want 3", got)
+	}
+}
+
+func TestSheetsSinkRetryExhausted(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink, _ := newBatchTestSink(t, c, WithMaxRetries(1))
+
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundCreated, "생성")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.fail(rateLimitError("1"), rateLimitError("1"))
+
+	err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundProgress, "kept"))
+
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) || gerr.Code != http.StatusTooManyRequests {
+		t.Fatalf("err = %v, want rate limit error", err)
+	}
+
+	// failed rows are kept and written on the next flush
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sh := c.sheet(testSpreadsheetID, defaultProgressSheetID)
+	if got := len(sh.rows); got != 3 {
+		t.Fatalf("rows = %d, want 3", got)
+	}
+
+	if got := rowValues(sh.rows[2])[1]; got != "kept" {
+		t.Errorf("description = %q, want %q", got, "kept")
+	}
+}
+
+func TestSheetsSinkBufferLimit(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink, _ := newBatchTestSink(t, c, WithProgressBatch(2, 0), WithMaxBufferedRows(3), WithMaxRetries(0))
+
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundCreated, "생성")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dropped := testutil.ToFloat64(ProgressRowsDropped)
+
+	// every flush fails, so failed rows are put back until the limit is reached
+	c.fail(rateLimitError("1"), rateLimitError("1"), rateLimitError("1"), rateLimitError("1"), rateLimitError("1"))
+
+	for _, description := range []string{"1", "2", "3", "4", "5"} {
+		_ = sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundProgress, description))
+	}
+
+	sink.bufMtx.Lock()
+	buffered := len(sink.buffer[testSpreadsheetID])
+	sink.bufMtx.Unlock()
+
+	if buffered != 3 {
+		t.Fatalf("buffered = %d, want 3", buffered)
+	}
+
+	if got := testutil.ToFloat64(ProgressRowsDropped) - dropped; got != 3 {
+		t.Errorf("dropped = %v, want 3", got)
+	}
+
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the newest rows are kept
+	sh := c.sheet(testSpreadsheetID, defaultProgressSheetID)
+
+	var got []string
+	for _, row := range sh.rows[1:] {
+		got = append(got, rowValues(row)[1])
+	}
+
+	if want := []string{"3", "4", "5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("descriptions = %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "empty", value: ""},
+		{name: "seconds", value: "7", want: 7 * time.Second, ok: true},
+		{name: "http date", value: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second, ok: true},
+		{name: "past date", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, ok: true},
+		{name: "capped", value: "3600", want: maxRetryBackoff, ok: true},
+		{name: "invalid", value: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+
+			got, ok := retryAfter(header, now)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package event
+
+import (
+	"context"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// SpreadsheetClient is the subset of the google sheets api used by the sheets sink
+type SpreadsheetClient interface {
+	Get(ctx context.Context, spreadsheetID string) (*sheets.Spreadsheet, error)
+	BatchUpdate(ctx context.Context, spreadsheetID string, req *sheets.BatchUpdateSpreadsheetRequest) (*sheets.BatchUpdateSpreadsheetResponse, error)
+	GetValues(ctx context.Context, spreadsheetID, readRange string) (*sheets.ValueRange, error)
+}
+
+type spreadsheetClient struct {
+	s *sheets.Service
+}
+
+// wrap google sheets service as SpreadsheetClient
+func NewSpreadsheetClient(s *sheets.Service) SpreadsheetClient {
+	return &spreadsheetClient{s: s}
+}
+
+func (c *spreadsheetClient) Get(ctx context.Context, spreadsheetID string) (*sheets.Spreadsheet, error) {
+	return c.s.Spreadsheets.Get(spreadsheetID).Context(ctx).Do()
+}
+
+func (c *spreadsheetClient) BatchUpdate(ctx context.Context, spreadsheetID string, req *sheets.BatchUpdateSpreadsheetRequest) (*sheets.BatchUpdateSpreadsheetResponse, error) {
+	return c.s.Spreadsheets.BatchUpdate(spreadsheetID, req).Context(ctx).Do()
+}
+
+func (c *spreadsheetClient) GetValues(ctx context.Context, spreadsheetID, readRange string) (*sheets.ValueRange, error) {
+	return c.s.Spreadsheets.Values.Get(spreadsheetID, readRange).Context(ctx).Do()
+}
@@ -0,0 +1,376 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"google.golang.org/api/sheets/v4"
+)
+
+var (
+	defaultDashboardSheetID    int64 = 1025
+	dashboardSheetTitle              = "대시보드"
+	dashboardTotalLabel              = "합계"
+	dashboardFixedColumns            = []string{"ID", "이름"}
+	dashboardRoundColumns            = []string{"발표", "참여", "회고"}
+	dashboardTotalColumns            = []string{"총 발표", "총 참여", "총 회고", "참여율"}
+	dashboardRoundHeaderRegexp       = regexp.MustCompile(`^(\d+)R (발표|참여|회고)$`)
+
+	dashboardMarkTrue  = "O"
+	dashboardMarkFalse = "X"
+)
+
+type dashboardRecord struct {
+	Presented  bool
+	Attended   bool
+	Reflection bool
+}
+
+// summary of all rounds of a study, one row per member
+type dashboard struct {
+	rounds  []int8
+	names   map[string]string
+	records map[string]map[int8]dashboardRecord
+}
+
+func newDashboard() *dashboard {
+	return &dashboard{
+		rounds:  []int8{},
+		names:   map[string]string{},
+		records: map[string]map[int8]dashboardRecord{},
+	}
+}
+
+// restore dashboard from the values of dashboard sheet
+func parseDashboard(values [][]interface{}) *dashboard {
+	d := newDashboard()
+
+	if len(values) == 0 {
+		return d
+	}
+
+	// column index to round number and kind of record
+	type column struct {
+		number int8
+		kind   string
+	}
+
+	columns := map[int]column{}
+
+	for i, v := range values[0] {
+		matches := dashboardRoundHeaderRegexp.FindStringSubmatch(fmt.Sprint(v))
+		if matches == nil {
+			continue
+		}
+
+		n, err := strconv.ParseInt(matches[1], 10, 8)
+		if err != nil {
+			continue
+		}
+
+		columns[i] = column{number: int8(n), kind: matches[2]}
+		d.addRound(int8(n))
+	}
+
+	for _, row := range values[1:] {
+		if len(row) == 0 {
+			continue
+		}
+
+		id := fmt.Sprint(row[0])
+		if id == "" || id == dashboardTotalLabel {
+			continue
+		}
+
+		if len(row) > 1 {
+			d.names[id] = fmt.Sprint(row[1])
+		}
+
+		d.records[id] = map[int8]dashboardRecord{}
+
+		for i, c := range columns {
+			if i >= len(row) || fmt.Sprint(row[i]) != dashboardMarkTrue {
+				continue
+			}
+
+			rec := d.records[id][c.number]
+
+			switch c.kind {
+			case "발표":
+				rec.Presented = true
+			case "참여":
+				rec.Attended = true
+			case "회고":
+				rec.Reflection = true
+			}
+
+			d.records[id][c.number] = rec
+		}
+	}
+
+	return d
+}
+
+// merge records of the round, existing records of the same round are overwritten
+func (d *dashboard) merge(r study.Round) {
+	d.addRound(r.Number)
+
+	for id, m := range r.Members {
+		if m.Name != "" || d.names[id] == "" {
+			d.names[id] = m.Name
+		}
+
+		if _, ok := d.records[id]; !ok {
+			d.records[id] = map[int8]dashboardRecord{}
+		}
+
+		d.records[id][r.Number] = dashboardRecord{
+			Presented:  m.Registered,
+			Attended:   m.Attended,
+			Reflection: m.SentReflection,
+		}
+	}
+}
+
+func (d *dashboard) addRound(n int8) {
+	for _, r := range d.rounds {
+		if r == n {
+			return
+		}
+	}
+
+	d.rounds = append(d.rounds, n)
+	sort.Slice(d.rounds, func(i, j int) bool { return d.rounds[i] < d.rounds[j] })
+}
+
+func (d *dashboard) memberIDs() []string {
+	ids := make([]string, 0, len(d.records))
+
+	for id := range d.records {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+func (d *dashboard) rows() []*sheets.RowData {
+	header := &sheets.RowData{}
+
+	for _, label := range dashboardFixedColumns {
+		header.Values = append(header.Values, labelCell(label))
+	}
+
+	for _, n := range d.rounds {
+		for _, kind := range dashboardRoundColumns {
+			header.Values = append(header.Values, labelCell(fmt.Sprintf("%dR %s", n, kind)))
+		}
+	}
+
+	for _, label := range dashboardTotalColumns {
+		header.Values = append(header.Values, labelCell(label))
+	}
+
+	rows := []*sheets.RowData{header}
+
+	roundTotals := make([]int, len(d.rounds)*len(dashboardRoundColumns))
+
+	for _, id := range d.memberIDs() {
+		row := &sheets.RowData{
+			Values: []*sheets.CellData{stringCell(id), stringCell(d.names[id])},
+		}
+
+		var presented, attended, reflection int
+
+		for i, n := range d.rounds {
+			rec := d.records[id][n]
+
+			for j, mark := range []bool{rec.Presented, rec.Attended, rec.Reflection} {
+				row.Values = append(row.Values, markCell(mark))
+
+				if mark {
+					roundTotals[i*len(dashboardRoundColumns)+j]++
+				}
+			}
+
+			if rec.Presented {
+				presented++
+			}
+
+			if rec.Attended {
+				attended++
+			}
+
+			if rec.Reflection {
+				reflection++
+			}
+		}
+
+		row.Values = append(row.Values,
+			numberCell(float64(presented)),
+			numberCell(float64(attended)),
+			numberCell(float64(reflection)),
+			percentCell(attendanceRate(presented, attended)),
+		)
+
+		rows = append(rows, row)
+	}
+
+	totals := &sheets.RowData{
+		Values: []*sheets.CellData{labelCell(dashboardTotalLabel), stringCell("")},
+	}
+
+	for _, n := range roundTotals {
+		totals.Values = append(totals.Values, numberCell(float64(n)))
+	}
+
+	rows = append(rows, totals)
+
+	return rows
+}
+
+// rate of attended presentations to registered ones
+func attendanceRate(presented, attended int) float64 {
+	if presented == 0 {
+		return 0
+	}
+	return float64(attended) / float64(presented)
+}
+
+// rebuild dashboard sheet with the finished round
+func (h *sheetsSink) updateDashboard(ctx context.Context, spreadsheetID string, r study.Round) error {
+	values, err := h.c.GetValues(ctx, spreadsheetID, dashboardSheetTitle)
+	if err != nil {
+		return err
+	}
+
+	d := parseDashboard(values.Values)
+	d.merge(r)
+
+	resp, err := h.batchUpdate(ctx, spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				// clear previous values
+				UpdateCells: &sheets.UpdateCellsRequest{
+					Range:  &sheets.GridRange{SheetId: h.dashboardSheetID},
+					Fields: "userEnteredValue,userEnteredFormat",
+				},
+			},
+			{
+				UpdateCells: &sheets.UpdateCellsRequest{
+					Start:  &sheets.GridCoordinate{SheetId: h.dashboardSheetID},
+					Fields: "*",
+					Rows:   d.rows(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// check status code
+	if resp.HTTPStatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code while updating dashboard: %d", resp.HTTPStatusCode)
+	}
+
+	return nil
+}
+
+func (h *sheetsSink) createDashboardSheet(ctx context.Context, spreadsheetID string) error {
+	// marks of round columns are highlighted
+	markRange := &sheets.GridRange{
+		SheetId:          h.dashboardSheetID,
+		StartRowIndex:    1,
+		StartColumnIndex: int64(len(dashboardFixedColumns)),
+	}
+
+	markRule := func(mark string, color *sheets.Color) *sheets.Request {
+		return &sheets.Request{
+			AddConditionalFormatRule: &sheets.AddConditionalFormatRuleRequest{
+				Rule: &sheets.ConditionalFormatRule{
+					Ranges: []*sheets.GridRange{markRange},
+					BooleanRule: &sheets.BooleanRule{
+						Condition: &sheets.BooleanCondition{
+							Type:   "TEXT_EQ",
+							Values: []*sheets.ConditionValue{{UserEnteredValue: mark}},
+						},
+						Format: &sheets.CellFormat{BackgroundColor: color},
+					},
+				},
+			},
+		}
+	}
+
+	resp, err := h.batchUpdate(ctx, spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AddSheet: &sheets.AddSheetRequest{
+					Properties: &sheets.SheetProperties{
+						Title:     dashboardSheetTitle,
+						SheetId:   h.dashboardSheetID,
+						SheetType: "GRID",
+						Index:     0,
+						GridProperties: &sheets.GridProperties{
+							FrozenRowCount:    1,
+							FrozenColumnCount: int64(len(dashboardFixedColumns)),
+						},
+					},
+				},
+			},
+			markRule(dashboardMarkTrue, &sheets.Color{Red: 0.72, Green: 0.88, Blue: 0.8}),
+			markRule(dashboardMarkFalse, &sheets.Color{Red: 0.96, Green: 0.78, Blue: 0.76}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// check status code
+	if resp.HTTPStatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code while adding sheet: %d", resp.HTTPStatusCode)
+	}
+
+	return nil
+}
+
+func labelCell(s string) *sheets.CellData {
+	return &sheets.CellData{
+		UserEnteredFormat: infoLabelFormat,
+		UserEnteredValue:  &sheets.ExtendedValue{StringValue: &s},
+	}
+}
+
+func stringCell(s string) *sheets.CellData {
+	return &sheets.CellData{
+		UserEnteredValue: &sheets.ExtendedValue{StringValue: &s},
+	}
+}
+
+func markCell(b bool) *sheets.CellData {
+	if b {
+		return stringCell(dashboardMarkTrue)
+	}
+	return stringCell(dashboardMarkFalse)
+}
+
+func numberCell(n float64) *sheets.CellData {
+	return &sheets.CellData{
+		UserEnteredValue: &sheets.ExtendedValue{NumberValue: &n},
+	}
+}
+
+func percentCell(n float64) *sheets.CellData {
+	return &sheets.CellData{
+		UserEnteredValue: &sheets.ExtendedValue{NumberValue: &n},
+		UserEnteredFormat: &sheets.CellFormat{
+			NumberFormat: &sheets.NumberFormat{Type: "PERCENT", Pattern: "0.0%"},
+		},
+	}
+}
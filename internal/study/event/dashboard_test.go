@@ -0,0 +1,104 @@
+package event
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestSheetsSinkSetupCreatesDashboard(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink := NewSheetsSink(c, WithDefaultSpreadsheetID(testSpreadsheetID))
+
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundCreated, "생성")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sh := c.sheet(testSpreadsheetID, defaultDashboardSheetID)
+	if sh == nil {
+		t.Fatalf("dashboard sheet %d not found", defaultDashboardSheetID)
+	}
+
+	if sh.props.Title != dashboardSheetTitle {
+		t.Errorf("title = %q, want %q", sh.props.Title, dashboardSheetTitle)
+	}
+}
+
+func TestSheetsSinkRecordRoundUpdatesDashboard(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink := NewSheetsSink(c, WithDefaultSpreadsheetID(testSpreadsheetID))
+
+	evt := testEvent(t, study.EventTopicStudyRoundFinished, "")
+
+	first := testRound()
+
+	if err := sink.RecordRound(context.Background(), evt, first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// next round without charlie, bob sent reflection
+	second := testRound()
+	second.SetNumber(4)
+	delete(second.Members, "300")
+
+	bob := second.Members["200"]
+	bob.SetSentReflection(true)
+	second.SetMember("200", bob)
+
+	if err := sink.RecordRound(context.Background(), evt, second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sh := c.sheet(testSpreadsheetID, defaultDashboardSheetID)
+
+	want := [][]string{
+		{"ID", "이름", "3R 발표", "3R 참여", "3R 회고", "4R 발표", "4R 참여", "4R 회고", "총 발표", "총 참여", "총 회고", "참여율"},
+		{"100", "alice", "O", "O", "X", "O", "O", "X", "2", "2", "0", "1"},
+		{"200", "bob", "O", "O", "X", "O", "O", "O", "2", "2", "1", "1"},
+		{"300", "charlie", "O", "X", "X", "X", "X", "X", "1", "0", "0", "0"},
+		{"합계", "", "3", "2", "0", "2", "2", "1"},
+	}
+
+	if len(sh.rows) != len(want) {
+		t.Fatalf("rows = %d, want %d", len(sh.rows), len(want))
+	}
+
+	for i, row := range sh.rows {
+		if got := rowValues(row); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("row %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestParseDashboard(t *testing.T) {
+	d := newDashboard()
+	d.merge(testRound())
+
+	var values [][]interface{}
+
+	for _, row := range d.rows() {
+		v := []interface{}{}
+		for _, s := range rowValues(row) {
+			v = append(v, s)
+		}
+		values = append(values, v)
+	}
+
+	parsed := parseDashboard(values)
+
+	if !reflect.DeepEqual(parsed.rounds, d.rounds) {
+		t.Errorf("rounds = %v, want %v", parsed.rounds, d.rounds)
+	}
+
+	if !reflect.DeepEqual(parsed.names, d.names) {
+		t.Errorf("names = %v, want %v", parsed.names, d.names)
+	}
+
+	if !reflect.DeepEqual(parsed.records, d.records) {
+		t.Errorf("records = %v, want %v", parsed.records, d.records)
+	}
+}
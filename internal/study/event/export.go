@@ -0,0 +1,77 @@
+package event
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV      ExportFormat = "csv"
+	ExportFormatJSON     ExportFormat = "json"
+	ExportFormatMarkdown ExportFormat = "md"
+)
+
+// content type of the exported file
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatCSV:
+		return "text/csv"
+	case ExportFormatJSON:
+		return "application/json"
+	case ExportFormatMarkdown:
+		return "text/markdown"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// write rounds in the same layout as the file and markdown sinks
+func ExportRounds(w io.Writer, format ExportFormat, rounds ...study.Round) error {
+	switch format {
+	case ExportFormatCSV:
+		cw := csv.NewWriter(w)
+
+		if err := cw.Write(roundsCSVHeader); err != nil {
+			return err
+		}
+
+		for _, r := range rounds {
+			if err := cw.WriteAll(roundCSVRecords(r)); err != nil {
+				return err
+			}
+		}
+
+		return cw.Error()
+	case ExportFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		// reviewers of the anonymous feedback are not exported
+		public := make([]study.PublicRound, 0, len(rounds))
+
+		for _, r := range rounds {
+			public = append(public, study.NewPublicRound(r))
+		}
+
+		return enc.Encode(public)
+	case ExportFormatMarkdown:
+		docs := make([]string, 0, len(rounds))
+
+		for _, r := range rounds {
+			docs = append(docs, markdownFromRoundData(r))
+		}
+
+		_, err := io.WriteString(w, strings.Join(docs, "\n"))
+		return err
+	default:
+		return errors.Join(ErrUnknownFileFormat, fmt.Errorf("unknown export format: %s", format))
+	}
+}
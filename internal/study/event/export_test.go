@@ -0,0 +1,88 @@
+package event
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestExportRounds(t *testing.T) {
+	first := testRound()
+	second := testRound()
+	second.SetNumber(4)
+	second.SetTitle("채널")
+
+	reviewed := second.Members["100"]
+	reviewed.SetReviewer("200")
+	second.SetMember("100", reviewed)
+
+	t.Run("csv", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		if err := ExportRounds(buf, ExportFormatCSV, first, second); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records, err := csv.NewReader(buf).ReadAll()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// header and one record per member of each round
+		if got, want := len(records), 1+len(first.Members)+len(second.Members); got != want {
+			t.Fatalf("records = %d, want %d", got, want)
+		}
+
+		// first column is the guild
+		if got := records[len(records)-1][1]; got != "4" {
+			t.Errorf("round of last record = %q, want %q", got, "4")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		if err := ExportRounds(buf, ExportFormatJSON, first, second); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var rounds []study.Round
+		if err := json.Unmarshal(buf.Bytes(), &rounds); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(rounds) != 2 || rounds[1].Title != "채널" {
+			t.Errorf("rounds = %+v", rounds)
+		}
+
+		// feedback is anonymous
+		if len(rounds[1].Members["100"].Reviewers) != 0 || strings.Contains(buf.String(), "reviewers") {
+			t.Errorf("reviewers are exported: %s", buf.String())
+		}
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		if err := ExportRounds(buf, ExportFormatMarkdown, first, second); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, heading := range []string{"# 3 라운드: 고루틴", "# 4 라운드: 채널"} {
+			if !strings.Contains(buf.String(), heading) {
+				t.Errorf("missing heading %q", heading)
+			}
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		if err := ExportRounds(&bytes.Buffer{}, ExportFormat("xml"), first); !errors.Is(err, ErrUnknownFileFormat) {
+			t.Errorf("err = %v, want %v", err, ErrUnknownFileFormat)
+		}
+	})
+}
@@ -0,0 +1,253 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+type fakeSheet struct {
+	props *sheets.SheetProperties
+	rows  []*sheets.RowData
+}
+
+// in-memory spreadsheets implementing SpreadsheetClient
+type fakeSpreadsheetClient struct {
+	mtx *sync.Mutex
+
+	spreadsheets map[string][]*fakeSheet
+
+	gets         int
+	batchUpdates int
+
+	failures []error // returned by the next batch updates in order, nil lets the update through
+}
+
+func newFakeSpreadsheetClient(spreadsheetID string, existing ...*sheets.SheetProperties) *fakeSpreadsheetClient {
+	f := &fakeSpreadsheetClient{
+		mtx:          &sync.Mutex{},
+		spreadsheets: map[string][]*fakeSheet{},
+	}
+
+	f.addSpreadsheet(spreadsheetID, existing...)
+
+	return f
+}
+
+func (f *fakeSpreadsheetClient) addSpreadsheet(spreadsheetID string, existing ...*sheets.SheetProperties) {
+	defer f.mtx.Unlock()
+	f.mtx.Lock()
+
+	sheets := []*fakeSheet{}
+
+	for _, props := range existing {
+		sheets = append(sheets, &fakeSheet{props: props})
+	}
+
+	f.spreadsheets[spreadsheetID] = sheets
+}
+
+func (f *fakeSpreadsheetClient) Get(_ context.Context, spreadsheetID string) (*sheets.Spreadsheet, error) {
+	defer f.mtx.Unlock()
+	f.mtx.Lock()
+
+	f.gets++
+
+	current, ok := f.spreadsheets[spreadsheetID]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Requested entity was not found."}
+	}
+
+	resp := &sheets.Spreadsheet{
+		SpreadsheetId:  spreadsheetID,
+		ServerResponse: googleapi.ServerResponse{HTTPStatusCode: http.StatusOK},
+	}
+
+	for _, sh := range current {
+		resp.Sheets = append(resp.Sheets, &sheets.Sheet{Properties: sh.props})
+	}
+
+	return resp, nil
+}
+
+// requests are applied atomically like the real api
+func (f *fakeSpreadsheetClient) BatchUpdate(_ context.Context, spreadsheetID string, req *sheets.BatchUpdateSpreadsheetRequest) (*sheets.BatchUpdateSpreadsheetResponse, error) {
+	defer f.mtx.Unlock()
+	f.mtx.Lock()
+
+	if len(f.failures) > 0 {
+		err := f.failures[0]
+		f.failures = f.failures[1:]
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	current, ok := f.spreadsheets[spreadsheetID]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Requested entity was not found."}
+	}
+
+	staged := make([]*fakeSheet, 0, len(current))
+	for _, sh := range current {
+		staged = append(staged, &fakeSheet{props: sh.props, rows: append([]*sheets.RowData{}, sh.rows...)})
+	}
+
+	for _, r := range req.Requests {
+		switch {
+		case r.AddSheet != nil:
+			props := r.AddSheet.Properties
+
+			for _, sh := range staged {
+				if sh.props.SheetId == props.SheetId || sh.props.Title == props.Title {
+					return nil, &googleapi.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("sheet already exists: %d %s", props.SheetId, props.Title)}
+				}
+			}
+
+			staged = append(staged, &fakeSheet{props: props})
+		case r.AppendCells != nil:
+			sh := findFakeSheet(staged, r.AppendCells.SheetId)
+			if sh == nil {
+				return nil, &googleapi.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("no sheet with id: %d", r.AppendCells.SheetId)}
+			}
+
+			sh.rows = append(sh.rows, r.AppendCells.Rows...)
+		case r.UpdateCells != nil:
+			// only whole sheet clear and overwrite from the first cell are supported
+			var id int64
+			if r.UpdateCells.Range != nil {
+				id = r.UpdateCells.Range.SheetId
+			} else {
+				id = r.UpdateCells.Start.SheetId
+			}
+
+			sh := findFakeSheet(staged, id)
+			if sh == nil {
+				return nil, &googleapi.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("no sheet with id: %d", id)}
+			}
+
+			if r.UpdateCells.Range != nil {
+				sh.rows = nil
+				continue
+			}
+
+			sh.rows = append([]*sheets.RowData{}, r.UpdateCells.Rows...)
+		case r.AddConditionalFormatRule != nil:
+			for _, gr := range r.AddConditionalFormatRule.Rule.Ranges {
+				if findFakeSheet(staged, gr.SheetId) == nil {
+					return nil, &googleapi.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("no sheet with id: %d", gr.SheetId)}
+				}
+			}
+		default:
+			return nil, &googleapi.Error{Code: http.StatusBadRequest, Message: "unsupported request"}
+		}
+	}
+
+	f.spreadsheets[spreadsheetID] = staged
+	f.batchUpdates++
+
+	return &sheets.BatchUpdateSpreadsheetResponse{
+		SpreadsheetId:  spreadsheetID,
+		ServerResponse: googleapi.ServerResponse{HTTPStatusCode: http.StatusOK},
+	}, nil
+}
+
+// read range is the title of a sheet, optionally quoted
+func (f *fakeSpreadsheetClient) GetValues(_ context.Context, spreadsheetID string, readRange string) (*sheets.ValueRange, error) {
+	defer f.mtx.Unlock()
+	f.mtx.Lock()
+
+	title := readRange
+	if len(title) > 1 && strings.HasPrefix(title, "'") && strings.HasSuffix(title, "'") {
+		title = strings.ReplaceAll(title[1:len(title)-1], "''", "'")
+	}
+
+	current, ok := f.spreadsheets[spreadsheetID]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Requested entity was not found."}
+	}
+
+	for _, sh := range current {
+		if sh.props.Title != title {
+			continue
+		}
+
+		resp := &sheets.ValueRange{
+			Range:          readRange,
+			ServerResponse: googleapi.ServerResponse{HTTPStatusCode: http.StatusOK},
+		}
+
+		for _, row := range sh.rows {
+			values := []interface{}{}
+			for _, v := range rowValues(row) {
+				values = append(values, v)
+			}
+			resp.Values = append(resp.Values, values)
+		}
+
+		return resp, nil
+	}
+
+	return nil, &googleapi.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("Unable to parse range: %s", readRange)}
+}
+
+func (f *fakeSpreadsheetClient) fail(errs ...error) {
+	defer f.mtx.Unlock()
+	f.mtx.Lock()
+
+	f.failures = append(f.failures, errs...)
+}
+
+func (f *fakeSpreadsheetClient) sheet(spreadsheetID string, id int64) *fakeSheet {
+	defer f.mtx.Unlock()
+	f.mtx.Lock()
+
+	return findFakeSheet(f.spreadsheets[spreadsheetID], id)
+}
+
+func findFakeSheet(sheets []*fakeSheet, id int64) *fakeSheet {
+	for _, sh := range sheets {
+		if sh.props.SheetId == id {
+			return sh
+		}
+	}
+	return nil
+}
+
+// string representation of the values of a row
+func rowValues(row *sheets.RowData) []string {
+	values := make([]string, 0, len(row.Values))
+
+	for _, c := range row.Values {
+		values = append(values, cellValue(c))
+	}
+
+	return values
+}
+
+func cellValue(c *sheets.CellData) string {
+	v := c.UserEnteredValue
+	if v == nil {
+		return ""
+	}
+
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.FormulaValue != nil:
+		return *v.FormulaValue
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	case v.NumberValue != nil:
+		return strconv.FormatFloat(*v.NumberValue, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
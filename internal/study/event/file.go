@@ -0,0 +1,157 @@
+package event
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+type FileFormat string
+
+const (
+	FileFormatCSV   FileFormat = "csv"
+	FileFormatJSONL FileFormat = "jsonl"
+)
+
+var ErrUnknownFileFormat = errors.New("unknown file format")
+
+var (
+	roundsFileName   = "rounds"
+	progressFileName = "progress"
+
+	roundsCSVHeader   = []string{"길드", "라운드", "제목", "진행 단계", "녹화 영상", "ID", "이름", "발표 주제", "발표 자료", "발표 참여", "생성", "최종 수정"}
+	progressCSVHeader = []string{"길드", "진행 상태", "설명", "시간"}
+)
+
+type fileSink struct {
+	dir    string
+	format FileFormat
+
+	mtx *sync.Mutex
+}
+
+// create sink which appends rounds and progress logs to local files
+func NewFileSink(dir string, format FileFormat) (Sink, error) {
+	switch format {
+	case FileFormatCSV, FileFormatJSONL:
+	default:
+		return nil, errors.Join(ErrUnknownFileFormat, fmt.Errorf("unknown file format: %s", format))
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &fileSink{
+		dir:    dir,
+		format: format,
+		mtx:    &sync.Mutex{},
+	}, nil
+}
+
+// append one record per member of the round
+func (f *fileSink) RecordRound(_ context.Context, _ study.Event, r study.Round) error {
+	defer f.mtx.Unlock()
+	f.mtx.Lock()
+
+	if f.format == FileFormatJSONL {
+		return f.appendJSON(roundsFileName, r)
+	}
+
+	return f.appendCSV(roundsFileName, roundsCSVHeader, roundCSVRecords(r)...)
+}
+
+// append progress log
+func (f *fileSink) RecordProgress(_ context.Context, evt study.Event) error {
+	defer f.mtx.Unlock()
+	f.mtx.Lock()
+
+	if f.format == FileFormatJSONL {
+		return f.appendJSON(progressFileName, evt)
+	}
+
+	return f.appendCSV(progressFileName, progressCSVHeader, []string{
+		evt.GuildID,
+		evt.Topic.String(),
+		evt.Description,
+		time.Unix(evt.Timestamp, 0).Format(time.RFC3339),
+	})
+}
+
+// one record per member of the round, rounds of all guilds are written to the same file
+func roundCSVRecords(r study.Round) [][]string {
+	records := make([][]string, 0, len(r.Members))
+
+	for _, id := range sortedMemberIDs(r) {
+		m := r.Members[id]
+
+		records = append(records, []string{
+			r.GuildID,
+			strconv.Itoa(int(r.Number)),
+			r.Title,
+			r.Stage.String(),
+			r.ContentURL,
+			id,
+			m.Name,
+			m.Subject,
+			m.ContentURL,
+			strconv.FormatBool(m.Attended),
+			r.CreatedAt.Format(time.RFC3339),
+			r.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return records
+}
+
+func (f *fileSink) appendJSON(name string, v any) error {
+	file, err := f.open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	return json.NewEncoder(file).Encode(v)
+}
+
+func (f *fileSink) appendCSV(name string, header []string, records ...[]string) error {
+	file, err := f.open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(file)
+
+	// write header to new file
+	if info.Size() == 0 {
+		if err := w.Write(header); err != nil {
+			return err
+		}
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+
+	return w.Error()
+}
+
+func (f *fileSink) open(name string) (*os.File, error) {
+	path := filepath.Join(f.dir, fmt.Sprintf("%s.%s", name, f.format))
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+}
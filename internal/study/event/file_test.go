@@ -0,0 +1,156 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func testFileRound(guildID string, number int8) study.Round {
+	r := study.NewRound()
+	r.SetGuildID(guildID)
+	r.SetNumber(number)
+	r.SetTitle("Go 동시성")
+
+	m := study.NewMember()
+	m.SetName("alice")
+	m.SetSubject("채널 | 뮤텍스")
+	r.SetMember("u1", m)
+
+	return r
+}
+
+func TestFileSinkCSVWritesHeaderOnce(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewFileSink(dir, FileFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evt, _ := study.NewEvent(study.EventTopicStudyRoundFinished, "")
+
+	for _, guildID := range []string{"g1", "g2"} {
+		if err := sink.RecordRound(context.Background(), evt, testFileRound(guildID, 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(filepath.Join(dir, "rounds.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 records, got %d", len(records))
+	}
+
+	if strings.Join(records[0], ",") != strings.Join(roundsCSVHeader, ",") {
+		t.Fatalf("unexpected header %v", records[0])
+	}
+
+	// rounds of guilds are distinguished by the guild column
+	if records[1][0] != "g1" || records[2][0] != "g2" {
+		t.Fatalf("unexpected guild columns %s, %s", records[1][0], records[2][0])
+	}
+}
+
+func TestFileSinkJSONLAppends(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewFileSink(dir, FileFormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wh, _ := study.NewWebhook("https://example.com/hook")
+
+	for _, desc := range []string{"first", "second"} {
+		evt, _ := study.NewEvent(study.EventTopicStudyRoundProgress, desc)
+		evt.SetTarget("g1", "")
+		evt.SetWebhooks([]study.Webhook{wh})
+
+		if err := sink.RecordProgress(context.Background(), evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(filepath.Join(dir, "progress.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var lines []study.Event
+
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		var evt study.Event
+		if err := json.Unmarshal(sc.Bytes(), &evt); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, evt)
+	}
+
+	if len(lines) != 2 || lines[0].Description != "first" || lines[1].Description != "second" {
+		t.Fatalf("unexpected lines %+v", lines)
+	}
+
+	if lines[0].GuildID != "g1" || len(lines[0].WebhookIDs) != 1 || lines[0].WebhookIDs[0] != wh.ID {
+		t.Fatalf("expected guild id and webhook id, got %+v", lines[0])
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "progress.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), wh.Secret) {
+		t.Fatal("secret of the webhook is written")
+	}
+}
+
+func TestMarkdownSinkEscapesTable(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewMarkdownSink(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evt, _ := study.NewEvent(study.EventTopicStudyRoundFinished, "")
+
+	r := testFileRound("g1", 3)
+
+	m, _ := r.GetMember("u1")
+	m.SetName("bob\nsmith")
+	r.SetMember("u1", m)
+
+	if err := sink.RecordRound(context.Background(), evt, r); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "g1", "round-003.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := string(b)
+
+	if !strings.Contains(md, `| u1 | bob smith | 채널 \| 뮤텍스 | 미등록 | X |`) {
+		t.Fatalf("unexpected member row:\n%s", md)
+	}
+}
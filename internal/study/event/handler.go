@@ -5,96 +5,37 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
-	"time"
+	"sync"
 
 	"github.com/piatoss3612/my-study-bot/internal/pubsub"
 	"github.com/piatoss3612/my-study-bot/internal/study"
-	"google.golang.org/api/sheets/v4"
 )
 
-var (
-	defaultProgressSheetID int64 = 1024
-	infoLabelFormat              = &sheets.CellFormat{
-		TextFormat: &sheets.TextFormat{
-			Bold: true,
-			ForegroundColor: &sheets.Color{
-				Red:   1.0,
-				Green: 1.0,
-				Blue:  1.0,
-			},
-		},
-		BackgroundColor: &sheets.Color{
-			Blue: 0.8,
-		},
-		HorizontalAlignment: "CENTER",
-	}
-)
+// max number of partially recorded events remembered, the oldest is forgotten first
+const maxPartialEvents = 1024
 
 type handler struct {
-	s               *sheets.Service
-	spreadsheetID   string
-	progressSheetID int64
-}
+	sinks []Sink
 
-type HandlerOptsFunc func(*handler)
-
-func WithDefaultProgressSheetID() HandlerOptsFunc {
-	return func(h *handler) {
-		h.progressSheetID = defaultProgressSheetID
-	}
+	mtx      *sync.Mutex
+	recorded map[string]map[int]bool // event id to indices of sinks which recorded it
+	order    []string                // ids of partially recorded events in arrival order
 }
 
-func WithProgressSheetID(id int64) HandlerOptsFunc {
-	return func(h *handler) {
-		h.progressSheetID = id
+// create handler which exports round events to the sinks
+func New(sinks ...Sink) pubsub.Consumer {
+	return &handler{
+		sinks:    sinks,
+		mtx:      &sync.Mutex{},
+		recorded: map[string]map[int]bool{},
 	}
 }
 
-func New(ctx context.Context, s *sheets.Service, spreadSheetID string, opts ...HandlerOptsFunc) (pubsub.Handler, error) {
-	h := &handler{
-		s:               s,
-		spreadsheetID:   spreadSheetID,
-		progressSheetID: defaultProgressSheetID,
-	}
-
-	for _, opt := range opts {
-		opt(h)
-	}
-
-	return h.setup(ctx)
-}
-
-// setup progress sheet
-func (h *handler) setup(ctx context.Context) (pubsub.Handler, error) {
-	// get spreadsheet
-	resp, err := h.s.Spreadsheets.Get(h.spreadsheetID).Context(ctx).Do()
-	if err != nil {
-		return nil, err
-	}
-
-	// check status code
-	if resp.HTTPStatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code while getting spreadsheet: %d", resp.HTTPStatusCode)
-	}
-
-	// check event sheet exists
-	var progressSheetExists bool
-
-	for _, sheet := range resp.Sheets {
-		if sheet.Properties.SheetId == h.progressSheetID {
-			progressSheetExists = true
-		}
-	}
-
-	if !progressSheetExists {
-		// create progress sheet
-		if err := h.createProgressSheet(ctx); err != nil {
-			return nil, err
-		}
-	}
-
-	return h, nil
+// register handler for round events
+func (h *handler) Register(m pubsub.Mapper) {
+	m.Register(study.EventTopicStudyRoundCreated.String(), h)
+	m.Register(study.EventTopicStudyRoundProgress.String(), h)
+	m.Register(study.EventTopicStudyRoundFinished.String(), h)
 }
 
 func (h *handler) Handle(ctx context.Context, body []byte) error {
@@ -104,9 +45,13 @@ func (h *handler) Handle(ctx context.Context, body []byte) error {
 		return err
 	}
 
+	var record func(s Sink) error
+
 	switch evt.Topic {
 	case study.EventTopicStudyRoundCreated, study.EventTopicStudyRoundProgress:
-		return h.recordProgress(ctx, evt)
+		record = func(s Sink) error {
+			return s.RecordProgress(ctx, evt)
+		}
 	case study.EventTopicStudyRoundFinished:
 		var r study.Round
 
@@ -114,384 +59,83 @@ func (h *handler) Handle(ctx context.Context, body []byte) error {
 			return err
 		}
 
-		return h.recordRound(ctx, r)
+		record = func(s Sink) error {
+			return s.RecordRound(ctx, evt, r)
+		}
 	default:
 		return errors.Join(study.ErrUnknownEventTopic, fmt.Errorf("unknown event topic: %s", evt.Topic))
 	}
-}
 
-// record round data to spreadsheet
-func (h *handler) recordRound(ctx context.Context, r study.Round) error {
-	addSheetReq := &sheets.AddSheetRequest{
-		Properties: &sheets.SheetProperties{
-			Title:     fmt.Sprintf("%d 라운드: %s", r.Number, r.Title),
-			SheetId:   int64(r.Number),
-			SheetType: "GRID",
-			TabColor: &sheets.Color{
-				Blue: 1.0,
-			},
-		},
-	}
+	// failure of a sink should not prevent others from recording,
+	// sinks which recorded the event are skipped when the event is redelivered
+	recorded := h.recordedSinks(evt.ID)
 
-	rows := rowsFromRoundData(r)
+	var errs []error
 
-	appendCellsReq := &sheets.AppendCellsRequest{
-		SheetId: int64(r.Number),
-		Fields:  "*",
-		Rows:    rows,
-	}
+	for n, s := range h.sinks {
+		if recorded[n] {
+			continue
+		}
 
-	resp, err := h.s.Spreadsheets.BatchUpdate(h.spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: []*sheets.Request{
-			{
-				AddSheet: addSheetReq, // create sheet
-			},
-			{
-				AppendCells: appendCellsReq, // then add rows
-			},
-		},
-	}).Context(ctx).Do()
-	if err != nil {
-		return err
-	}
+		if err := record(s); err != nil {
+			errs = append(errs, err)
+			continue
+		}
 
-	// check status code
-	if resp.HTTPStatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code while adding sheet: %d", resp.HTTPStatusCode)
+		recorded[n] = true
 	}
 
-	return nil
+	h.setRecordedSinks(evt.ID, recorded, len(errs) == 0)
+
+	return errors.Join(errs...)
 }
 
-func (h *handler) recordProgress(ctx context.Context, evt study.Event) error {
-	resp, err := h.s.Spreadsheets.BatchUpdate(h.spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: []*sheets.Request{
-			{
-				AppendCells: &sheets.AppendCellsRequest{
-					SheetId: h.progressSheetID,
-					Fields:  "*",
-					Rows: []*sheets.RowData{
-						{
-							Values: []*sheets.CellData{
-								{
-									UserEnteredValue: &sheets.ExtendedValue{
-										StringValue: func() *string {
-											s := evt.Topic.String()
-											return &s
-										}(),
-									},
-								},
-								{
-									UserEnteredValue: &sheets.ExtendedValue{
-										StringValue: func() *string {
-											s := evt.Description
-											return &s
-										}(),
-									},
-								},
-								{
-									UserEnteredValue: &sheets.ExtendedValue{
-										StringValue: func() *string {
-											s := time.Unix(evt.Timestamp, 0).Format(time.RFC3339)
-											return &s
-										}(),
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}).Context(ctx).Do()
-	if err != nil {
-		return err
-	}
+// copy of the sinks which recorded the event
+func (h *handler) recordedSinks(id string) map[int]bool {
+	defer h.mtx.Unlock()
+	h.mtx.Lock()
+
+	recorded := make(map[int]bool, len(h.sinks))
 
-	// check status code
-	if resp.HTTPStatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.HTTPStatusCode)
+	for n := range h.recorded[id] {
+		recorded[n] = true
 	}
 
-	return nil
+	return recorded
 }
 
-func (h *handler) createProgressSheet(ctx context.Context) error {
-	addSheetReq := &sheets.AddSheetRequest{
-		Properties: &sheets.SheetProperties{
-			Title:     "진행 로그",
-			SheetId:   h.progressSheetID,
-			SheetType: "GRID",
-		},
+// remember sinks which recorded the event until all of them do,
+// events without id are always recorded to every sink
+func (h *handler) setRecordedSinks(id string, recorded map[int]bool, completed bool) {
+	if id == "" {
+		return
 	}
 
-	appendCellsReq := &sheets.AppendCellsRequest{
-		SheetId: h.progressSheetID,
-		Fields:  "*",
-		Rows: []*sheets.RowData{
-			{
-				Values: []*sheets.CellData{
-					{
-						UserEnteredFormat: infoLabelFormat,
-						UserEnteredValue: &sheets.ExtendedValue{
-							StringValue: func() *string {
-								s := "진행 상태"
-								return &s
-							}(),
-						},
-					},
-					{
-						UserEnteredFormat: infoLabelFormat,
-						UserEnteredValue: &sheets.ExtendedValue{
-							StringValue: func() *string {
-								s := "설명"
-								return &s
-							}(),
-						},
-					},
-					{
-						UserEnteredFormat: infoLabelFormat,
-						UserEnteredValue: &sheets.ExtendedValue{
-							StringValue: func() *string {
-								s := "시간"
-								return &s
-							}(),
-						},
-					},
-				},
-			},
-		},
-	}
+	defer h.mtx.Unlock()
+	h.mtx.Lock()
 
-	resp, err := h.s.Spreadsheets.BatchUpdate(h.spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: []*sheets.Request{
-			{
-				AddSheet: addSheetReq,
-			},
-			{
-				AppendCells: appendCellsReq,
-			},
-		},
-	}).Context(ctx).Do()
-	if err != nil {
-		return err
-	}
+	if completed {
+		if _, ok := h.recorded[id]; ok {
+			delete(h.recorded, id)
 
-	// check status code
-	if resp.HTTPStatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code while adding sheet: %d", resp.HTTPStatusCode)
+			for n, partial := range h.order {
+				if partial == id {
+					h.order = append(h.order[:n], h.order[n+1:]...)
+					break
+				}
+			}
+		}
+		return
 	}
 
-	return nil
-}
-
-func rowsFromRoundData(r study.Round) []*sheets.RowData {
-	rows := []*sheets.RowData{
-		{
-			Values: []*sheets.CellData{
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "제목"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := r.Title
-							return &s
-						}(),
-					},
-				},
-			},
-		},
-		{
-			Values: []*sheets.CellData{
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "진행 단계"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := r.Stage.String()
-							return &s
-						}(),
-					},
-				},
-			},
-		},
-		{
-			Values: []*sheets.CellData{
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "녹화 영상"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						FormulaValue: func() *string {
-							s := fmt.Sprintf(`=HYPERLINK("%s")`, r.ContentURL)
-							return &s
-						}(),
-					},
-				},
-			},
-		},
-		{
-			Values: []*sheets.CellData{
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "생성"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := r.CreatedAt.Format(time.RFC3339)
-							return &s
-						}(),
-					},
-				},
-			},
-		},
-		{
-			Values: []*sheets.CellData{
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "최종 수정"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := r.UpdatedAt.Format(time.RFC3339)
-							return &s
-						}(),
-					},
-				},
-			},
-		},
-		{}, // empty row
-		{
-			Values: []*sheets.CellData{
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "ID"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "이름"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "발표 주제"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "발표 자료"
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredFormat: infoLabelFormat,
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := "발표 참여"
-							return &s
-						}(),
-					},
-				},
-			},
-		},
+	if _, ok := h.recorded[id]; !ok {
+		h.order = append(h.order, id)
 	}
 
-	for id, m := range r.Members {
-		row := &sheets.RowData{
-			Values: []*sheets.CellData{
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := id
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := m.Name
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						StringValue: func() *string {
-							s := m.Subject
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						FormulaValue: func() *string {
-							s := fmt.Sprintf(`=HYPERLINK("%s")`, m.ContentURL)
-							return &s
-						}(),
-					},
-				},
-				{
-					UserEnteredValue: &sheets.ExtendedValue{
-						BoolValue: func() *bool {
-							b := m.Attended
-							return &b
-						}(),
-					},
-				},
-			},
-		}
+	h.recorded[id] = recorded
 
-		rows = append(rows, row)
+	for len(h.order) > maxPartialEvents {
+		delete(h.recorded, h.order[0])
+		h.order = h.order[1:]
 	}
-
-	return rows
 }
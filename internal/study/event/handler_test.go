@@ -0,0 +1,90 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// sink counting records, failing while failures are left
+type countingSink struct {
+	progress int
+	failures int
+}
+
+func (s *countingSink) RecordRound(context.Context, study.Event, study.Round) error {
+	return nil
+}
+
+func (s *countingSink) RecordProgress(context.Context, study.Event) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+
+	s.progress++
+	return nil
+}
+
+func TestHandlerSkipsRecordedSinksOnRedelivery(t *testing.T) {
+	ok := &countingSink{}
+	flaky := &countingSink{failures: 1}
+
+	h := New(ok, flaky).(*handler)
+
+	evt := testEvent(t, study.EventTopicStudyRoundProgress, "progress")
+	evt.SetTarget("guild", "")
+
+	body, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Handle(context.Background(), body); err == nil {
+		t.Fatal("expected error of the flaky sink")
+	}
+
+	// redelivered event is recorded only to the sink which failed
+	if err := h.Handle(context.Background(), body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ok.progress != 1 || flaky.progress != 1 {
+		t.Fatalf("expected each sink to record once, got %d and %d", ok.progress, flaky.progress)
+	}
+
+	if len(h.recorded) != 0 || len(h.order) != 0 {
+		t.Fatalf("completed event is still remembered: %v %v", h.recorded, h.order)
+	}
+
+	// event without id is recorded to every sink
+	evt.ID = ""
+
+	body, _ = json.Marshal(evt)
+
+	if err := h.Handle(context.Background(), body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ok.progress != 2 || flaky.progress != 2 {
+		t.Fatalf("expected each sink to record twice, got %d and %d", ok.progress, flaky.progress)
+	}
+}
+
+func TestHandlerForgetsOldestPartialEvents(t *testing.T) {
+	h := New(&countingSink{failures: maxPartialEvents + 1}).(*handler)
+
+	for n := 0; n <= maxPartialEvents; n++ {
+		evt := testEvent(t, study.EventTopicStudyRoundProgress, "progress")
+		body, _ := json.Marshal(evt)
+
+		_ = h.Handle(context.Background(), body)
+	}
+
+	if len(h.recorded) != maxPartialEvents || len(h.order) != maxPartialEvents {
+		t.Fatalf("expected %d partial events, got %d and %d", maxPartialEvents, len(h.recorded), len(h.order))
+	}
+}
@@ -0,0 +1,82 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+type markdownSink struct {
+	dir string
+}
+
+// create sink which archives each round as a markdown file
+func NewMarkdownSink(dir string) (Sink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &markdownSink{dir: dir}, nil
+}
+
+// write round to its own file under the guild directory, the file is overwritten if exists
+func (m *markdownSink) RecordRound(_ context.Context, _ study.Event, r study.Round) error {
+	dir := filepath.Join(m.dir, r.GuildID)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("round-%03d.md", r.Number)
+	return os.WriteFile(filepath.Join(dir, name), []byte(markdownFromRoundData(r)), 0o644)
+}
+
+// progress logs are not archived
+func (m *markdownSink) RecordProgress(_ context.Context, _ study.Event) error {
+	return nil
+}
+
+func markdownFromRoundData(r study.Round) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "# %d 라운드: %s\n\n", r.Number, r.Title)
+	fmt.Fprintf(b, "- 진행 단계: %s\n", r.Stage.String())
+	fmt.Fprintf(b, "- 녹화 영상: %s\n", markdownLink(r.ContentURL))
+	fmt.Fprintf(b, "- 생성: %s\n", r.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(b, "- 최종 수정: %s\n\n", r.UpdatedAt.Format(time.RFC3339))
+
+	b.WriteString("| ID | 이름 | 발표 주제 | 발표 자료 | 발표 참여 |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, id := range sortedMemberIDs(r) {
+		mem := r.Members[id]
+
+		attended := "X"
+		if mem.Attended {
+			attended = "O"
+		}
+
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n",
+			id, markdownEscape(mem.Name), markdownEscape(mem.Subject), markdownLink(mem.ContentURL), attended)
+	}
+
+	return b.String()
+}
+
+func markdownLink(url string) string {
+	if url == "" {
+		return "미등록"
+	}
+	return fmt.Sprintf("<%s>", url)
+}
+
+// escape characters breaking table layout
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
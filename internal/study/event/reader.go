@@ -0,0 +1,158 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+var hyperlinkRegexp = regexp.MustCompile(`^=HYPERLINK\("(.*)"\)$`)
+
+// RoundSheetReader reads round data edited on the round sheet
+type RoundSheetReader interface {
+	ReadRound(ctx context.Context, spreadsheetURL string, number int8) (study.Round, error)
+}
+
+type roundSheetReader struct {
+	c SpreadsheetClient
+}
+
+func NewRoundSheetReader(c SpreadsheetClient) RoundSheetReader {
+	return &roundSheetReader{c: c}
+}
+
+func (rr *roundSheetReader) ReadRound(ctx context.Context, spreadsheetURL string, number int8) (study.Round, error) {
+	spreadsheetID, err := study.SpreadsheetIDFromURL(spreadsheetURL)
+	if err != nil {
+		return study.Round{}, err
+	}
+
+	resp, err := rr.c.Get(ctx, spreadsheetID)
+	if err != nil {
+		return study.Round{}, err
+	}
+
+	// round sheet has the round number as its id, title may be renamed by managers
+	var title string
+
+	for _, sheet := range resp.Sheets {
+		if sheet.Properties.SheetId == int64(number) {
+			title = sheet.Properties.Title
+		}
+	}
+
+	if title == "" {
+		return study.Round{}, study.ErrRoundSheetNotFound
+	}
+
+	values, err := rr.c.GetValues(ctx, spreadsheetID, sheetRange(title))
+	if err != nil {
+		return study.Round{}, err
+	}
+
+	r, err := roundFromValues(values.Values)
+	if err != nil {
+		return study.Round{}, err
+	}
+
+	r.SetNumber(number)
+
+	return r, nil
+}
+
+// quote the sheet title to be used as a1 notation
+func sheetRange(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
+
+// restore round from the values of the round sheet written by rowsFromRoundData
+func roundFromValues(values [][]interface{}) (study.Round, error) {
+	r := study.NewRound()
+
+	var header map[string]int
+
+	for _, row := range values {
+		if len(row) == 0 {
+			continue
+		}
+
+		first := cellString(row, 0)
+
+		if header == nil {
+			// member table starts with the header row
+			if first == "ID" {
+				header = map[string]int{}
+
+				for i := range row {
+					header[cellString(row, i)] = i
+				}
+
+				continue
+			}
+
+			switch first {
+			case "제목":
+				r.SetTitle(cellString(row, 1))
+			case "녹화 영상":
+				r.SetContentURL(hyperlinkTarget(cellString(row, 1)))
+			}
+
+			continue
+		}
+
+		if first == "" {
+			continue
+		}
+
+		m := study.NewMember()
+
+		if i, ok := header["이름"]; ok {
+			m.SetName(cellString(row, i))
+		}
+
+		if i, ok := header["발표 주제"]; ok {
+			m.SetSubject(cellString(row, i))
+		}
+
+		if i, ok := header["발표 자료"]; ok {
+			m.SetContentURL(hyperlinkTarget(cellString(row, i)))
+		}
+
+		if i, ok := header["발표 참여"]; ok {
+			attended, err := strconv.ParseBool(strings.ToLower(cellString(row, i)))
+			if err != nil {
+				return study.Round{}, errors.Join(study.ErrInvalidRoundChange, fmt.Errorf("발표 참여 여부를 확인해주세요 (ID: %s)", first))
+			}
+
+			m.SetAttended(attended)
+		}
+
+		r.SetMember(first, m)
+	}
+
+	if header == nil {
+		return study.Round{}, study.ErrRoundSheetNotFound
+	}
+
+	return r, nil
+}
+
+func cellString(row []interface{}, i int) string {
+	if i >= len(row) || row[i] == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(row[i]))
+}
+
+// hyperlink cells are read as formula or as its displayed url
+func hyperlinkTarget(s string) string {
+	if matches := hyperlinkRegexp.FindStringSubmatch(s); matches != nil {
+		return matches[1]
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+const testSpreadsheetURL = "https://docs.google.com/spreadsheets/d/" + testSpreadsheetID + "/edit"
+
+func TestRoundSheetReaderReadRound(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	r := testRound()
+	r.SetTitle("it's 고루틴") // quote in the sheet title
+
+	if err := NewSheetsSink(c, WithDefaultSpreadsheetID(testSpreadsheetID)).RecordRound(context.Background(), testEvent(t, study.EventTopicStudyRoundFinished, ""), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := NewRoundSheetReader(c).ReadRound(context.Background(), testSpreadsheetURL, r.Number)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// unchanged sheet has nothing to sync
+	if changes := study.DiffRound(r, got); len(changes) != 0 {
+		t.Errorf("changes = %v, want none", changes)
+	}
+}
+
+func TestRoundSheetReaderNotFound(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	_, err := NewRoundSheetReader(c).ReadRound(context.Background(), testSpreadsheetURL, 1)
+	if !errors.Is(err, study.ErrRoundSheetNotFound) {
+		t.Fatalf("err = %v, want %v", err, study.ErrRoundSheetNotFound)
+	}
+}
+
+func TestRoundFromValuesDiff(t *testing.T) {
+	r := testRound()
+
+	// values edited by managers, displayed values of formulas and booleans
+	values := [][]interface{}{
+		{"제목", "고루틴 심화"},
+		{"진행 단계", "라운드 종료"},
+		{"녹화 영상", "https://youtu.be/record"},
+		{},
+		{"ID", "이름", "발표 주제", "발표 자료", "발표 참여"},
+		{"100", "alice", "고루틴", "https://a.com", "TRUE"},
+		{"200", "bobby", "뮤텍스", "https://b.com", "TRUE"},
+		{"300", "charlie", "채널", "https://c.com", "TRUE"},
+		{"999", "unknown", "", "", "FALSE"},
+	}
+
+	edited, err := roundFromValues(values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []study.RoundChange{
+		{Field: study.RoundFieldTitle, Old: "고루틴", New: "고루틴 심화"},
+		{MemberID: "200", Field: study.RoundFieldMemberName, Old: "bob", New: "bobby"},
+		{MemberID: "300", Field: study.RoundFieldMemberAttended, Old: "false", New: "true"},
+	}
+
+	changes := study.DiffRound(r, edited)
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+
+	for _, c := range changes {
+		if err := r.ApplyChange(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if changes := study.DiffRound(r, edited); len(changes) != 0 {
+		t.Errorf("changes after apply = %v, want none", changes)
+	}
+}
+
+func TestRoundFromValuesInvalidAttendance(t *testing.T) {
+	values := [][]interface{}{
+		{"ID", "이름", "발표 주제", "발표 자료", "발표 참여"},
+		{"100", "alice", "고루틴", "https://a.com", "참여"},
+	}
+
+	if _, err := roundFromValues(values); !errors.Is(err, study.ErrInvalidRoundChange) {
+		t.Fatalf("err = %v, want %v", err, study.ErrInvalidRoundChange)
+	}
+}
@@ -0,0 +1,647 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"google.golang.org/api/sheets/v4"
+)
+
+var (
+	defaultProgressSheetID int64 = 1024
+	infoLabelFormat              = &sheets.CellFormat{
+		TextFormat: &sheets.TextFormat{
+			Bold: true,
+			ForegroundColor: &sheets.Color{
+				Red:   1.0,
+				Green: 1.0,
+				Blue:  1.0,
+			},
+		},
+		BackgroundColor: &sheets.Color{
+			Blue: 0.8,
+		},
+		HorizontalAlignment: "CENTER",
+	}
+)
+
+var ErrNoSpreadsheet = errors.New("no spreadsheet to record events")
+
+type sheetsSink struct {
+	c                    SpreadsheetClient
+	defaultSpreadsheetID string
+	progressSheetID      int64
+	dashboardSheetID     int64
+
+	mtx          *sync.Mutex
+	spreadsheets map[string]string      // guild id to spreadsheet id
+	prepared     map[string]bool        // spreadsheets which have progress sheet
+	locks        map[string]*sync.Mutex // spreadsheet id to lock held while preparing
+
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	maxBuffered   int
+	onFlushError  func(error)
+	sleep         func(context.Context, time.Duration) error
+
+	bufMtx    *sync.Mutex
+	flushMtx  *sync.Mutex
+	buffer    map[string][]*sheets.RowData // spreadsheet id to progress rows
+	stop      chan struct{}
+	closeOnce *sync.Once
+}
+
+type SheetsOptsFunc func(*sheetsSink)
+
+func WithDefaultProgressSheetID() SheetsOptsFunc {
+	return func(h *sheetsSink) {
+		h.progressSheetID = defaultProgressSheetID
+	}
+}
+
+func WithProgressSheetID(id int64) SheetsOptsFunc {
+	return func(h *sheetsSink) {
+		h.progressSheetID = id
+	}
+}
+
+func WithDashboardSheetID(id int64) SheetsOptsFunc {
+	return func(h *sheetsSink) {
+		h.dashboardSheetID = id
+	}
+}
+
+// spreadsheet used for guilds which have not set their own
+func WithDefaultSpreadsheetID(id string) SheetsOptsFunc {
+	return func(h *sheetsSink) {
+		h.defaultSpreadsheetID = id
+	}
+}
+
+// create sink which records rounds and progress logs to google spreadsheet of each guild
+func NewSheetsSink(c SpreadsheetClient, opts ...SheetsOptsFunc) Sink {
+	h := &sheetsSink{
+		c:                c,
+		progressSheetID:  defaultProgressSheetID,
+		dashboardSheetID: defaultDashboardSheetID,
+		mtx:              &sync.Mutex{},
+		spreadsheets:     map[string]string{},
+		prepared:         map[string]bool{},
+		locks:            map[string]*sync.Mutex{},
+		batchSize:        1,
+		maxRetries:       defaultMaxRetries,
+		maxBuffered:      defaultMaxBuffered,
+		sleep:            sleepContext,
+		bufMtx:           &sync.Mutex{},
+		flushMtx:         &sync.Mutex{},
+		buffer:           map[string][]*sheets.RowData{},
+		stop:             make(chan struct{}),
+		closeOnce:        &sync.Once{},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.batchSize < 1 {
+		h.batchSize = 1
+	}
+
+	// buffered rows are flushed on interval even if the batch is not full
+	if h.batchSize > 1 && h.flushInterval > 0 {
+		go h.flushLoop()
+	}
+
+	return h
+}
+
+// resolve spreadsheet of the event target, progress sheet is created on first use
+func (h *sheetsSink) spreadsheet(ctx context.Context, evt study.Event) (string, error) {
+	h.mtx.Lock()
+
+	spreadsheetID, err := h.resolve(evt)
+	if err != nil {
+		h.mtx.Unlock()
+		return "", err
+	}
+
+	if h.prepared[spreadsheetID] {
+		h.mtx.Unlock()
+		return spreadsheetID, nil
+	}
+
+	lock, ok := h.locks[spreadsheetID]
+	if !ok {
+		lock = &sync.Mutex{}
+		h.locks[spreadsheetID] = lock
+	}
+
+	h.mtx.Unlock()
+
+	// setup calls the api, so only events of the same spreadsheet wait for it
+	defer lock.Unlock()
+	lock.Lock()
+
+	if h.isPrepared(spreadsheetID) {
+		return spreadsheetID, nil
+	}
+
+	if err := h.setup(ctx, spreadsheetID); err != nil {
+		return "", err
+	}
+
+	h.mtx.Lock()
+	h.prepared[spreadsheetID] = true
+	h.mtx.Unlock()
+
+	return spreadsheetID, nil
+}
+
+func (h *sheetsSink) isPrepared(spreadsheetID string) bool {
+	defer h.mtx.Unlock()
+	h.mtx.Lock()
+
+	return h.prepared[spreadsheetID]
+}
+
+func (h *sheetsSink) resolve(evt study.Event) (string, error) {
+	// spreadsheet set by the manager
+	if evt.SpreadsheetURL != "" {
+		spreadsheetID, err := study.SpreadsheetIDFromURL(evt.SpreadsheetURL)
+		if err != nil {
+			return "", err
+		}
+
+		if evt.GuildID != "" {
+			h.spreadsheets[evt.GuildID] = spreadsheetID
+		}
+
+		return spreadsheetID, nil
+	}
+
+	// spreadsheet resolved by previous events of the guild
+	if spreadsheetID, ok := h.spreadsheets[evt.GuildID]; ok {
+		return spreadsheetID, nil
+	}
+
+	if h.defaultSpreadsheetID == "" {
+		return "", errors.Join(ErrNoSpreadsheet, fmt.Errorf("guild: %s", evt.GuildID))
+	}
+
+	return h.defaultSpreadsheetID, nil
+}
+
+// setup progress and dashboard sheet
+func (h *sheetsSink) setup(ctx context.Context, spreadsheetID string) error {
+	// get spreadsheet
+	resp, err := h.c.Get(ctx, spreadsheetID)
+	if err != nil {
+		return err
+	}
+
+	// check status code
+	if resp.HTTPStatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code while getting spreadsheet: %d", resp.HTTPStatusCode)
+	}
+
+	// check event sheet exists
+	var progressSheetExists, dashboardSheetExists bool
+
+	for _, sheet := range resp.Sheets {
+		switch sheet.Properties.SheetId {
+		case h.progressSheetID:
+			progressSheetExists = true
+		case h.dashboardSheetID:
+			dashboardSheetExists = true
+		}
+	}
+
+	if !progressSheetExists {
+		// create progress sheet
+		if err := h.createProgressSheet(ctx, spreadsheetID); err != nil {
+			return err
+		}
+	}
+
+	if !dashboardSheetExists {
+		// create dashboard sheet
+		if err := h.createDashboardSheet(ctx, spreadsheetID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// record round data to spreadsheet
+func (h *sheetsSink) RecordRound(ctx context.Context, evt study.Event, r study.Round) error {
+	spreadsheetID, err := h.spreadsheet(ctx, evt)
+	if err != nil {
+		return err
+	}
+
+	// sheet and rows are added at once, so an existing sheet means the round is recorded
+	// and only the dashboard is left when the event is redelivered
+	exists, err := h.hasSheet(ctx, spreadsheetID, int64(r.Number))
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := h.addRoundSheet(ctx, spreadsheetID, r); err != nil {
+			return err
+		}
+	}
+
+	// summarize the round on dashboard
+	return h.updateDashboard(ctx, spreadsheetID, r)
+}
+
+func (h *sheetsSink) hasSheet(ctx context.Context, spreadsheetID string, sheetID int64) (bool, error) {
+	resp, err := h.c.Get(ctx, spreadsheetID)
+	if err != nil {
+		return false, err
+	}
+
+	if resp.HTTPStatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code while getting spreadsheet: %d", resp.HTTPStatusCode)
+	}
+
+	for _, sheet := range resp.Sheets {
+		if sheet.Properties.SheetId == sheetID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// add the sheet of the round with its rows
+func (h *sheetsSink) addRoundSheet(ctx context.Context, spreadsheetID string, r study.Round) error {
+	addSheetReq := &sheets.AddSheetRequest{
+		Properties: &sheets.SheetProperties{
+			Title:     fmt.Sprintf("%d 라운드: %s", r.Number, r.Title),
+			SheetId:   int64(r.Number),
+			SheetType: "GRID",
+			TabColor: &sheets.Color{
+				Blue: 1.0,
+			},
+		},
+	}
+
+	rows := rowsFromRoundData(r)
+
+	appendCellsReq := &sheets.AppendCellsRequest{
+		SheetId: int64(r.Number),
+		Fields:  "*",
+		Rows:    rows,
+	}
+
+	resp, err := h.batchUpdate(ctx, spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AddSheet: addSheetReq, // create sheet
+			},
+			{
+				AppendCells: appendCellsReq, // then add rows
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// check status code
+	if resp.HTTPStatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code while adding sheet: %d", resp.HTTPStatusCode)
+	}
+
+	return nil
+}
+
+// append progress log to progress sheet
+func (h *sheetsSink) RecordProgress(ctx context.Context, evt study.Event) error {
+	spreadsheetID, err := h.spreadsheet(ctx, evt)
+	if err != nil {
+		return err
+	}
+
+	// rows are written in batch to avoid hitting the quota of google sheets api
+	return h.enqueue(ctx, spreadsheetID, &sheets.RowData{
+		Values: []*sheets.CellData{
+			{
+				UserEnteredValue: &sheets.ExtendedValue{
+					StringValue: func() *string {
+						s := evt.Topic.String()
+						return &s
+					}(),
+				},
+			},
+			{
+				UserEnteredValue: &sheets.ExtendedValue{
+					StringValue: func() *string {
+						s := evt.Description
+						return &s
+					}(),
+				},
+			},
+			{
+				UserEnteredValue: &sheets.ExtendedValue{
+					StringValue: func() *string {
+						s := time.Unix(evt.Timestamp, 0).Format(time.RFC3339)
+						return &s
+					}(),
+				},
+			},
+		},
+	})
+}
+
+func (h *sheetsSink) createProgressSheet(ctx context.Context, spreadsheetID string) error {
+	addSheetReq := &sheets.AddSheetRequest{
+		Properties: &sheets.SheetProperties{
+			Title:     "진행 로그",
+			SheetId:   h.progressSheetID,
+			SheetType: "GRID",
+		},
+	}
+
+	appendCellsReq := &sheets.AppendCellsRequest{
+		SheetId: h.progressSheetID,
+		Fields:  "*",
+		Rows: []*sheets.RowData{
+			{
+				Values: []*sheets.CellData{
+					{
+						UserEnteredFormat: infoLabelFormat,
+						UserEnteredValue: &sheets.ExtendedValue{
+							StringValue: func() *string {
+								s := "진행 상태"
+								return &s
+							}(),
+						},
+					},
+					{
+						UserEnteredFormat: infoLabelFormat,
+						UserEnteredValue: &sheets.ExtendedValue{
+							StringValue: func() *string {
+								s := "설명"
+								return &s
+							}(),
+						},
+					},
+					{
+						UserEnteredFormat: infoLabelFormat,
+						UserEnteredValue: &sheets.ExtendedValue{
+							StringValue: func() *string {
+								s := "시간"
+								return &s
+							}(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resp, err := h.batchUpdate(ctx, spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AddSheet: addSheetReq,
+			},
+			{
+				AppendCells: appendCellsReq,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// check status code
+	if resp.HTTPStatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code while adding sheet: %d", resp.HTTPStatusCode)
+	}
+
+	return nil
+}
+
+func rowsFromRoundData(r study.Round) []*sheets.RowData {
+	rows := []*sheets.RowData{
+		{
+			Values: []*sheets.CellData{
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "제목"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := r.Title
+							return &s
+						}(),
+					},
+				},
+			},
+		},
+		{
+			Values: []*sheets.CellData{
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "진행 단계"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := r.Stage.String()
+							return &s
+						}(),
+					},
+				},
+			},
+		},
+		{
+			Values: []*sheets.CellData{
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "녹화 영상"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						FormulaValue: func() *string {
+							s := fmt.Sprintf(`=HYPERLINK("%s")`, r.ContentURL)
+							return &s
+						}(),
+					},
+				},
+			},
+		},
+		{
+			Values: []*sheets.CellData{
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "생성"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := r.CreatedAt.Format(time.RFC3339)
+							return &s
+						}(),
+					},
+				},
+			},
+		},
+		{
+			Values: []*sheets.CellData{
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "최종 수정"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := r.UpdatedAt.Format(time.RFC3339)
+							return &s
+						}(),
+					},
+				},
+			},
+		},
+		{}, // empty row
+		{
+			Values: []*sheets.CellData{
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "ID"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "이름"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "발표 주제"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "발표 자료"
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredFormat: infoLabelFormat,
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := "발표 참여"
+							return &s
+						}(),
+					},
+				},
+			},
+		},
+	}
+
+	// members are ordered by id to keep rows stable
+	for _, id := range sortedMemberIDs(r) {
+		m := r.Members[id]
+
+		row := &sheets.RowData{
+			Values: []*sheets.CellData{
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := id
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := m.Name
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						StringValue: func() *string {
+							s := m.Subject
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						FormulaValue: func() *string {
+							s := fmt.Sprintf(`=HYPERLINK("%s")`, m.ContentURL)
+							return &s
+						}(),
+					},
+				},
+				{
+					UserEnteredValue: &sheets.ExtendedValue{
+						BoolValue: func() *bool {
+							b := m.Attended
+							return &b
+						}(),
+					},
+				},
+			},
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
@@ -0,0 +1,363 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"google.golang.org/api/sheets/v4"
+)
+
+const testSpreadsheetID = "spreadsheet"
+
+func testRound() study.Round {
+	r := study.NewRound()
+	r.SetNumber(3)
+	r.SetTitle("고루틴")
+	r.SetStage(study.StageFinished)
+	r.SetContentURL("https://youtu.be/record")
+	r.CreatedAt = time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	r.UpdatedAt = time.Date(2023, 5, 8, 10, 0, 0, 0, time.UTC)
+
+	for _, m := range []struct {
+		id, name, subject, url string
+		attended               bool
+	}{
+		{"300", "charlie", "채널", "https://c.com", false},
+		{"100", "alice", "고루틴", "https://a.com", true},
+		{"200", "bob", "뮤텍스", "https://b.com", true},
+	} {
+		member := study.NewMember()
+		member.SetName(m.name)
+		member.SetSubject(m.subject)
+		member.SetContentURL(m.url)
+		member.SetRegistered(true)
+		member.SetAttended(m.attended)
+		r.SetMember(m.id, member)
+	}
+
+	return r
+}
+
+func testEvent(t *testing.T, topic study.EventTopic, description string) study.Event {
+	t.Helper()
+
+	evt, err := study.NewEvent(topic, description)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return evt
+}
+
+func TestSheetsSinkConcurrentSetup(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+	sink := NewSheetsSink(c, WithDefaultSpreadsheetID(testSpreadsheetID))
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundCreated, "생성")); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// events waiting for the same spreadsheet do not prepare it again
+	if c.gets != 1 {
+		t.Errorf("gets = %d, want 1", c.gets)
+	}
+}
+
+func TestSheetsSinkSetup(t *testing.T) {
+	tests := []struct {
+		name            string
+		existing        []*sheets.SheetProperties
+		opts            []SheetsOptsFunc
+		progressSheetID int64
+		header          bool
+	}{
+		{
+			name:            "create progress sheet",
+			progressSheetID: defaultProgressSheetID,
+			header:          true,
+		},
+		{
+			name:            "create progress sheet with custom id",
+			opts:            []SheetsOptsFunc{WithProgressSheetID(7)},
+			progressSheetID: 7,
+			header:          true,
+		},
+		{
+			name:            "progress sheet already exists",
+			existing:        []*sheets.SheetProperties{{SheetId: defaultProgressSheetID, Title: "진행 로그"}},
+			progressSheetID: defaultProgressSheetID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeSpreadsheetClient(testSpreadsheetID, tt.existing...)
+
+			opts := append([]SheetsOptsFunc{WithDefaultSpreadsheetID(testSpreadsheetID)}, tt.opts...)
+			sink := NewSheetsSink(c, opts...)
+
+			// spreadsheet is not touched until the first event
+			if c.gets != 0 || c.batchUpdates != 0 {
+				t.Fatalf("spreadsheet accessed before first use: gets = %d, batch updates = %d", c.gets, c.batchUpdates)
+			}
+
+			evt := testEvent(t, study.EventTopicStudyRoundCreated, "생성")
+
+			for i := 0; i < 2; i++ {
+				if err := sink.RecordProgress(context.Background(), evt); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			// spreadsheet is prepared only once
+			if c.gets != 1 {
+				t.Errorf("gets = %d, want 1", c.gets)
+			}
+
+			sh := c.sheet(testSpreadsheetID, tt.progressSheetID)
+			if sh == nil {
+				t.Fatalf("progress sheet %d not found", tt.progressSheetID)
+			}
+
+			rows := 2
+			if tt.header {
+				rows++
+			}
+
+			if len(sh.rows) != rows {
+				t.Fatalf("rows = %d, want %d", len(sh.rows), rows)
+			}
+
+			if !tt.header {
+				return
+			}
+
+			want := []string{"진행 상태", "설명", "시간"}
+			if got := rowValues(sh.rows[0]); !reflect.DeepEqual(got, want) {
+				t.Errorf("header = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSheetsSinkSpreadsheetNotFound(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink := NewSheetsSink(c, WithDefaultSpreadsheetID("unknown"))
+
+	if err := sink.RecordProgress(context.Background(), testEvent(t, study.EventTopicStudyRoundCreated, "생성")); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestSheetsSinkSpreadsheetRouting(t *testing.T) {
+	const guildSpreadsheetID = "guild-spreadsheet"
+
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+	c.addSpreadsheet(guildSpreadsheetID)
+
+	sink := NewSheetsSink(c, WithDefaultSpreadsheetID(testSpreadsheetID))
+
+	// guild with its own spreadsheet
+	evt := testEvent(t, study.EventTopicStudyRoundProgress, "guild")
+	evt.SetTarget("guild", "https://docs.google.com/spreadsheets/d/"+guildSpreadsheetID+"/edit#gid=0")
+
+	if err := sink.RecordProgress(context.Background(), evt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// later events of the guild without url use the cached spreadsheet
+	evt = testEvent(t, study.EventTopicStudyRoundProgress, "guild cached")
+	evt.SetTarget("guild", "")
+
+	if err := sink.RecordProgress(context.Background(), evt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// guild without spreadsheet falls back to default
+	evt = testEvent(t, study.EventTopicStudyRoundProgress, "other")
+	evt.SetTarget("other", "")
+
+	if err := sink.RecordProgress(context.Background(), evt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(c.sheet(guildSpreadsheetID, defaultProgressSheetID).rows); got != 3 {
+		t.Errorf("guild spreadsheet rows = %d, want 3", got)
+	}
+
+	if got := len(c.sheet(testSpreadsheetID, defaultProgressSheetID).rows); got != 2 {
+		t.Errorf("default spreadsheet rows = %d, want 2", got)
+	}
+}
+
+func TestSheetsSinkNoSpreadsheet(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink := NewSheetsSink(c)
+
+	evt := testEvent(t, study.EventTopicStudyRoundProgress, "no target")
+	evt.SetTarget("guild", "")
+
+	if err := sink.RecordProgress(context.Background(), evt); !errors.Is(err, ErrNoSpreadsheet) {
+		t.Fatalf("err = %v, want %v", err, ErrNoSpreadsheet)
+	}
+
+	evt.SetTarget("guild", "https://example.com/spreadsheets/d/abc")
+
+	if err := sink.RecordProgress(context.Background(), evt); !errors.Is(err, study.ErrInvalidSpreadsheetURL) {
+		t.Fatalf("err = %v, want %v", err, study.ErrInvalidSpreadsheetURL)
+	}
+}
+
+func TestSheetsSinkRecordRound(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink := NewSheetsSink(c, WithDefaultSpreadsheetID(testSpreadsheetID))
+
+	r := testRound()
+	evt := testEvent(t, study.EventTopicStudyRoundFinished, "")
+
+	if err := sink.RecordRound(context.Background(), evt, r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sh := c.sheet(testSpreadsheetID, int64(r.Number))
+	if sh == nil {
+		t.Fatalf("round sheet %d not found", r.Number)
+	}
+
+	if want := "3 라운드: 고루틴"; sh.props.Title != want {
+		t.Errorf("title = %q, want %q", sh.props.Title, want)
+	}
+
+	if got, want := len(sh.rows), len(rowsFromRoundData(r)); got != want {
+		t.Errorf("rows = %d, want %d", got, want)
+	}
+
+	// recording the same round again keeps the sheet as is
+	if err := sink.RecordRound(context.Background(), evt, r); err != nil {
+		t.Fatalf("unexpected error on redelivered round: %v", err)
+	}
+
+	if got, want := len(c.sheet(testSpreadsheetID, int64(r.Number)).rows), len(rowsFromRoundData(r)); got != want {
+		t.Errorf("rows after redelivery = %d, want %d", got, want)
+	}
+}
+
+func TestSheetsSinkRecordRoundRetriesDashboard(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink := NewSheetsSink(c, WithDefaultSpreadsheetID(testSpreadsheetID))
+
+	r := testRound()
+	evt := testEvent(t, study.EventTopicStudyRoundFinished, "")
+
+	// setup creates progress and dashboard sheets, then the round sheet is added
+	// and the dashboard update fails
+	c.fail(nil, nil, nil, errors.New("quota exceeded"))
+
+	if err := sink.RecordRound(context.Background(), evt, r); err == nil {
+		t.Fatal("expected error of the dashboard update")
+	}
+
+	if c.sheet(testSpreadsheetID, int64(r.Number)) == nil {
+		t.Fatal("round sheet should be added before the dashboard update")
+	}
+
+	// redelivered event only updates the dashboard
+	if err := sink.RecordRound(context.Background(), evt, r); err != nil {
+		t.Fatalf("unexpected error on redelivered round: %v", err)
+	}
+
+	if len(c.sheet(testSpreadsheetID, defaultDashboardSheetID).rows) == 0 {
+		t.Fatal("dashboard is not updated")
+	}
+}
+
+func TestSheetsSinkRecordProgress(t *testing.T) {
+	c := newFakeSpreadsheetClient(testSpreadsheetID)
+
+	sink := NewSheetsSink(c, WithDefaultSpreadsheetID(testSpreadsheetID))
+
+	evt := testEvent(t, study.EventTopicStudyRoundProgress, "고루틴: 발표")
+
+	if err := sink.RecordProgress(context.Background(), evt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sh := c.sheet(testSpreadsheetID, defaultProgressSheetID)
+	if len(sh.rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(sh.rows))
+	}
+
+	want := []string{
+		study.EventTopicStudyRoundProgress.String(),
+		"고루틴: 발표",
+		time.Unix(evt.Timestamp, 0).Format(time.RFC3339),
+	}
+
+	if got := rowValues(sh.rows[1]); !reflect.DeepEqual(got, want) {
+		t.Errorf("row = %v, want %v", got, want)
+	}
+}
+
+func TestRowsFromRoundData(t *testing.T) {
+	r := testRound()
+
+	rows := rowsFromRoundData(r)
+
+	want := [][]string{
+		{"제목", "고루틴"},
+		{"진행 단계", "라운드 종료"},
+		{"녹화 영상", `=HYPERLINK("https://youtu.be/record")`},
+		{"생성", "2023-05-01T10:00:00Z"},
+		{"최종 수정", "2023-05-08T10:00:00Z"},
+		{},
+		{"ID", "이름", "발표 주제", "발표 자료", "발표 참여"},
+		{"100", "alice", "고루틴", `=HYPERLINK("https://a.com")`, "true"},
+		{"200", "bob", "뮤텍스", `=HYPERLINK("https://b.com")`, "true"},
+		{"300", "charlie", "채널", `=HYPERLINK("https://c.com")`, "false"},
+	}
+
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %d, want %d", len(rows), len(want))
+	}
+
+	for i, row := range rows {
+		if got := rowValues(row); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("row %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestRowsFromRoundDataOrderIsStable(t *testing.T) {
+	r := testRound()
+
+	first := rowsFromRoundData(r)
+
+	for i := 0; i < 10; i++ {
+		rows := rowsFromRoundData(r)
+
+		for j := range rows {
+			if !reflect.DeepEqual(rowValues(rows[j]), rowValues(first[j])) {
+				t.Fatalf("row %d differs between calls", j)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// Sink exports round records and progress logs to a durable storage,
+// the event carries the guild and its export target
+type Sink interface {
+	RecordRound(ctx context.Context, evt study.Event, r study.Round) error
+	RecordProgress(ctx context.Context, evt study.Event) error
+}
+
+// Closer is implemented by sinks which buffer records and should flush them before shutdown
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// close sinks implementing Closer
+func CloseSinks(ctx context.Context, sinks ...Sink) error {
+	var errs []error
+
+	for _, s := range sinks {
+		if c, ok := s.(Closer); ok {
+			if err := c.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// member ids of the round in ascending order
+func sortedMemberIDs(r study.Round) []string {
+	ids := make([]string, 0, len(r.Members))
+
+	for id := range r.Members {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
@@ -0,0 +1,480 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/repository"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// headers of webhook requests
+	WebhookSignatureHeader = "X-Study-Signature"
+	WebhookTopicHeader     = "X-Study-Topic"
+	WebhookDeliveryHeader  = "X-Study-Delivery"
+
+	// topic of the payload sent to test a webhook
+	WebhookPingTopic = "ping"
+
+	defaultWebhookRetries   = 3
+	defaultWebhookTimeout   = 10 * time.Second
+	defaultDeliveryTimeout  = 2 * time.Minute
+	defaultWebhookQueueSize = 100
+)
+
+var (
+	ErrWebhookDelivery    = errors.New("failed to deliver webhook")
+	ErrNoWebhookResolver  = errors.New("no resolver to find webhooks of the event")
+	ErrWebhookSinkClosed  = errors.New("webhook sink is closed")
+	ErrWebhookQueueIsFull = errors.New("webhook delivery queue is full")
+)
+
+// number of webhook delivery attempts by result
+var WebhookDeliveries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "webhook_deliveries_total",
+		Help: "Total number of webhook delivery attempts.",
+	},
+	[]string{"result"},
+)
+
+// WebhookPayload is the body of webhook requests
+type WebhookPayload struct {
+	ID          string             `json:"id"` // same across retries of a delivery
+	Topic       string             `json:"topic"`
+	GuildID     string             `json:"guild_id"`
+	Description string             `json:"description"`
+	Timestamp   int64              `json:"timestamp"`
+	Round       *study.PublicRound `json:"round,omitempty"` // reviewers of the anonymous feedback are left out
+}
+
+// Delivery is a record of a delivery attempt
+type Delivery struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhook_id"`
+	URL        string    `json:"url"`
+	Topic      string    `json:"topic"`
+	GuildID    string    `json:"guild_id"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Duration   string    `json:"duration"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+func (d Delivery) Succeeded() bool {
+	return d.Error == "" && d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// WebhookResolver finds webhooks of the guild with their secrets
+type WebhookResolver interface {
+	Webhooks(ctx context.Context, guildID string) ([]study.Webhook, error)
+}
+
+type studyWebhookResolver struct {
+	q repository.Query
+}
+
+// resolve webhooks from the study of the guild
+func NewStudyWebhookResolver(q repository.Query) WebhookResolver {
+	return &studyWebhookResolver{q: q}
+}
+
+func (r *studyWebhookResolver) Webhooks(ctx context.Context, guildID string) ([]study.Webhook, error) {
+	s, err := r.q.FindStudy(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Webhooks, nil
+}
+
+// WebhookSink delivers events to the webhooks whose ids are carried by them
+type WebhookSink interface {
+	Sink
+	Closer
+	Ping(ctx context.Context, guildID string, w study.Webhook) (Delivery, error)
+}
+
+type webhookJob struct {
+	w study.Webhook
+	p WebhookPayload
+}
+
+type webhookSink struct {
+	client          *http.Client
+	resolver        WebhookResolver
+	maxRetries      int
+	backoff         time.Duration
+	deliveryTimeout time.Duration
+	queueSize       int
+	onError         func(error)
+
+	logMtx *sync.Mutex
+	log    io.Writer
+
+	// deliveries are queued per webhook and sent by a worker of each webhook
+	mtx    *sync.Mutex
+	queues map[string]chan webhookJob
+	closed bool
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+}
+
+type WebhookOptsFunc func(*webhookSink)
+
+func WithWebhookClient(c *http.Client) WebhookOptsFunc {
+	return func(h *webhookSink) {
+		h.client = c
+	}
+}
+
+// number of retries after the first attempt of each endpoint
+func WithWebhookRetries(n int) WebhookOptsFunc {
+	return func(h *webhookSink) {
+		h.maxRetries = n
+	}
+}
+
+// resolve webhooks of the events with their secrets
+func WithWebhookResolver(r WebhookResolver) WebhookOptsFunc {
+	return func(h *webhookSink) {
+		h.resolver = r
+	}
+}
+
+// max deliveries waiting for each webhook, deliveries beyond it are dropped
+func WithWebhookQueueSize(n int) WebhookOptsFunc {
+	return func(h *webhookSink) {
+		h.queueSize = n
+	}
+}
+
+// errors of deliveries sent in background are reported to the handler
+func WithDeliveryErrorHandler(fn func(error)) WebhookOptsFunc {
+	return func(h *webhookSink) {
+		h.onError = fn
+	}
+}
+
+// write each delivery attempt as a json line
+func WithDeliveryLog(w io.Writer) WebhookOptsFunc {
+	return func(h *webhookSink) {
+		h.log = w
+	}
+}
+
+func NewWebhookSink(opts ...WebhookOptsFunc) WebhookSink {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	h := &webhookSink{
+		client:          &http.Client{Timeout: defaultWebhookTimeout},
+		maxRetries:      defaultWebhookRetries,
+		backoff:         defaultRetryBackoff,
+		deliveryTimeout: defaultDeliveryTimeout,
+		queueSize:       defaultWebhookQueueSize,
+		logMtx:          &sync.Mutex{},
+		mtx:             &sync.Mutex{},
+		queues:          map[string]chan webhookJob{},
+		wg:              &sync.WaitGroup{},
+		ctx:             ctx,
+		cancel:          cancel,
+		sleep:           sleepContext,
+		now:             time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.queueSize < 1 {
+		h.queueSize = 1
+	}
+
+	return h
+}
+
+func (h *webhookSink) RecordRound(ctx context.Context, evt study.Event, r study.Round) error {
+	public := study.NewPublicRound(r)
+	return h.enqueueAll(ctx, evt, &public)
+}
+
+func (h *webhookSink) RecordProgress(ctx context.Context, evt study.Event) error {
+	return h.enqueueAll(ctx, evt, nil)
+}
+
+// stop accepting deliveries and wait for queued ones, in-flight deliveries are canceled when ctx is done
+func (h *webhookSink) Close(ctx context.Context) error {
+	h.mtx.Lock()
+	if !h.closed {
+		h.closed = true
+		for _, q := range h.queues {
+			close(q)
+		}
+	}
+	h.mtx.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		h.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// send a ping payload once without retries
+func (h *webhookSink) Ping(ctx context.Context, guildID string, w study.Webhook) (Delivery, error) {
+	p, err := newWebhookPayload(WebhookPingTopic, guildID, "웹훅 테스트", h.now().Unix(), nil)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return h.send(ctx, w, p, 1)
+}
+
+// queue the event to the webhooks carried by it, deliveries are sent in background
+// so that slow endpoints neither block the handler nor each other
+func (h *webhookSink) enqueueAll(ctx context.Context, evt study.Event, r *study.PublicRound) error {
+	if len(evt.WebhookIDs) == 0 {
+		return nil
+	}
+
+	if h.resolver == nil {
+		return ErrNoWebhookResolver
+	}
+
+	webhooks, err := h.resolver.Webhooks(ctx, evt.GuildID)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]bool, len(evt.WebhookIDs))
+	for _, id := range evt.WebhookIDs {
+		ids[id] = true
+	}
+
+	for _, w := range webhooks {
+		// webhooks removed or changed after the event are skipped
+		if !ids[w.ID] || !w.Subscribes(evt.Topic) {
+			continue
+		}
+
+		p, err := newWebhookPayload(evt.Topic.String(), evt.GuildID, evt.Description, evt.Timestamp, r)
+		if err != nil {
+			return err
+		}
+
+		if err := h.enqueue(webhookJob{w: w, p: p}); err != nil {
+			// the event is not redelivered for a single busy endpoint
+			WebhookDeliveries.WithLabelValues("dropped").Inc()
+			h.reportError(err)
+		}
+	}
+
+	return nil
+}
+
+func (h *webhookSink) enqueue(job webhookJob) error {
+	defer h.mtx.Unlock()
+	h.mtx.Lock()
+
+	if h.closed {
+		return ErrWebhookSinkClosed
+	}
+
+	q, ok := h.queues[job.w.ID]
+	if !ok {
+		q = make(chan webhookJob, h.queueSize)
+		h.queues[job.w.ID] = q
+
+		h.wg.Add(1)
+		go h.work(q)
+	}
+
+	select {
+	case q <- job:
+		return nil
+	default:
+		return errors.Join(ErrWebhookQueueIsFull, fmt.Errorf("webhook %s", job.w.ID))
+	}
+}
+
+// send deliveries of a webhook in order, each with its own timeout
+func (h *webhookSink) work(q <-chan webhookJob) {
+	defer h.wg.Done()
+
+	for job := range q {
+		ctx, cancel := context.WithTimeout(h.ctx, h.deliveryTimeout)
+		err := h.deliver(ctx, job.w, job.p)
+		cancel()
+
+		if err != nil {
+			h.reportError(err)
+		}
+	}
+}
+
+func (h *webhookSink) reportError(err error) {
+	if h.onError != nil {
+		h.onError(err)
+	}
+}
+
+// deliver payload retrying with exponential backoff on network errors, 429 and 5xx responses
+func (h *webhookSink) deliver(ctx context.Context, w study.Webhook, p WebhookPayload) error {
+	backoff := h.backoff
+
+	for attempt := 1; ; attempt++ {
+		d, err := h.send(ctx, w, p, attempt)
+		if err != nil {
+			return err
+		}
+
+		if d.Succeeded() {
+			return nil
+		}
+
+		retryable := d.StatusCode == 0 || d.StatusCode == http.StatusTooManyRequests || d.StatusCode >= 500
+
+		if !retryable || attempt > h.maxRetries {
+			return errors.Join(ErrWebhookDelivery, fmt.Errorf("webhook %s: %s after %d attempts", w.ID, deliveryResult(d), attempt))
+		}
+
+		if err := h.sleep(ctx, backoff); err != nil {
+			return err
+		}
+
+		backoff = minDuration(backoff*2, maxRetryBackoff)
+	}
+}
+
+// send signed payload, failures of the request are recorded in the delivery
+func (h *webhookSink) send(ctx context.Context, w study.Webhook, p WebhookPayload, attempt int) (Delivery, error) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "my-study-bot-webhook")
+	req.Header.Set(WebhookTopicHeader, p.Topic)
+	req.Header.Set(WebhookDeliveryHeader, p.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(w.Secret, body))
+
+	d := Delivery{
+		ID:        p.ID,
+		WebhookID: w.ID,
+		URL:       w.URL,
+		Topic:     p.Topic,
+		GuildID:   p.GuildID,
+		Attempt:   attempt,
+		SentAt:    h.now(),
+	}
+
+	start := time.Now()
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		d.Error = err.Error()
+	} else {
+		d.StatusCode = resp.StatusCode
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		_ = resp.Body.Close()
+	}
+
+	d.Duration = time.Since(start).String()
+
+	h.record(d)
+
+	return d, nil
+}
+
+func (h *webhookSink) record(d Delivery) {
+	result := "success"
+	if !d.Succeeded() {
+		result = "failure"
+	}
+
+	WebhookDeliveries.WithLabelValues(result).Inc()
+
+	if h.log == nil {
+		return
+	}
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+
+	defer h.logMtx.Unlock()
+	h.logMtx.Lock()
+
+	_, _ = h.log.Write(append(b, '\n'))
+}
+
+// signature of the body in the form of sha256=<hex>, receivers compare it with their own
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// report whether the signature header matches the body
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
+
+func newWebhookPayload(topic, guildID, description string, timestamp int64, r *study.PublicRound) (WebhookPayload, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return WebhookPayload{}, err
+	}
+
+	return WebhookPayload{
+		ID:          hex.EncodeToString(b),
+		Topic:       topic,
+		GuildID:     guildID,
+		Description: description,
+		Timestamp:   timestamp,
+		Round:       r,
+	}, nil
+}
+
+func deliveryResult(d Delivery) string {
+	if d.Error != "" {
+		return d.Error
+	}
+	return fmt.Sprintf("status code %d", d.StatusCode)
+}
@@ -0,0 +1,375 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+type webhookReceiver struct {
+	mtx      sync.Mutex
+	statuses []int // responded in order, 200 after all are used
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rcv.mtx.Lock()
+	defer rcv.mtx.Unlock()
+
+	rcv.requests = append(rcv.requests, r)
+	rcv.bodies = append(rcv.bodies, body)
+
+	status := http.StatusOK
+	if len(rcv.statuses) > 0 {
+		status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+	}
+
+	w.WriteHeader(status)
+}
+
+func (rcv *webhookReceiver) count() int {
+	rcv.mtx.Lock()
+	defer rcv.mtx.Unlock()
+
+	return len(rcv.requests)
+}
+
+// webhooks of guilds registered in memory
+type fakeWebhookResolver map[string][]study.Webhook
+
+func (f fakeWebhookResolver) Webhooks(_ context.Context, guildID string) ([]study.Webhook, error) {
+	return f[guildID], nil
+}
+
+// errors of background deliveries collected in order
+type deliveryErrors struct {
+	mtx  sync.Mutex
+	errs []error
+}
+
+func (d *deliveryErrors) add(err error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.errs = append(d.errs, err)
+}
+
+func newTestWebhookSink(t *testing.T, log io.Writer, webhooks fakeWebhookResolver, opts ...WebhookOptsFunc) (*webhookSink, *deliveryErrors) {
+	t.Helper()
+
+	errs := &deliveryErrors{}
+
+	opts = append([]WebhookOptsFunc{
+		WithWebhookRetries(2),
+		WithDeliveryLog(log),
+		WithWebhookResolver(webhooks),
+		WithDeliveryErrorHandler(errs.add),
+	}, opts...)
+
+	h := NewWebhookSink(opts...).(*webhookSink)
+	h.sleep = func(context.Context, time.Duration) error { return nil }
+
+	t.Cleanup(func() {
+		_ = h.Close(context.Background())
+	})
+
+	return h, errs
+}
+
+func TestWebhookSinkDeliversSignedPayload(t *testing.T) {
+	rcv := &webhookReceiver{}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	wh, err := study.NewWebhook(srv.URL, study.EventTopicStudyRoundFinished)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, _ := newTestWebhookSink(t, io.Discard, fakeWebhookResolver{"guild": {wh}})
+
+	evt, _ := study.NewEvent(study.EventTopicStudyRoundFinished, "")
+	evt.SetTarget("guild", "")
+	evt.SetWebhooks([]study.Webhook{wh})
+
+	r := study.NewRound()
+	r.SetNumber(2)
+	r.SetTitle("round")
+
+	speaker := study.NewMember()
+	speaker.SetReviewer("reviewer")
+	r.SetMember("speaker", speaker)
+
+	if err := h.RecordRound(context.Background(), evt, r); err != nil {
+		t.Fatalf("failed to deliver: %v", err)
+	}
+
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rcv.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(rcv.requests))
+	}
+
+	req, body := rcv.requests[0], rcv.bodies[0]
+
+	if !VerifyWebhookSignature(wh.Secret, body, req.Header.Get(WebhookSignatureHeader)) {
+		t.Fatalf("invalid signature: %s", req.Header.Get(WebhookSignatureHeader))
+	}
+
+	if req.Header.Get(WebhookTopicHeader) != study.EventTopicStudyRoundFinished.String() {
+		t.Fatalf("unexpected topic header: %s", req.Header.Get(WebhookTopicHeader))
+	}
+
+	var p WebhookPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.GuildID != "guild" || p.Round == nil || p.Round.Number != 2 || p.ID != req.Header.Get(WebhookDeliveryHeader) {
+		t.Fatalf("unexpected payload: %+v", p)
+	}
+
+	// feedback is anonymous
+	if bytes.Contains(body, []byte("reviewer")) {
+		t.Fatalf("reviewers are sent to the webhook: %s", body)
+	}
+
+	// only ids of the webhooks are carried by the event
+	b, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(b, []byte(wh.Secret)) {
+		t.Fatal("secret of the webhook is carried by the event")
+	}
+
+	// progress events are not subscribed
+	progress, _ := study.NewEvent(study.EventTopicStudyRoundProgress, "progress")
+	progress.SetWebhooks([]study.Webhook{wh})
+
+	if len(progress.WebhookIDs) != 0 {
+		t.Fatalf("expected unsubscribed topic to be skipped, got %v", progress.WebhookIDs)
+	}
+}
+
+func TestWebhookSinkRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		requests int
+		failed   bool
+	}{
+		{"succeeds after server errors", []int{500, 429}, 3, false},
+		{"gives up after max retries", []int{502, 503, 504}, 3, true},
+		{"client errors are not retried", []int{400}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv := &webhookReceiver{statuses: tt.statuses}
+			srv := httptest.NewServer(rcv)
+			defer srv.Close()
+
+			wh, err := study.NewWebhook(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			log := &bytes.Buffer{}
+			h, errs := newTestWebhookSink(t, log, fakeWebhookResolver{"guild": {wh}})
+
+			evt, _ := study.NewEvent(study.EventTopicStudyRoundProgress, "progress")
+			evt.SetTarget("guild", "")
+			evt.SetWebhooks([]study.Webhook{wh})
+
+			// the handler returns without waiting for the delivery
+			if err := h.RecordProgress(context.Background(), evt); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err := h.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if failed := len(errs.errs) == 1 && errors.Is(errs.errs[0], ErrWebhookDelivery); failed != tt.failed {
+				t.Fatalf("unexpected delivery errors: %v", errs.errs)
+			}
+
+			if len(rcv.requests) != tt.requests {
+				t.Fatalf("expected %d requests, got %d", tt.requests, len(rcv.requests))
+			}
+
+			// the same delivery id is used for all attempts
+			ids := map[string]bool{}
+			for _, req := range rcv.requests {
+				ids[req.Header.Get(WebhookDeliveryHeader)] = true
+			}
+
+			if len(ids) != 1 {
+				t.Fatalf("expected 1 delivery id, got %d", len(ids))
+			}
+
+			// every attempt is logged
+			var deliveries []Delivery
+
+			sc := bufio.NewScanner(log)
+			for sc.Scan() {
+				var d Delivery
+				if err := json.Unmarshal(sc.Bytes(), &d); err != nil {
+					t.Fatal(err)
+				}
+				deliveries = append(deliveries, d)
+			}
+
+			if len(deliveries) != tt.requests {
+				t.Fatalf("expected %d logged deliveries, got %d", tt.requests, len(deliveries))
+			}
+
+			last := deliveries[len(deliveries)-1]
+			if last.Attempt != tt.requests || last.Succeeded() == tt.failed {
+				t.Fatalf("unexpected last delivery: %+v", last)
+			}
+		})
+	}
+}
+
+func TestWebhookSinkEndpointsAreIndependent(t *testing.T) {
+	release := make(chan struct{})
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	rcv := &webhookReceiver{}
+	fast := httptest.NewServer(rcv)
+	defer fast.Close()
+
+	slowHook, _ := study.NewWebhook(slow.URL)
+	fastHook, _ := study.NewWebhook(fast.URL)
+
+	h, _ := newTestWebhookSink(t, io.Discard, fakeWebhookResolver{"guild": {slowHook, fastHook}})
+
+	evt, _ := study.NewEvent(study.EventTopicStudyRoundProgress, "progress")
+	evt.SetTarget("guild", "")
+	evt.SetWebhooks([]study.Webhook{slowHook, fastHook})
+
+	done := make(chan error, 1)
+	go func() { done <- h.RecordProgress(context.Background(), evt) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler is blocked by the slow endpoint")
+	}
+
+	// the fast endpoint receives while the slow one is still waiting
+	deadline := time.Now().Add(time.Second)
+	for rcv.count() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("fast endpoint is blocked by the slow endpoint")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebhookSinkDropsWhenQueueIsFull(t *testing.T) {
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	wh, _ := study.NewWebhook(srv.URL)
+
+	h, errs := newTestWebhookSink(t, io.Discard, fakeWebhookResolver{"guild": {wh}}, WithWebhookQueueSize(1))
+
+	evt, _ := study.NewEvent(study.EventTopicStudyRoundProgress, "progress")
+	evt.SetTarget("guild", "")
+	evt.SetWebhooks([]study.Webhook{wh})
+
+	// first is taken by the worker, second waits in the queue and the rest are dropped
+	for i := 0; i < 4; i++ {
+		if err := h.RecordProgress(context.Background(), evt); err != nil {
+			t.Fatal(err)
+		}
+
+		if i == 0 {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	close(release)
+
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs.errs) != 2 || !errors.Is(errs.errs[0], ErrWebhookQueueIsFull) {
+		t.Fatalf("expected 2 dropped deliveries, got %v", errs.errs)
+	}
+
+	if err := h.RecordProgress(context.Background(), evt); err != nil {
+		t.Fatal(err)
+	}
+
+	if !errors.Is(errs.errs[len(errs.errs)-1], ErrWebhookSinkClosed) {
+		t.Fatalf("expected closed sink to reject deliveries, got %v", errs.errs)
+	}
+}
+
+func TestWebhookSinkPing(t *testing.T) {
+	rcv := &webhookReceiver{statuses: []int{500}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	wh, err := study.NewWebhook(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, _ := newTestWebhookSink(t, io.Discard, nil)
+
+	d, err := h.Ping(context.Background(), "guild", wh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ping is not retried
+	if d.Succeeded() || d.StatusCode != 500 || len(rcv.requests) != 1 {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+
+	if rcv.requests[0].Header.Get(WebhookTopicHeader) != WebhookPingTopic {
+		t.Fatalf("unexpected topic: %s", rcv.requests[0].Header.Get(WebhookTopicHeader))
+	}
+}
+
+func TestNewWebhookRejectsInvalidURL(t *testing.T) {
+	for _, u := range []string{"", "ftp://example.com", "example.com/hook", "https://"} {
+		if _, err := study.NewWebhook(u); !errors.Is(err, study.ErrInvalidWebhookURL) {
+			t.Fatalf("expected invalid url error for %q, got %v", u, err)
+		}
+	}
+}
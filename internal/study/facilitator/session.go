@@ -0,0 +1,146 @@
+package facilitator
+
+import (
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// time of each speaker when the round has no timetable
+const DefaultBudget = 15 * time.Minute
+
+type TurnState uint8
+
+const (
+	TurnWaiting TurnState = iota
+	TurnSpeaking
+	TurnCompleted
+	TurnSkipped
+)
+
+// Turn is the presentation of a speaker
+type Turn struct {
+	MemberID  string
+	Budget    time.Duration
+	StartedAt time.Time
+	State     TurnState
+}
+
+func (t Turn) Deadline() time.Time {
+	return t.StartedAt.Add(t.Budget)
+}
+
+// Session is the progress of the presentation led by the manager
+type Session struct {
+	RoundID string
+	Turns   []Turn
+
+	current int // index of the speaking turn, -1 if nobody is speaking
+	next    int // index of the next turn
+}
+
+// session in order of the timetable, or in order of registration if not generated
+func NewSession(r study.Round) *Session {
+	sess := &Session{
+		RoundID: r.ID,
+		current: -1,
+	}
+
+	if len(r.Timetable) > 0 {
+		for _, slot := range r.Timetable {
+			sess.Turns = append(sess.Turns, Turn{MemberID: slot.MemberID, Budget: slot.Duration})
+		}
+		return sess
+	}
+
+	for _, id := range r.SpeakerIDs() {
+		sess.Turns = append(sess.Turns, Turn{MemberID: id, Budget: DefaultBudget})
+	}
+
+	return sess
+}
+
+// turn of the speaker on stage
+func (s *Session) Current() (Turn, bool) {
+	if s.current < 0 {
+		return Turn{}, false
+	}
+	return s.Turns[s.current], true
+}
+
+// whether all turns are over
+func (s *Session) Done() bool {
+	return s.current < 0 && s.next >= len(s.Turns)
+}
+
+// complete the current turn and start the next one, the completed turn is returned if exists
+func (s *Session) Next(now time.Time) (Turn, bool) {
+	completed, ok := s.finish(TurnCompleted)
+	s.start(now)
+	return completed, ok
+}
+
+// skip the current turn, or the next one if nobody is speaking, and start the next one
+func (s *Session) Skip(now time.Time) (Turn, bool) {
+	if s.current < 0 {
+		if s.next >= len(s.Turns) {
+			return Turn{}, false
+		}
+
+		s.Turns[s.next].State = TurnSkipped
+		skipped := s.Turns[s.next]
+		s.next++
+		s.start(now)
+
+		return skipped, true
+	}
+
+	skipped, ok := s.finish(TurnSkipped)
+	s.start(now)
+	return skipped, ok
+}
+
+// give more time to the current turn
+func (s *Session) Extend(d time.Duration) bool {
+	if s.current < 0 {
+		return false
+	}
+
+	s.Turns[s.current].Budget += d
+	return true
+}
+
+// complete the current turn and skip the rest, the completed turn is returned if exists
+func (s *Session) End() (Turn, bool) {
+	completed, ok := s.finish(TurnCompleted)
+
+	for ; s.next < len(s.Turns); s.next++ {
+		s.Turns[s.next].State = TurnSkipped
+	}
+
+	return completed, ok
+}
+
+func (s *Session) finish(state TurnState) (Turn, bool) {
+	if s.current < 0 {
+		return Turn{}, false
+	}
+
+	s.Turns[s.current].State = state
+	finished := s.Turns[s.current]
+	s.current = -1
+
+	return finished, true
+}
+
+func (s *Session) start(now time.Time) {
+	if s.next >= len(s.Turns) {
+		return
+	}
+
+	s.current = s.next
+	s.next++
+
+	s.Turns[s.current].State = TurnSpeaking
+	s.Turns[s.current].StartedAt = now
+}
@@ -0,0 +1,84 @@
+package facilitator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func testSession() *Session {
+	r := study.NewRound()
+	r.SetID("round")
+	r.SetTimetable(study.NewTimetable([]string{"u1", "u2", "u3"}, study.TimetableOrderRegistration,
+		time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC), 10*time.Minute, nil))
+
+	return NewSession(r)
+}
+
+func TestSession(t *testing.T) {
+	sess := testSession()
+	now := time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC)
+
+	if _, ok := sess.Current(); ok {
+		t.Fatal("expected nobody to be speaking before start")
+	}
+
+	// first speaker starts, nothing is finished
+	if _, ok := sess.Next(now); ok {
+		t.Fatal("expected no finished turn")
+	}
+
+	if !sess.Extend(5 * time.Minute) {
+		t.Fatal("expected current turn to be extended")
+	}
+
+	current, _ := sess.Current()
+	if current.MemberID != "u1" || !current.Deadline().Equal(now.Add(15*time.Minute)) {
+		t.Fatalf("unexpected current turn: %+v", current)
+	}
+
+	finished, ok := sess.Next(now.Add(15 * time.Minute))
+	if !ok || finished.MemberID != "u1" || finished.State != TurnCompleted {
+		t.Fatalf("expected u1 to be completed, got %+v", finished)
+	}
+
+	skipped, ok := sess.Skip(now.Add(16 * time.Minute))
+	if !ok || skipped.MemberID != "u2" || skipped.State != TurnSkipped {
+		t.Fatalf("expected u2 to be skipped, got %+v", skipped)
+	}
+
+	current, _ = sess.Current()
+	if current.MemberID != "u3" || !current.StartedAt.Equal(now.Add(16*time.Minute)) {
+		t.Fatalf("expected u3 to be speaking, got %+v", current)
+	}
+
+	finished, ok = sess.Next(now.Add(30 * time.Minute))
+	if !ok || finished.MemberID != "u3" || !sess.Done() {
+		t.Fatalf("expected session to be done, got %+v", finished)
+	}
+}
+
+func TestSessionEnd(t *testing.T) {
+	sess := testSession()
+	now := time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC)
+
+	// skipping before start skips the first speaker
+	skipped, _ := sess.Skip(now)
+	if skipped.MemberID != "u1" {
+		t.Fatalf("expected u1 to be skipped, got %+v", skipped)
+	}
+
+	finished, ok := sess.End()
+	if !ok || finished.MemberID != "u2" || !sess.Done() {
+		t.Fatalf("expected u2 to be completed on end, got %+v", finished)
+	}
+
+	if sess.Turns[2].State != TurnSkipped {
+		t.Fatalf("expected the rest to be skipped, got %+v", sess.Turns[2])
+	}
+
+	if sess.Extend(time.Minute) {
+		t.Fatal("expected ended session not to be extended")
+	}
+}
@@ -0,0 +1,125 @@
+package material
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// max size of the attachment, same as the upload limit of discord
+const MaxAttachmentSize = 25 << 20
+
+const defaultHashTimeout = 30 * time.Second
+
+// extensions of files accepted as materials
+var AllowedExtensions = []string{".pdf", ".ppt", ".pptx", ".key", ".odp", ".md", ".zip"}
+
+// check type and size of the attachment
+func CheckAttachment(filename string, size int64) error {
+	ext := strings.ToLower(path.Ext(filename))
+
+	allowed := false
+
+	for _, e := range AllowedExtensions {
+		if ext == e {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		return errors.Join(study.ErrInvalidMaterialFile, fmt.Errorf("허용된 파일 형식: %s", strings.Join(AllowedExtensions, ", ")))
+	}
+
+	if size <= 0 || size > MaxAttachmentSize {
+		return errors.Join(study.ErrInvalidMaterialFile, fmt.Errorf("파일 크기는 %dMB 이하여야 합니다", MaxAttachmentSize>>20))
+	}
+
+	return nil
+}
+
+// File is the downloaded attachment
+type File struct {
+	Data     []byte
+	Checksum string // hex encoded sha256
+}
+
+// Hasher downloads the uploaded attachment and computes its checksum
+type Hasher interface {
+	Checksum(ctx context.Context, rawURL string) (string, error)
+	Download(ctx context.Context, rawURL string) (File, error)
+}
+
+type hasher struct {
+	client *http.Client
+}
+
+type HashOptsFunc func(*hasher)
+
+func WithHashClient(c *http.Client) HashOptsFunc {
+	return func(h *hasher) {
+		h.client = c
+	}
+}
+
+func NewHasher(opts ...HashOptsFunc) Hasher {
+	h := &hasher{
+		client: &http.Client{Timeout: defaultHashTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// download the attachment and return hex encoded sha256
+func (h *hasher) Checksum(ctx context.Context, rawURL string) (string, error) {
+	f, err := h.Download(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	return f.Checksum, nil
+}
+
+// download the attachment into memory, the size is limited by MaxAttachmentSize
+func (h *hasher) Download(ctx context.Context, rawURL string) (File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return File{}, errors.Join(study.ErrUnreachableMaterial, err)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return File{}, errors.Join(study.ErrUnreachableMaterial, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return File{}, errors.Join(study.ErrUnreachableMaterial, fmt.Errorf("응답 코드: %d", resp.StatusCode))
+	}
+
+	// read one more byte to detect oversized files
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxAttachmentSize+1))
+	if err != nil {
+		return File{}, errors.Join(study.ErrUnreachableMaterial, err)
+	}
+
+	if len(data) > MaxAttachmentSize {
+		return File{}, errors.Join(study.ErrInvalidMaterialFile, fmt.Errorf("파일 크기는 %dMB 이하여야 합니다", MaxAttachmentSize>>20))
+	}
+
+	sum := sha256.Sum256(data)
+
+	return File{Data: data, Checksum: hex.EncodeToString(sum[:])}, nil
+}
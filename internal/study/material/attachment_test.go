@@ -0,0 +1,80 @@
+package material
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestCheckAttachment(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		size     int64
+		ok       bool
+	}{
+		{"pdf", "deck.pdf", 1024, true},
+		{"upper case extension", "DECK.PPTX", 1024, true},
+		{"not allowed type", "run.exe", 1024, false},
+		{"no extension", "deck", 1024, false},
+		{"empty file", "deck.pdf", 0, false},
+		{"too large", "deck.pdf", MaxAttachmentSize + 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAttachment(tt.filename, tt.size)
+			if tt.ok && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, study.ErrInvalidMaterialFile) {
+				t.Fatalf("expected ErrInvalidMaterialFile, got %v", err)
+			}
+		})
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	body := []byte("presentation material")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/deck.pdf" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	h := NewHasher(WithHashClient(srv.Client()))
+
+	sum, err := h.Checksum(context.Background(), srv.URL+"/deck.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := sha256.Sum256(body)
+	if sum != hex.EncodeToString(want[:]) {
+		t.Fatalf("expected %x, got %s", want, sum)
+	}
+
+	if _, err := h.Checksum(context.Background(), srv.URL+"/missing.pdf"); !errors.Is(err, study.ErrUnreachableMaterial) {
+		t.Fatalf("expected ErrUnreachableMaterial, got %v", err)
+	}
+
+	f, err := h.Download(context.Background(), srv.URL+"/deck.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(f.Data, body) || f.Checksum != sum {
+		t.Fatalf("unexpected file: %q %s", f.Data, f.Checksum)
+	}
+}
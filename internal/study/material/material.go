@@ -0,0 +1,154 @@
+package material
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+// hosts allowed when the study has no allowlist,
+// "*.example.com" matches subdomains and ".pdf" matches files of any host
+var DefaultHosts = []string{
+	"docs.google.com",
+	"drive.google.com",
+	"github.com",
+	"gist.github.com",
+	"*.github.io",
+	"notion.so",
+	"www.notion.so",
+	"*.notion.site",
+	".pdf",
+}
+
+// Preview is the summary of the submitted material
+type Preview struct {
+	URL           string
+	Kind          string
+	Host          string
+	StatusCode    int    // zero if not probed
+	ContentType   string // empty if unknown
+	ContentLength int64  // negative if unknown
+}
+
+func (p Preview) Probed() bool {
+	return p.StatusCode != 0
+}
+
+// parse the url and check it against the allowlist, defaults are used if hosts is empty
+func Check(rawURL string, hosts []string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return nil, errors.Join(study.ErrInvalidMaterialURL, fmt.Errorf("올바른 링크 형식이 아닙니다: %s", rawURL))
+	}
+
+	if u.Scheme != "https" {
+		return nil, errors.Join(study.ErrInvalidMaterialURL, errors.New("https 링크만 제출할 수 있습니다"))
+	}
+
+	// rules like ".pdf" accept any host, so internal addresses are rejected first
+	if isInternalHost(u.Hostname()) {
+		return nil, errors.Join(study.ErrInvalidMaterialURL, errors.New("내부 네트워크 주소는 제출할 수 없습니다"))
+	}
+
+	if len(hosts) == 0 {
+		hosts = DefaultHosts
+	}
+
+	for _, h := range hosts {
+		if match(h, u) {
+			return u, nil
+		}
+	}
+
+	return nil, errors.Join(study.ErrInvalidMaterialURL, fmt.Errorf("허용된 도메인: %s", strings.Join(hosts, ", ")))
+}
+
+func match(rule string, u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+
+	switch {
+	case strings.HasPrefix(rule, "*."):
+		return strings.HasSuffix(host, rule[1:])
+	case strings.HasPrefix(rule, "."):
+		return strings.EqualFold(path.Ext(u.Path), rule)
+	default:
+		return host == rule
+	}
+}
+
+// localhost or a private, loopback, link-local or unspecified ip
+func isInternalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && isInternalIP(ip)
+}
+
+func isInternalIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()
+}
+
+// parse comma separated rules of the allowlist
+func ParseHosts(txt string) ([]string, error) {
+	var hosts []string
+
+	for _, h := range strings.Split(txt, ",") {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			continue
+		}
+
+		if strings.ContainsAny(h, "/: ") || strings.Trim(strings.TrimPrefix(h, "*"), ".") == "" {
+			return nil, errors.Join(study.ErrInvalidArgs, fmt.Errorf("올바르지 않은 도메인입니다: %s", h))
+		}
+
+		hosts = append(hosts, h)
+	}
+
+	if len(hosts) == 0 {
+		return nil, errors.Join(study.ErrRequiredArgs, errors.New("허용할 도메인을 입력해주세요"))
+	}
+
+	return hosts, nil
+}
+
+// kind of the material guessed from the url
+func Kind(u *url.URL) string {
+	host := strings.ToLower(u.Hostname())
+
+	switch {
+	case strings.EqualFold(path.Ext(u.Path), ".pdf"):
+		return "PDF"
+	case host == "docs.google.com" && strings.HasPrefix(u.Path, "/presentation"):
+		return "Google Slides"
+	case host == "docs.google.com" && strings.HasPrefix(u.Path, "/document"):
+		return "Google Docs"
+	case host == "docs.google.com" || host == "drive.google.com":
+		return "Google Drive"
+	case host == "github.com" || host == "gist.github.com" || strings.HasSuffix(host, ".github.io"):
+		return "GitHub"
+	case host == "notion.so" || strings.HasSuffix(host, ".notion.so") || strings.HasSuffix(host, ".notion.site"):
+		return "Notion"
+	default:
+		return "웹 페이지"
+	}
+}
+
+// preview of the material without probing
+func NewPreview(u *url.URL) Preview {
+	return Preview{
+		URL:           u.String(),
+		Kind:          Kind(u),
+		Host:          u.Hostname(),
+		ContentLength: -1,
+	}
+}
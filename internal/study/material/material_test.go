@@ -0,0 +1,156 @@
+package material
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		hosts []string
+		ok    bool
+	}{
+		{"google slides", "https://docs.google.com/presentation/d/abc/edit", nil, true},
+		{"github pages", "https://user.github.io/slides", nil, true},
+		{"notion site", "https://team.notion.site/page", nil, true},
+		{"pdf of any host", "https://example.com/files/deck.PDF", nil, true},
+		{"not allowed host", "https://example.com/deck", nil, false},
+		{"http scheme", "http://github.com/user/repo", nil, false},
+		{"no host", "github.com/user/repo", nil, false},
+		{"suffix is not subdomain", "https://evilgithub.io/x", []string{"*.github.io"}, false},
+		{"custom allowlist", "https://slides.com/deck", []string{"slides.com"}, true},
+		{"custom allowlist excludes defaults", "https://github.com/user/repo", []string{"slides.com"}, false},
+		{"pdf of loopback ip", "https://127.0.0.1/deck.pdf", nil, false},
+		{"pdf of private ip", "https://10.0.0.5/deck.pdf", nil, false},
+		{"pdf of link-local ip", "https://169.254.169.254/deck.pdf", nil, false},
+		{"pdf of ipv6 loopback", "https://[::1]/deck.pdf", nil, false},
+		{"pdf of localhost", "https://localhost./deck.pdf", nil, false},
+		{"pdf of public ip", "https://93.184.216.34/deck.pdf", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Check(tt.url, tt.hosts)
+			if tt.ok && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, study.ErrInvalidMaterialURL) {
+				t.Fatalf("expected ErrInvalidMaterialURL, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseHosts(t *testing.T) {
+	hosts, err := ParseHosts(" Docs.Google.com, *.notion.site ,.pdf,")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"docs.google.com", "*.notion.site", ".pdf"}
+	if len(hosts) != len(want) {
+		t.Fatalf("expected %v, got %v", want, hosts)
+	}
+	for n := range want {
+		if hosts[n] != want[n] {
+			t.Fatalf("expected %v, got %v", want, hosts)
+		}
+	}
+
+	for _, txt := range []string{"", "https://github.com", "*.", "."} {
+		if _, err := ParseHosts(txt); err == nil {
+			t.Fatalf("expected error for %q", txt)
+		}
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := map[string]string{
+		"https://docs.google.com/presentation/d/abc": "Google Slides",
+		"https://github.com/user/repo":               "GitHub",
+		"https://www.notion.so/page":                 "Notion",
+		"https://example.com/deck.pdf":               "PDF",
+		"https://example.com/deck":                   "웹 페이지",
+	}
+
+	for raw, want := range tests {
+		u, _ := url.Parse(raw)
+		if got := Kind(u); got != want {
+			t.Errorf("%s: expected %s, got %s", raw, want, got)
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD, got %s", r.Method)
+		}
+
+		switch r.URL.Path {
+		case "/deck.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Length", "2048")
+		case "/moved":
+			http.Redirect(w, r, "/deck.pdf", http.StatusFound)
+		case "/no-head":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewProber(WithProbeClient(srv.Client()))
+
+	u, _ := url.Parse(srv.URL + "/deck.pdf")
+
+	preview, err := p.Probe(context.Background(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Kind != "PDF" || preview.ContentType != "application/pdf" || preview.ContentLength != 2048 || !preview.Probed() {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+
+	u, _ = url.Parse(srv.URL + "/no-head")
+
+	if _, err := p.Probe(context.Background(), u); err != nil {
+		t.Fatalf("server not allowing HEAD should be reachable: %v", err)
+	}
+
+	u, _ = url.Parse(srv.URL + "/moved")
+
+	preview, err = p.Probe(context.Background(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.StatusCode != http.StatusFound || preview.ContentType != "" {
+		t.Fatalf("redirect should not be followed: %+v", preview)
+	}
+
+	u, _ = url.Parse(srv.URL + "/missing")
+
+	if _, err := p.Probe(context.Background(), u); !errors.Is(err, study.ErrUnreachableMaterial) {
+		t.Fatalf("expected ErrUnreachableMaterial, got %v", err)
+	}
+}
+
+func TestProbeRejectsInternalAddress(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL + "/deck.pdf")
+
+	if _, err := NewProber().Probe(context.Background(), u); !errors.Is(err, study.ErrUnreachableMaterial) {
+		t.Fatalf("expected ErrUnreachableMaterial, got %v", err)
+	}
+}
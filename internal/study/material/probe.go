@@ -0,0 +1,110 @@
+package material
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+const defaultProbeTimeout = 5 * time.Second
+
+// Prober checks that the material is reachable
+type Prober interface {
+	Probe(ctx context.Context, u *url.URL) (Preview, error)
+}
+
+type prober struct {
+	client *http.Client
+}
+
+type ProbeOptsFunc func(*prober)
+
+func WithProbeClient(c *http.Client) ProbeOptsFunc {
+	return func(p *prober) {
+		p.client = c
+	}
+}
+
+func NewProber(opts ...ProbeOptsFunc) Prober {
+	p := &prober{
+		client: &http.Client{
+			Transport: publicTransport(),
+			Timeout:   defaultProbeTimeout,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// send a HEAD request, servers not allowing HEAD are regarded as reachable
+func (p *prober) Probe(ctx context.Context, u *url.URL) (Preview, error) {
+	preview := NewPreview(u)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return preview, errors.Join(study.ErrUnreachableMaterial, err)
+	}
+
+	// redirects are not followed, the location may point to an internal address
+	client := *p.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return preview, errors.Join(study.ErrUnreachableMaterial, err)
+	}
+	defer resp.Body.Close()
+
+	preview.StatusCode = resp.StatusCode
+
+	switch {
+	case resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+		return preview, nil
+	case resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest:
+		// headers of the redirect do not describe the material
+		return preview, nil
+	case resp.StatusCode >= http.StatusBadRequest:
+		return preview, errors.Join(study.ErrUnreachableMaterial, fmt.Errorf("응답 코드: %d", resp.StatusCode))
+	}
+
+	preview.ContentType = resp.Header.Get("Content-Type")
+	preview.ContentLength = resp.ContentLength
+
+	return preview, nil
+}
+
+// transport refusing to connect to internal addresses after the host is resolved
+func publicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: defaultProbeTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+				return fmt.Errorf("내부 네트워크 주소에는 연결할 수 없습니다: %s", host)
+			}
+			return nil
+		},
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = dialer.DialContext
+	return t
+}
@@ -1,5 +1,7 @@
 package study
 
+import "time"
+
 type Member struct {
 	Name           string          `bson:"name" json:"name"`
 	Subject        string          `bson:"subject" json:"subject"`
@@ -8,6 +10,28 @@ type Member struct {
 	Attended       bool            `bson:"attended" json:"attended"`
 	SentReflection bool            `bson:"sent_reflection" json:"sent_reflection,omitempty"`
 	Reviewers      map[string]bool `bson:"reviewers" json:"reviewers,omitempty"`
+	ThreadID       string          `bson:"thread_id" json:"thread_id,omitempty"` // discussion thread of the presentation
+	RegisteredAt   time.Time       `bson:"registered_at" json:"registered_at,omitempty"`
+	Materials      []Material      `bson:"materials" json:"materials,omitempty"`           // submitted in order, the last one is the content url
+	ExtendedUntil  time.Time       `bson:"extended_until" json:"extended_until,omitempty"` // deadline of the submission granted by the manager
+}
+
+// max number of materials submitted by a speaker
+const MaxMaterials = 5
+
+// Material is a link or a file attachment submitted by the speaker
+type Material struct {
+	URL         string    `bson:"url" json:"url"` // cdn url of the attachment
+	Filename    string    `bson:"filename" json:"filename,omitempty"`
+	Size        int64     `bson:"size" json:"size,omitempty"`
+	ContentType string    `bson:"content_type" json:"content_type,omitempty"`
+	Checksum    string    `bson:"checksum" json:"checksum,omitempty"` // hex encoded sha256 of the attachment
+	SubmittedAt time.Time `bson:"submitted_at" json:"submitted_at"`
+	Late        bool      `bson:"late" json:"late,omitempty"` // submitted after the deadline
+}
+
+func (m Material) IsAttachment() bool {
+	return m.Filename != ""
 }
 
 func NewMember() Member {
@@ -33,10 +57,30 @@ func (m *Member) SetContentURL(contentURL string) {
 	m.ContentURL = contentURL
 }
 
+// add the material and make it the content url
+func (m *Member) AddMaterial(material Material) {
+	m.Materials = append(m.Materials, material)
+	m.ContentURL = material.URL
+}
+
+// whether the same link or file is already submitted
+func (m Member) HasMaterial(material Material) bool {
+	for _, mat := range m.Materials {
+		if mat.URL == material.URL || (material.Checksum != "" && mat.Checksum == material.Checksum) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Member) SetRegistered(registered bool) {
 	m.Registered = registered
 }
 
+func (m *Member) SetRegisteredAt(t time.Time) {
+	m.RegisteredAt = t
+}
+
 func (m Member) IsRegistered() bool {
 	return m.Registered
 }
@@ -64,3 +108,7 @@ func (m *Member) SetReviewer(userID string) {
 func (m Member) IsReviewer(userID string) bool {
 	return m.Reviewers[userID]
 }
+
+func (m *Member) SetThreadID(threadID string) {
+	m.ThreadID = threadID
+}
@@ -0,0 +1,108 @@
+package study
+
+import (
+	"strconv"
+	"time"
+)
+
+// max number of options of a select menu
+const MaxTopicProposals = 25
+
+// TopicProposal is a candidate title of the next round
+type TopicProposal struct {
+	ID         string    `bson:"id" json:"id"`
+	Title      string    `bson:"title" json:"title"`
+	ProposerID string    `bson:"proposer_id" json:"proposer_id"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+}
+
+// TopicPoll decides the title of the next round by votes of members
+type TopicPoll struct {
+	ChannelID string            `bson:"channel_id" json:"channel_id"`
+	MessageID string            `bson:"message_id" json:"message_id"`
+	Proposals []TopicProposal   `bson:"proposals" json:"proposals"`
+	Votes     map[string]string `bson:"votes" json:"votes"` // member id to proposal id
+	CreatedAt time.Time         `bson:"created_at" json:"created_at"`
+}
+
+func NewTopicPoll(channelID, messageID string) TopicPoll {
+	return TopicPoll{
+		ChannelID: channelID,
+		MessageID: messageID,
+		Proposals: []TopicProposal{},
+		Votes:     map[string]string{},
+		CreatedAt: time.Now(),
+	}
+}
+
+func (s *Study) SetTopicPoll(p *TopicPoll) {
+	s.TopicPoll = p
+}
+
+// add a proposal with the next id
+func (p *TopicPoll) Propose(title, proposerID string) TopicProposal {
+	proposal := TopicProposal{
+		ID:         strconv.Itoa(len(p.Proposals) + 1),
+		Title:      title,
+		ProposerID: proposerID,
+		CreatedAt:  time.Now(),
+	}
+
+	p.Proposals = append(p.Proposals, proposal)
+
+	return proposal
+}
+
+// each member has only one vote, the previous vote is replaced
+func (p *TopicPoll) Vote(memberID, proposalID string) {
+	if p.Votes == nil {
+		p.Votes = map[string]string{}
+	}
+
+	p.Votes[memberID] = proposalID
+}
+
+func (p TopicPoll) GetProposal(id string) (TopicProposal, bool) {
+	for _, proposal := range p.Proposals {
+		if proposal.ID == id {
+			return proposal, true
+		}
+	}
+	return TopicProposal{}, false
+}
+
+func (p TopicPoll) HasProposed(memberID string) bool {
+	for _, proposal := range p.Proposals {
+		if proposal.ProposerID == memberID {
+			return true
+		}
+	}
+	return false
+}
+
+func (p TopicPoll) Count(proposalID string) int {
+	cnt := 0
+
+	for _, id := range p.Votes {
+		if id == proposalID {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+// proposal with the most votes, the earlier one wins a tie
+func (p TopicPoll) Winner() (TopicProposal, bool) {
+	var winner TopicProposal
+
+	best := -1
+
+	for _, proposal := range p.Proposals {
+		if cnt := p.Count(proposal.ID); cnt > best {
+			winner, best = proposal, cnt
+		}
+	}
+
+	return winner, best >= 0
+}
@@ -0,0 +1,61 @@
+package study
+
+import "testing"
+
+func TestTopicPollWinner(t *testing.T) {
+	tests := []struct {
+		name   string
+		titles []string
+		votes  map[string]string
+		winner string
+		ok     bool
+	}{
+		{"no proposals", nil, nil, "", false},
+		{"zero votes picks the earliest", []string{"채널", "제네릭"}, nil, "채널", true},
+		{"most votes", []string{"채널", "제네릭"}, map[string]string{"u1": "2", "u2": "2", "u3": "1"}, "제네릭", true},
+		{"tie picks the earliest", []string{"채널", "제네릭", "리플렉션"}, map[string]string{"u1": "3", "u2": "2"}, "제네릭", true},
+		{"votes of unknown proposals are ignored", []string{"채널", "제네릭"}, map[string]string{"u1": "9", "u2": "9", "u3": "2"}, "제네릭", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTopicPoll("channel", "message")
+
+			for n, title := range tt.titles {
+				p.Propose(title, string(rune('a'+n)))
+			}
+
+			for memberID, proposalID := range tt.votes {
+				p.Vote(memberID, proposalID)
+			}
+
+			winner, ok := p.Winner()
+			if ok != tt.ok || winner.Title != tt.winner {
+				t.Fatalf("winner = %q (%v), want %q (%v)", winner.Title, ok, tt.winner, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTopicPollVoteReplacesPrevious(t *testing.T) {
+	p := TopicPoll{}
+
+	first := p.Propose("채널", "a")
+	second := p.Propose("제네릭", "b")
+
+	// poll loaded without votes accepts votes
+	p.Vote("u1", first.ID)
+	p.Vote("u1", second.ID)
+
+	if p.Count(first.ID) != 0 || p.Count(second.ID) != 1 || len(p.Votes) != 1 {
+		t.Fatalf("expected the previous vote to be replaced, got %v", p.Votes)
+	}
+
+	if !p.HasProposed("a") || p.HasProposed("u1") {
+		t.Fatal("unexpected proposers")
+	}
+
+	if got, ok := p.GetProposal(second.ID); !ok || got.Title != "제네릭" {
+		t.Fatalf("unexpected proposal %+v", got)
+	}
+}
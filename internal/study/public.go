@@ -0,0 +1,80 @@
+package study
+
+import "time"
+
+// PublicMember is the member shared outside of the bot by exports, the api and webhooks,
+// reviewers are left out since the feedback is sent anonymously
+type PublicMember struct {
+	Name           string     `json:"name"`
+	Subject        string     `json:"subject"`
+	ContentURL     string     `json:"content_url"`
+	Registered     bool       `json:"registered"`
+	Attended       bool       `json:"attended"`
+	SentReflection bool       `json:"sent_reflection,omitempty"`
+	ThreadID       string     `json:"thread_id,omitempty"`
+	RegisteredAt   time.Time  `json:"registered_at,omitempty"`
+	Materials      []Material `json:"materials,omitempty"`
+	ExtendedUntil  time.Time  `json:"extended_until,omitempty"`
+}
+
+func NewPublicMember(m Member) PublicMember {
+	return PublicMember{
+		Name:           m.Name,
+		Subject:        m.Subject,
+		ContentURL:     m.ContentURL,
+		Registered:     m.Registered,
+		Attended:       m.Attended,
+		SentReflection: m.SentReflection,
+		ThreadID:       m.ThreadID,
+		RegisteredAt:   m.RegisteredAt,
+		Materials:      m.Materials,
+		ExtendedUntil:  m.ExtendedUntil,
+	}
+}
+
+// PublicRound is the round shared outside of the bot with members of the same layout
+type PublicRound struct {
+	ID      string `json:"id,omitempty"`
+	GuildID string `json:"guild_id,omitempty"`
+
+	Number     int8                    `json:"number"`
+	Stage      Stage                   `json:"stage"`
+	Title      string                  `json:"title"`
+	ContentURL string                  `json:"content_url"`
+	Members    map[string]PublicMember `json:"members"`
+	Schedule   []ScheduleEntry         `json:"schedule,omitempty"`
+	Timetable  []Slot                  `json:"timetable,omitempty"`
+
+	MaxSpeakers int      `json:"max_speakers,omitempty"`
+	Waitlist    []string `json:"waitlist,omitempty"`
+
+	ScheduledEventID string `json:"scheduled_event_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewPublicRound(r Round) PublicRound {
+	members := make(map[string]PublicMember, len(r.Members))
+
+	for id, m := range r.Members {
+		members[id] = NewPublicMember(m)
+	}
+
+	return PublicRound{
+		ID:               r.ID,
+		GuildID:          r.GuildID,
+		Number:           r.Number,
+		Stage:            r.Stage,
+		Title:            r.Title,
+		ContentURL:       r.ContentURL,
+		Members:          members,
+		Schedule:         r.Schedule,
+		Timetable:        r.Timetable,
+		MaxSpeakers:      r.MaxSpeakers,
+		Waitlist:         r.Waitlist,
+		ScheduledEventID: r.ScheduledEventID,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+	}
+}
@@ -82,6 +82,11 @@ func (si *mongoStore) UpdateStudy(ctx context.Context, s study.Study) (*study.St
 				{Key: "spreadsheet_url", Value: s.SpreadsheetURL},
 				{Key: "current_stage", Value: s.CurrentStage},
 				{Key: "total_round", Value: s.TotalRound},
+				{Key: "webhooks", Value: s.Webhooks},
+				{Key: "calendar_token", Value: s.CalendarToken},
+				{Key: "presentation_channel_id", Value: s.PresentationChannelID},
+				{Key: "topic_poll", Value: s.TopicPoll},
+				{Key: "material_hosts", Value: s.MaterialHosts},
 				{Key: "updated_at", Value: s.UpdatedAt},
 			},
 		},
@@ -115,6 +120,11 @@ func (si mongoStore) UpdateRound(ctx context.Context, r study.Round) (*study.Rou
 				{Key: "content_url", Value: r.ContentURL},
 				{Key: "stage", Value: r.Stage},
 				{Key: "members", Value: r.Members},
+				{Key: "schedule", Value: r.Schedule},
+				{Key: "timetable", Value: r.Timetable},
+				{Key: "max_speakers", Value: r.MaxSpeakers},
+				{Key: "waitlist", Value: r.Waitlist},
+				{Key: "scheduled_event_id", Value: r.ScheduledEventID},
 				{Key: "updated_at", Value: r.UpdatedAt},
 			},
 		},
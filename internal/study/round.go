@@ -13,6 +13,13 @@ type Round struct {
 	Title      string            `bson:"title" json:"title"`
 	ContentURL string            `bson:"content_url" json:"content_url"`
 	Members    map[string]Member `bson:"members" json:"members"`
+	Schedule   []ScheduleEntry   `bson:"schedule" json:"schedule,omitempty"`
+	Timetable  []Slot            `bson:"timetable" json:"timetable,omitempty"` // order of the presentation
+
+	MaxSpeakers int      `bson:"max_speakers" json:"max_speakers,omitempty"` // zero means unlimited
+	Waitlist    []string `bson:"waitlist" json:"waitlist,omitempty"`         // member ids waiting for vacancies
+
+	ScheduledEventID string `bson:"scheduled_event_id" json:"scheduled_event_id,omitempty"` // discord scheduled event of the presentation
 
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
@@ -54,6 +61,10 @@ func (r *Round) SetStage(stage Stage) {
 	r.Stage = stage
 }
 
+func (r *Round) SetScheduledEventID(eventID string) {
+	r.ScheduledEventID = eventID
+}
+
 func (r *Round) SetMember(memberID string, member Member) {
 	r.Members[memberID] = member
 }
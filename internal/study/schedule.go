@@ -0,0 +1,46 @@
+package study
+
+import (
+	"sort"
+	"time"
+)
+
+// ScheduleEntry is the planned start of a stage of the round,
+// the start of a closing stage is the deadline of the previous one
+type ScheduleEntry struct {
+	Stage Stage     `bson:"stage" json:"stage"`
+	At    time.Time `bson:"at" json:"at"`
+}
+
+// stages which can be scheduled
+func (s Stage) IsSchedulable() bool {
+	return s >= StageRegistrationOpened && s <= StageFinished
+}
+
+// set start of the stage, zero time removes the entry
+func (r *Round) SetSchedule(stage Stage, at time.Time) {
+	schedule := make([]ScheduleEntry, 0, len(r.Schedule)+1)
+
+	for _, e := range r.Schedule {
+		if e.Stage != stage {
+			schedule = append(schedule, e)
+		}
+	}
+
+	if !at.IsZero() {
+		schedule = append(schedule, ScheduleEntry{Stage: stage, At: at})
+	}
+
+	sort.Slice(schedule, func(i, j int) bool { return schedule[i].Stage < schedule[j].Stage })
+
+	r.Schedule = schedule
+}
+
+func (r Round) ScheduleOf(stage Stage) (time.Time, bool) {
+	for _, e := range r.Schedule {
+		if e.Stage == stage {
+			return e.At, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/piatoss3612/my-study-bot/internal/study"
 	"github.com/piatoss3612/my-study-bot/internal/study/repository"
@@ -34,10 +35,11 @@ func New(tx repository.Tx) Service {
 }
 
 type NewRoundParams struct {
-	GuildID   string
-	ManagerID string
-	Title     string
-	MemberIDs []string
+	GuildID     string
+	ManagerID   string
+	Title       string
+	MemberIDs   []string
+	MaxSpeakers int // zero means unlimited
 }
 
 type NewStudyParams struct {
@@ -57,6 +59,22 @@ type UpdateParams struct {
 	ContentURL string
 	ReviewerID string
 	RevieweeID string
+	RoundID    string // target round instead of the ongoing one
+	Changes    []study.RoundChange
+	Webhook    study.Webhook
+	Schedule   []study.ScheduleEntry // zero time removes the entry
+	Token      string
+	EventID    string            // discord scheduled event
+	Threads    map[string]string // member id to thread id
+	Timetable  []study.Slot
+	Swap       [2]int // orders of the slots to be swapped
+	MessageID  string
+	Proposal   study.TopicProposal
+	Hosts      []string // allowed hosts of materials
+	Material   study.Material
+	Until      time.Time // extended deadline, zero revokes the extension
+
+	Promoted []string // set by the update, members promoted from the waitlist
 }
 
 type UpdateFunc func(*study.Study, *study.Round, *UpdateParams)
@@ -165,6 +183,7 @@ func (svc *studyService) NewRound(ctx context.Context, params *NewRoundParams) (
 		r.SetNumber(s.TotalRound)
 		r.SetTitle(params.Title)
 		r.SetStage(study.StageRegistrationOpened)
+		r.SetMaxSpeakers(params.MaxSpeakers)
 
 		// set initial members
 		for _, id := range params.MemberIDs {
@@ -262,16 +281,22 @@ func (svc *studyService) UpdateRound(ctx context.Context, params *UpdateParams,
 			return nil, study.ErrStudyNotFound
 		}
 
-		if s.OngoingRoundID == "" {
+		roundID := params.RoundID
+		if roundID == "" {
+			roundID = s.OngoingRoundID
+		}
+
+		if roundID == "" {
 			return nil, study.ErrRoundNotFound
 		}
 
-		r, err := svc.tx.FindRound(sc, s.OngoingRoundID)
+		r, err := svc.tx.FindRound(sc, roundID)
 		if err != nil {
 			return nil, err
 		}
 
-		if r == nil {
+		// round should belong to the study of guild
+		if r == nil || r.GuildID != s.GuildID {
 			return nil, study.ErrRoundNotFound
 		}
 
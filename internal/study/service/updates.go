@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/piatoss3612/my-study-bot/internal/study"
 )
 
@@ -18,6 +20,13 @@ func MoveStage(s *study.Study, r *study.Round, _ *UpdateParams) {
 	r.SetStage(next)
 }
 
+func RollbackStage(s *study.Study, r *study.Round, _ *UpdateParams) {
+	prev := s.CurrentStage.Prev()
+
+	s.SetCurrentStage(prev)
+	r.SetStage(prev)
+}
+
 func UpdateManagerID(s *study.Study, _ *study.Round, params *UpdateParams) {
 	s.SetManagerID(params.ManagerID)
 }
@@ -30,6 +39,10 @@ func UpdateReflectionChannelID(s *study.Study, _ *study.Round, params *UpdatePar
 	s.SetReflectionChannelID(params.ChannelID)
 }
 
+func UpdatePresentationChannelID(s *study.Study, _ *study.Round, params *UpdateParams) {
+	s.SetPresentationChannelID(params.ChannelID)
+}
+
 func RegisterMember(_ *study.Study, r *study.Round, params *UpdateParams) {
 	member, ok := r.GetMember(params.MemberID)
 	if !ok {
@@ -38,14 +51,54 @@ func RegisterMember(_ *study.Study, r *study.Round, params *UpdateParams) {
 
 	member.SetName(params.MemberName)
 	member.SetSubject(params.Subject)
+
+	// order of registration is kept when the registration is changed
+	if !member.IsRegistered() {
+		// member waits for a vacancy if the round is full
+		if r.IsFull() {
+			r.SetMember(params.MemberID, member)
+			r.AddToWaitlist(params.MemberID)
+			return
+		}
+
+		member.SetRegisteredAt(time.Now())
+	}
+
 	member.SetRegistered(true)
 
 	r.SetMember(params.MemberID, member)
 }
 
-func SubmitMemberContent(_ *study.Study, r *study.Round, params *UpdateParams) {
+func CancelRegistration(_ *study.Study, r *study.Round, params *UpdateParams) {
+	// member in the waitlist just leaves it
+	if r.RemoveFromWaitlist(params.MemberID) {
+		return
+	}
+
+	member, _ := r.GetMember(params.MemberID)
+	member.SetRegistered(false)
+	r.SetMember(params.MemberID, member)
+	r.RemoveSlot(params.MemberID)
+
+	// promoted members are returned to the caller, the update may run again if the transaction is retried
+	params.Promoted = r.PromoteWaitlist(time.Now())
+}
+
+func SubmitMemberContent(s *study.Study, r *study.Round, params *UpdateParams) {
+	material := params.Material
+	if material.URL == "" {
+		material.URL = params.ContentURL
+	}
+
 	member, _ := r.GetMember(params.MemberID)
-	member.SetContentURL(params.ContentURL)
+
+	now := time.Now()
+	_, late := r.SubmissionWindow(s.CurrentStage, member, now)
+
+	material.SubmittedAt = now
+	material.Late = late
+
+	member.AddMaterial(material)
 
 	r.SetMember(params.MemberID, member)
 }
@@ -78,3 +131,79 @@ func SetSentReflection(_ *study.Study, r *study.Round, params *UpdateParams) {
 func SetSpreadsheetURL(s *study.Study, _ *study.Round, params *UpdateParams) {
 	s.SetSpreadsheetURL(params.ContentURL)
 }
+
+func ApplyRoundChanges(_ *study.Study, r *study.Round, params *UpdateParams) {
+	for _, c := range params.Changes {
+		_ = r.ApplyChange(c)
+	}
+}
+
+func AddWebhook(s *study.Study, _ *study.Round, params *UpdateParams) {
+	s.AddWebhook(params.Webhook)
+}
+
+func RemoveWebhook(s *study.Study, _ *study.Round, params *UpdateParams) {
+	s.RemoveWebhook(params.Webhook.ID)
+}
+
+func UpdateSchedule(_ *study.Study, r *study.Round, params *UpdateParams) {
+	for _, e := range params.Schedule {
+		r.SetSchedule(e.Stage, e.At)
+	}
+}
+
+func UpdateCalendarToken(s *study.Study, _ *study.Round, params *UpdateParams) {
+	s.SetCalendarToken(params.Token)
+}
+
+func UpdateScheduledEventID(_ *study.Study, r *study.Round, params *UpdateParams) {
+	r.SetScheduledEventID(params.EventID)
+}
+
+func UpdateThreadIDs(_ *study.Study, r *study.Round, params *UpdateParams) {
+	for memberID, threadID := range params.Threads {
+		member, ok := r.GetMember(memberID)
+		if !ok {
+			continue
+		}
+
+		member.SetThreadID(threadID)
+		r.SetMember(memberID, member)
+	}
+}
+
+func UpdateTimetable(_ *study.Study, r *study.Round, params *UpdateParams) {
+	r.SetTimetable(params.Timetable)
+}
+
+func SwapSlots(_ *study.Study, r *study.Round, params *UpdateParams) {
+	r.SwapSlots(params.Swap[0], params.Swap[1])
+}
+
+func OpenTopicPoll(s *study.Study, _ *study.Round, params *UpdateParams) {
+	poll := study.NewTopicPoll(params.ChannelID, params.MessageID)
+	s.SetTopicPoll(&poll)
+}
+
+func CloseTopicPoll(s *study.Study, _ *study.Round, _ *UpdateParams) {
+	s.SetTopicPoll(nil)
+}
+
+func ProposeTopic(s *study.Study, _ *study.Round, params *UpdateParams) {
+	s.TopicPoll.Propose(params.Proposal.Title, params.MemberID)
+}
+
+func VoteTopic(s *study.Study, _ *study.Round, params *UpdateParams) {
+	s.TopicPoll.Vote(params.MemberID, params.Proposal.ID)
+}
+
+func UpdateMaterialHosts(s *study.Study, _ *study.Round, params *UpdateParams) {
+	s.SetMaterialHosts(params.Hosts)
+}
+
+func GrantExtension(_ *study.Study, r *study.Round, params *UpdateParams) {
+	member, _ := r.GetMember(params.MemberID)
+	member.SetExtendedUntil(params.Until)
+
+	r.SetMember(params.MemberID, member)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestCancelRegistrationReturnsPromoted(t *testing.T) {
+	r := study.NewRound()
+	r.SetMaxSpeakers(1)
+
+	speaker := study.NewMember()
+	speaker.SetRegistered(true)
+	r.SetMember("a", speaker)
+
+	r.SetMember("b", study.NewMember())
+	r.AddToWaitlist("b")
+
+	params := &UpdateParams{MemberID: "a"}
+
+	CancelRegistration(&study.Study{}, &r, params)
+
+	if !reflect.DeepEqual(params.Promoted, []string{"b"}) {
+		t.Fatalf("promoted = %v, want [b]", params.Promoted)
+	}
+
+	// member leaving the waitlist does not promote anyone
+	r.AddToWaitlist("c")
+	r.SetMember("c", study.NewMember())
+
+	params = &UpdateParams{MemberID: "c"}
+
+	CancelRegistration(&study.Study{}, &r, params)
+
+	if len(params.Promoted) != 0 {
+		t.Fatalf("expected nobody to be promoted, got %v", params.Promoted)
+	}
+}
@@ -3,6 +3,9 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"github.com/piatoss3612/my-study-bot/internal/study"
 )
@@ -21,6 +24,13 @@ func ValidateToCheckOngoingRound(s *study.Study, _ *study.Round, _ *UpdateParams
 	return nil
 }
 
+func ValidateToRollbackStage(s *study.Study, _ *study.Round, _ *UpdateParams) error {
+	if s.CurrentStage.Prev().IsNone() {
+		return errors.Join(study.ErrInvalidStage, fmt.Errorf("이전 단계로 되돌릴 수 없는 단계입니다"))
+	}
+	return nil
+}
+
 func ValidateToRegister(s *study.Study, r *study.Round, params *UpdateParams) error {
 	if params.MemberID == "" {
 		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("등록할 사용자 ID가 없습니다"))
@@ -35,6 +45,31 @@ func ValidateToRegister(s *study.Study, r *study.Round, params *UpdateParams) er
 		return study.ErrAlreadyRegistered
 	}
 
+	if r.WaitlistPosition(params.MemberID) > 0 {
+		return study.ErrAlreadyWaiting
+	}
+
+	return nil
+}
+
+func ValidateToCancelRegistration(s *study.Study, r *study.Round, params *UpdateParams) error {
+	if params.MemberID == "" {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("등록을 취소할 사용자 ID가 없습니다"))
+	}
+
+	if !s.CurrentStage.IsRegistrationOpened() {
+		return errors.Join(study.ErrInvalidStage, fmt.Errorf("발표자 등록 취소가 불가능한 단계입니다"))
+	}
+
+	member, ok := r.GetMember(params.MemberID)
+	if !ok {
+		return study.ErrMemberNotFound
+	}
+
+	if !member.IsRegistered() && r.WaitlistPosition(params.MemberID) == 0 {
+		return study.ErrNotRegistered
+	}
+
 	return nil
 }
 
@@ -64,10 +99,6 @@ func ValidateToSubmitMemberContent(s *study.Study, r *study.Round, params *Updat
 		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("발표자료를 제출할 사용자 ID가 없습니다"))
 	}
 
-	if !s.CurrentStage.IsSubmissionOpened() {
-		return errors.Join(study.ErrInvalidStage, fmt.Errorf("발표자료 제출이 불가능한 단계입니다"))
-	}
-
 	member, ok := r.GetMember(params.MemberID)
 	if !ok {
 		return study.ErrMemberNotFound
@@ -77,6 +108,28 @@ func ValidateToSubmitMemberContent(s *study.Study, r *study.Round, params *Updat
 		return study.ErrMemberNotRegistered
 	}
 
+	// submission after closing is allowed only within the extension
+	if allowed, _ := r.SubmissionWindow(s.CurrentStage, member, time.Now()); !allowed {
+		return errors.Join(study.ErrInvalidStage, fmt.Errorf("발표자료 제출이 불가능한 단계입니다"))
+	}
+
+	material := params.Material
+	if material.URL == "" {
+		material.URL = params.ContentURL
+	}
+
+	if material.URL == "" {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("제출할 발표자료가 없습니다"))
+	}
+
+	if len(member.Materials) >= study.MaxMaterials {
+		return errors.Join(study.ErrInvalidArgs, fmt.Errorf("발표자료는 최대 %d개까지 제출할 수 있습니다", study.MaxMaterials))
+	}
+
+	if member.HasMaterial(material) {
+		return errors.Join(study.ErrInvalidArgs, fmt.Errorf("이미 제출한 발표자료입니다"))
+	}
+
 	return nil
 }
 
@@ -175,3 +228,241 @@ func ValidateToSetSendReflection(s *study.Study, r *study.Round, params *UpdateP
 
 	return nil
 }
+
+func ValidateToSetSpreadsheetURL(_ *study.Study, _ *study.Round, params *UpdateParams) error {
+	if params.ContentURL == "" {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("스프레드시트 URL이 없습니다"))
+	}
+
+	if _, err := study.SpreadsheetIDFromURL(params.ContentURL); err != nil {
+		return errors.Join(err, fmt.Errorf("https://docs.google.com/spreadsheets/d/{ID} 형식의 URL을 입력해주세요"))
+	}
+
+	return nil
+}
+
+func ValidateToApplyRoundChanges(_ *study.Study, r *study.Round, params *UpdateParams) error {
+	if len(params.Changes) == 0 {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("적용할 변경 사항이 없습니다"))
+	}
+
+	for _, c := range params.Changes {
+		current, ok := r.FieldValue(c.MemberID, c.Field)
+		if !ok {
+			return study.ErrInvalidRoundChange
+		}
+
+		// round is changed after the changes are proposed
+		if current != c.Old {
+			return errors.Join(study.ErrInvalidRoundChange, fmt.Errorf("라운드 정보가 변경되었습니다. 다시 시도해주세요"))
+		}
+
+		if c.Field == study.RoundFieldMemberAttended {
+			if _, err := strconv.ParseBool(c.New); err != nil {
+				return errors.Join(study.ErrInvalidRoundChange, fmt.Errorf("발표 참여 여부는 true 또는 false로 입력해주세요"))
+			}
+		}
+	}
+
+	return nil
+}
+
+func ValidateToAddWebhook(s *study.Study, _ *study.Round, params *UpdateParams) error {
+	if params.Webhook.ID == "" || params.Webhook.URL == "" {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("등록할 웹훅 정보가 없습니다"))
+	}
+
+	if len(s.Webhooks) >= study.MaxWebhooks {
+		return errors.Join(study.ErrTooManyWebhooks, fmt.Errorf("웹훅은 최대 %d개까지 등록할 수 있습니다", study.MaxWebhooks))
+	}
+
+	for _, w := range s.Webhooks {
+		if w.URL == params.Webhook.URL {
+			return errors.Join(study.ErrInvalidWebhookURL, fmt.Errorf("이미 등록된 URL입니다"))
+		}
+	}
+
+	return nil
+}
+
+func ValidateToRemoveWebhook(s *study.Study, _ *study.Round, params *UpdateParams) error {
+	if _, ok := s.GetWebhook(params.Webhook.ID); !ok {
+		return study.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func ValidateToUpdateSchedule(_ *study.Study, r *study.Round, params *UpdateParams) error {
+	if len(params.Schedule) == 0 {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("변경할 일정이 없습니다"))
+	}
+
+	// check order of the schedule after update
+	updated := study.Round{Schedule: r.Schedule}
+
+	for _, e := range params.Schedule {
+		if !e.Stage.IsSchedulable() {
+			return errors.Join(study.ErrInvalidStage, fmt.Errorf("<%s> 단계는 일정을 설정할 수 없습니다", e.Stage))
+		}
+
+		updated.SetSchedule(e.Stage, e.At)
+	}
+
+	for i := 1; i < len(updated.Schedule); i++ {
+		prev, next := updated.Schedule[i-1], updated.Schedule[i]
+
+		if next.At.Before(prev.At) {
+			return errors.Join(study.ErrInvalidSchedule, fmt.Errorf("<%s> 일정이 <%s> 일정보다 빠릅니다", next.Stage, prev.Stage))
+		}
+	}
+
+	return nil
+}
+
+func ValidateToUpdateCalendarToken(_ *study.Study, _ *study.Round, params *UpdateParams) error {
+	if params.Token == "" {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("캘린더 토큰이 없습니다"))
+	}
+	return nil
+}
+
+func ValidateToUpdateTimetable(s *study.Study, r *study.Round, params *UpdateParams) error {
+	if s.CurrentStage >= study.StagePresentationFinished {
+		return errors.Join(study.ErrInvalidStage, fmt.Errorf("발표가 종료된 라운드의 발표 순서는 변경할 수 없습니다"))
+	}
+
+	if len(params.Timetable) == 0 {
+		return errors.Join(study.ErrInvalidTimetable, fmt.Errorf("등록된 발표자가 없습니다"))
+	}
+
+	seen := map[string]bool{}
+
+	for _, slot := range params.Timetable {
+		member, ok := r.GetMember(slot.MemberID)
+		if !ok || !member.IsRegistered() {
+			return study.ErrMemberNotRegistered
+		}
+
+		if seen[slot.MemberID] {
+			return errors.Join(study.ErrInvalidTimetable, fmt.Errorf("발표자가 중복되었습니다"))
+		}
+
+		if slot.Duration <= 0 {
+			return errors.Join(study.ErrInvalidTimetable, fmt.Errorf("발표 시간은 0보다 커야 합니다"))
+		}
+
+		seen[slot.MemberID] = true
+	}
+
+	return nil
+}
+
+func ValidateToSwapSlots(s *study.Study, r *study.Round, params *UpdateParams) error {
+	if s.CurrentStage >= study.StagePresentationFinished {
+		return errors.Join(study.ErrInvalidStage, fmt.Errorf("발표가 종료된 라운드의 발표 순서는 변경할 수 없습니다"))
+	}
+
+	if len(r.Timetable) == 0 {
+		return errors.Join(study.ErrInvalidTimetable, fmt.Errorf("발표 순서를 먼저 생성해주세요"))
+	}
+
+	a, b := params.Swap[0], params.Swap[1]
+
+	if a == b || a < 1 || b < 1 || a > len(r.Timetable) || b > len(r.Timetable) {
+		return errors.Join(study.ErrInvalidTimetable, fmt.Errorf("1부터 %d 사이의 서로 다른 순서를 입력해주세요", len(r.Timetable)))
+	}
+
+	return nil
+}
+
+func ValidateToOpenTopicPoll(s *study.Study, _ *study.Round, params *UpdateParams) error {
+	if !(s.CurrentStage.IsNone() || s.CurrentStage.IsWait()) {
+		return errors.Join(study.ErrRoundExists, fmt.Errorf("라운드가 종료된 후에 주제 투표를 시작할 수 있습니다"))
+	}
+
+	if s.TopicPoll != nil {
+		return study.ErrTopicPollExists
+	}
+
+	if params.ChannelID == "" || params.MessageID == "" {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("투표 메시지 정보가 없습니다"))
+	}
+
+	return nil
+}
+
+func ValidateToCloseTopicPoll(s *study.Study, _ *study.Round, _ *UpdateParams) error {
+	if s.TopicPoll == nil {
+		return study.ErrTopicPollNotFound
+	}
+	return nil
+}
+
+func ValidateToProposeTopic(s *study.Study, _ *study.Round, params *UpdateParams) error {
+	if s.TopicPoll == nil {
+		return study.ErrTopicPollNotFound
+	}
+
+	title := params.Proposal.Title
+
+	if title == "" || utf8.RuneCountInString(title) > 100 {
+		return errors.Join(study.ErrInvalidTopicProposal, fmt.Errorf("주제는 1자 이상 100자 이하로 입력해주세요"))
+	}
+
+	if len(s.TopicPoll.Proposals) >= study.MaxTopicProposals {
+		return errors.Join(study.ErrInvalidTopicProposal, fmt.Errorf("주제는 최대 %d개까지 제안할 수 있습니다", study.MaxTopicProposals))
+	}
+
+	if s.TopicPoll.HasProposed(params.MemberID) {
+		return errors.Join(study.ErrInvalidTopicProposal, fmt.Errorf("주제는 한 사람당 하나만 제안할 수 있습니다"))
+	}
+
+	for _, p := range s.TopicPoll.Proposals {
+		if p.Title == title {
+			return errors.Join(study.ErrInvalidTopicProposal, fmt.Errorf("이미 제안된 주제입니다"))
+		}
+	}
+
+	return nil
+}
+
+func ValidateToVoteTopic(s *study.Study, _ *study.Round, params *UpdateParams) error {
+	if s.TopicPoll == nil {
+		return study.ErrTopicPollNotFound
+	}
+
+	if params.MemberID == "" {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("투표할 사용자 ID가 없습니다"))
+	}
+
+	if _, ok := s.TopicPoll.GetProposal(params.Proposal.ID); !ok {
+		return errors.Join(study.ErrInvalidTopicProposal, fmt.Errorf("존재하지 않는 주제입니다"))
+	}
+
+	return nil
+}
+
+func ValidateToGrantExtension(s *study.Study, r *study.Round, params *UpdateParams) error {
+	if params.MemberID == "" {
+		return errors.Join(study.ErrInvalidUpdateParams, fmt.Errorf("제출 기한을 연장할 사용자 ID가 없습니다"))
+	}
+
+	if s.CurrentStage < study.StageRegistrationClosed || s.CurrentStage >= study.StagePresentationFinished {
+		return errors.Join(study.ErrInvalidStage, fmt.Errorf("발표자 등록 마감부터 발표 종료 전까지만 제출 기한을 연장할 수 있습니다"))
+	}
+
+	member, ok := r.GetMember(params.MemberID)
+	if !ok {
+		return study.ErrMemberNotFound
+	}
+
+	if !member.IsRegistered() {
+		return study.ErrMemberNotRegistered
+	}
+
+	if !params.Until.IsZero() && !params.Until.After(time.Now()) {
+		return errors.Join(study.ErrInvalidArgs, fmt.Errorf("연장된 기한은 현재 시간 이후여야 합니다"))
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestValidateToProposeTopic(t *testing.T) {
+	full := study.NewTopicPoll("channel", "message")
+	for n := 0; n < study.MaxTopicProposals; n++ {
+		full.Propose(fmt.Sprintf("주제 %d", n), fmt.Sprintf("p%d", n))
+	}
+
+	poll := study.NewTopicPoll("channel", "message")
+	poll.Propose("채널", "a")
+
+	tests := []struct {
+		name     string
+		poll     *study.TopicPoll
+		memberID string
+		title    string
+		err      error
+	}{
+		{"valid", &poll, "b", "제네릭", nil},
+		{"no poll", nil, "b", "제네릭", study.ErrTopicPollNotFound},
+		{"empty title", &poll, "b", "", study.ErrInvalidTopicProposal},
+		{"title too long", &poll, "b", strings.Repeat("가", 101), study.ErrInvalidTopicProposal},
+		{"title at the limit", &poll, "b", strings.Repeat("가", 100), nil},
+		{"too many proposals", &full, "b", "제네릭", study.ErrInvalidTopicProposal},
+		{"already proposed", &poll, "a", "제네릭", study.ErrInvalidTopicProposal},
+		{"duplicated title", &poll, "b", "채널", study.ErrInvalidTopicProposal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &study.Study{}
+			s.SetTopicPoll(tt.poll)
+
+			err := ValidateToProposeTopic(s, nil, &UpdateParams{
+				MemberID: tt.memberID,
+				Proposal: study.TopicProposal{Title: tt.title},
+			})
+
+			if tt.err == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
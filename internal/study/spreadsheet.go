@@ -0,0 +1,32 @@
+package study
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var spreadsheetIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9-_]+$`)
+
+// extract spreadsheet id from url like https://docs.google.com/spreadsheets/d/{id}/edit
+func SpreadsheetIDFromURL(spreadsheetURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(spreadsheetURL))
+	if err != nil {
+		return "", ErrInvalidSpreadsheetURL
+	}
+
+	if u.Scheme != "https" || u.Host != "docs.google.com" {
+		return "", ErrInvalidSpreadsheetURL
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "spreadsheets" || parts[1] != "d" {
+		return "", ErrInvalidSpreadsheetURL
+	}
+
+	if !spreadsheetIDRegexp.MatchString(parts[2]) {
+		return "", ErrInvalidSpreadsheetURL
+	}
+
+	return parts[2], nil
+}
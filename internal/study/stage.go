@@ -111,3 +111,24 @@ func (s Stage) Next() Stage {
 		return StageNone
 	}
 }
+
+func (s Stage) Prev() Stage {
+	switch s {
+	case StageRegistrationClosed:
+		return StageRegistrationOpened
+	case StageSubmissionOpened:
+		return StageRegistrationClosed
+	case StageSubmissionClosed:
+		return StageSubmissionOpened
+	case StagePresentationStarted:
+		return StageSubmissionClosed
+	case StagePresentationFinished:
+		return StagePresentationStarted
+	case StageReviewOpened:
+		return StagePresentationFinished
+	case StageReviewClosed:
+		return StageReviewOpened
+	default:
+		return StageNone
+	}
+}
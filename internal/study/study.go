@@ -5,18 +5,26 @@ import (
 )
 
 type Study struct {
-	ID                  string `bson:"_id,omitempty"`
-	GuildID             string `bson:"guild_id"`
-	NoticeChannelID     string `bson:"notice_channel_id"`
-	ReflectionChannelID string `bson:"reflection_channel_id"`
-	ManagerID           string `bson:"manager_id"`
-	OngoingRoundID      string `bson:"ongoing_round_id"`
-	SpreadsheetURL      string `bson:"spreadsheet_url"`
-	CurrentStage        Stage  `bson:"current_stage"`
-	TotalRound          int8   `bson:"total_round"`
+	ID                    string `bson:"_id,omitempty" json:"id,omitempty"`
+	GuildID               string `bson:"guild_id" json:"guild_id"`
+	NoticeChannelID       string `bson:"notice_channel_id" json:"notice_channel_id"`
+	ReflectionChannelID   string `bson:"reflection_channel_id" json:"reflection_channel_id"`
+	PresentationChannelID string `bson:"presentation_channel_id" json:"presentation_channel_id"` // voice or stage channel of the presentation
+	ManagerID             string `bson:"manager_id" json:"manager_id"`
+	OngoingRoundID        string `bson:"ongoing_round_id" json:"ongoing_round_id"`
+	SpreadsheetURL        string `bson:"spreadsheet_url" json:"spreadsheet_url"`
+	CurrentStage          Stage  `bson:"current_stage" json:"current_stage"`
+	TotalRound            int8   `bson:"total_round" json:"total_round"`
 
-	CreatedAt time.Time `bson:"created_at"`
-	UpdatedAt time.Time `bson:"updated_at"`
+	Webhooks      []Webhook `bson:"webhooks" json:"webhooks,omitempty"`
+	CalendarToken string    `bson:"calendar_token" json:"calendar_token,omitempty"` // access token of the calendar feed
+
+	TopicPoll *TopicPoll `bson:"topic_poll" json:"topic_poll,omitempty"` // vote for the title of the next round
+
+	MaterialHosts []string `bson:"material_hosts" json:"material_hosts,omitempty"` // allowed hosts of submitted materials, defaults if empty
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
 }
 
 func New() Study {
@@ -49,6 +57,10 @@ func (s *Study) SetReflectionChannelID(channelID string) {
 	s.ReflectionChannelID = channelID
 }
 
+func (s *Study) SetPresentationChannelID(channelID string) {
+	s.PresentationChannelID = channelID
+}
+
 func (s *Study) SetManagerID(userID string) {
 	s.ManagerID = userID
 }
@@ -76,3 +88,36 @@ func (s *Study) IncrementTotalRound() {
 func (s *Study) SetUpdatedAt(t time.Time) {
 	s.UpdatedAt = t
 }
+
+func (s *Study) SetMaterialHosts(hosts []string) {
+	s.MaterialHosts = hosts
+}
+
+func (s *Study) SetCalendarToken(token string) {
+	s.CalendarToken = token
+}
+
+func (s *Study) AddWebhook(w Webhook) {
+	s.Webhooks = append(s.Webhooks, w)
+}
+
+func (s *Study) RemoveWebhook(id string) {
+	webhooks := make([]Webhook, 0, len(s.Webhooks))
+
+	for _, w := range s.Webhooks {
+		if w.ID != id {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	s.Webhooks = webhooks
+}
+
+func (s Study) GetWebhook(id string) (Webhook, bool) {
+	for _, w := range s.Webhooks {
+		if w.ID == id {
+			return w, true
+		}
+	}
+	return Webhook{}, false
+}
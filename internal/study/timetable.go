@@ -0,0 +1,135 @@
+package study
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Slot is the turn of a speaker in the presentation
+type Slot struct {
+	MemberID string        `bson:"member_id" json:"member_id"`
+	Order    int           `bson:"order" json:"order"` // starts from 1
+	StartAt  time.Time     `bson:"start_at" json:"start_at"`
+	Duration time.Duration `bson:"duration" json:"duration"`
+}
+
+func (s Slot) EndAt() time.Time {
+	return s.StartAt.Add(s.Duration)
+}
+
+type TimetableOrder uint8
+
+const (
+	TimetableOrderRegistration TimetableOrder = iota
+	TimetableOrderRandom
+)
+
+func (o TimetableOrder) String() string {
+	switch o {
+	case TimetableOrderRandom:
+		return "랜덤"
+	default:
+		return "등록순"
+	}
+}
+
+// ids of registered speakers in order of registration
+func (r Round) SpeakerIDs() []string {
+	ids := []string{}
+
+	for id, m := range r.Members {
+		if m.IsRegistered() {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := r.Members[ids[i]].RegisteredAt, r.Members[ids[j]].RegisteredAt
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// timetable of the speakers starting at the given time, each speaker has the same duration
+func NewTimetable(speakerIDs []string, order TimetableOrder, start time.Time, duration time.Duration, rnd *rand.Rand) []Slot {
+	ids := make([]string, len(speakerIDs))
+	copy(ids, speakerIDs)
+
+	if order == TimetableOrderRandom {
+		rnd.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	}
+
+	slots := make([]Slot, 0, len(ids))
+
+	for _, id := range ids {
+		slots = append(slots, Slot{MemberID: id, Duration: duration})
+	}
+
+	return arrangeSlots(slots, start)
+}
+
+func (r *Round) SetTimetable(slots []Slot) {
+	r.Timetable = slots
+}
+
+// swap turns of the speakers in the given orders, start times are rearranged
+func (r *Round) SwapSlots(a, b int) bool {
+	if a < 1 || b < 1 || a > len(r.Timetable) || b > len(r.Timetable) {
+		return false
+	}
+
+	slots := make([]Slot, len(r.Timetable))
+	copy(slots, r.Timetable)
+
+	slots[a-1], slots[b-1] = slots[b-1], slots[a-1]
+
+	r.Timetable = arrangeSlots(slots, r.Timetable[0].StartAt)
+
+	return true
+}
+
+// remove the slot of the member, start times of the following slots are rearranged
+func (r *Round) RemoveSlot(memberID string) bool {
+	slots := make([]Slot, 0, len(r.Timetable))
+
+	for _, s := range r.Timetable {
+		if s.MemberID != memberID {
+			slots = append(slots, s)
+		}
+	}
+
+	if len(slots) == len(r.Timetable) {
+		return false
+	}
+
+	r.Timetable = arrangeSlots(slots, r.Timetable[0].StartAt)
+
+	return true
+}
+
+func (r Round) SlotOf(memberID string) (Slot, bool) {
+	for _, s := range r.Timetable {
+		if s.MemberID == memberID {
+			return s, true
+		}
+	}
+	return Slot{}, false
+}
+
+// set order and start time of the slots one after another
+func arrangeSlots(slots []Slot, start time.Time) []Slot {
+	at := start
+
+	for n := range slots {
+		slots[n].Order = n + 1
+		slots[n].StartAt = at
+		at = at.Add(slots[n].Duration)
+	}
+
+	return slots
+}
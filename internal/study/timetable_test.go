@@ -0,0 +1,128 @@
+package study
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+var timetableStart = time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC)
+
+func slotMemberIDs(slots []Slot) []string {
+	ids := make([]string, 0, len(slots))
+	for _, s := range slots {
+		ids = append(ids, s.MemberID)
+	}
+	return ids
+}
+
+// orders start from 1 and each slot starts when the previous one ends
+func checkArranged(t *testing.T, slots []Slot, start time.Time) {
+	t.Helper()
+
+	at := start
+
+	for n, s := range slots {
+		if s.Order != n+1 || !s.StartAt.Equal(at) {
+			t.Fatalf("slot %d is not arranged: %+v", n, s)
+		}
+		at = s.EndAt()
+	}
+}
+
+func TestNewTimetable(t *testing.T) {
+	ids := []string{"a", "b", "c", "d"}
+
+	slots := NewTimetable(ids, TimetableOrderRegistration, timetableStart, 15*time.Minute, nil)
+
+	if got := slotMemberIDs(slots); !reflect.DeepEqual(got, ids) {
+		t.Fatalf("members = %v, want %v", got, ids)
+	}
+
+	checkArranged(t, slots, timetableStart)
+
+	if end := slots[len(slots)-1].EndAt(); !end.Equal(timetableStart.Add(time.Hour)) {
+		t.Fatalf("end = %v, want %v", end, timetableStart.Add(time.Hour))
+	}
+
+	// random order is a permutation of the speakers and the input is not changed
+	random := NewTimetable(ids, TimetableOrderRandom, timetableStart, 10*time.Minute, rand.New(rand.NewSource(1)))
+
+	got := slotMemberIDs(random)
+	sort.Strings(got)
+
+	if !reflect.DeepEqual(got, ids) {
+		t.Fatalf("members = %v, want permutation of %v", got, ids)
+	}
+
+	if !reflect.DeepEqual(ids, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("input is changed: %v", ids)
+	}
+
+	checkArranged(t, random, timetableStart)
+
+	if slots := NewTimetable(nil, TimetableOrderRegistration, timetableStart, time.Minute, nil); len(slots) != 0 {
+		t.Fatalf("expected empty timetable, got %+v", slots)
+	}
+}
+
+func TestSwapSlots(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b int
+		ok   bool
+		want []string
+	}{
+		{"swap first and last", 1, 3, true, []string{"c", "b", "a"}},
+		{"swap same slot", 2, 2, true, []string{"a", "b", "c"}},
+		{"zero order", 0, 1, false, []string{"a", "b", "c"}},
+		{"out of range", 1, 4, false, []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRound()
+
+			// slots of different durations are rearranged by their new orders
+			slots := NewTimetable([]string{"a", "b", "c"}, TimetableOrderRegistration, timetableStart, 10*time.Minute, nil)
+			slots[0].Duration = 30 * time.Minute
+			r.SetTimetable(arrangeSlots(slots, timetableStart))
+
+			if ok := r.SwapSlots(tt.a, tt.b); ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+
+			if got := slotMemberIDs(r.Timetable); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("members = %v, want %v", got, tt.want)
+			}
+
+			checkArranged(t, r.Timetable, timetableStart)
+		})
+	}
+}
+
+func TestRemoveSlot(t *testing.T) {
+	r := NewRound()
+	r.SetTimetable(NewTimetable([]string{"a", "b", "c"}, TimetableOrderRegistration, timetableStart, 15*time.Minute, nil))
+
+	if r.RemoveSlot("x") {
+		t.Fatal("expected missing member not to be removed")
+	}
+
+	if !r.RemoveSlot("a") {
+		t.Fatal("expected member to be removed")
+	}
+
+	if got := slotMemberIDs(r.Timetable); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Fatalf("members = %v, want [b c]", got)
+	}
+
+	// following slots move forward from the start of the presentation
+	checkArranged(t, r.Timetable, timetableStart)
+
+	if _, ok := r.SlotOf("a"); ok {
+		t.Fatal("expected slot of removed member not to be found")
+	}
+}
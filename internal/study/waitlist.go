@@ -0,0 +1,76 @@
+package study
+
+import "time"
+
+func (r *Round) SetMaxSpeakers(n int) {
+	r.MaxSpeakers = n
+}
+
+// number of registered speakers
+func (r Round) SpeakerCount() int {
+	cnt := 0
+
+	for _, m := range r.Members {
+		if m.IsRegistered() {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+// whether no more speakers can be registered, zero max speakers means unlimited
+func (r Round) IsFull() bool {
+	return r.MaxSpeakers > 0 && r.SpeakerCount() >= r.MaxSpeakers
+}
+
+func (r *Round) AddToWaitlist(memberID string) {
+	if r.WaitlistPosition(memberID) > 0 {
+		return
+	}
+
+	r.Waitlist = append(r.Waitlist, memberID)
+}
+
+func (r *Round) RemoveFromWaitlist(memberID string) bool {
+	for n, id := range r.Waitlist {
+		if id == memberID {
+			r.Waitlist = append(r.Waitlist[:n:n], r.Waitlist[n+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// position in the waitlist starting from 1, zero if not waiting
+func (r Round) WaitlistPosition(memberID string) int {
+	for n, id := range r.Waitlist {
+		if id == memberID {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+// register members in the waitlist while there are vacancies, promoted member ids are returned
+func (r *Round) PromoteWaitlist(at time.Time) []string {
+	var promoted []string
+
+	for len(r.Waitlist) > 0 && !r.IsFull() {
+		id := r.Waitlist[0]
+		r.Waitlist = r.Waitlist[1:]
+
+		member, ok := r.GetMember(id)
+		if !ok {
+			continue
+		}
+
+		member.SetRegistered(true)
+		member.SetRegisteredAt(at)
+		r.SetMember(id, member)
+
+		promoted = append(promoted, id)
+	}
+
+	return promoted
+}
@@ -0,0 +1,107 @@
+package study
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// round with registered speakers and members waiting in order
+func waitlistRound(maxSpeakers int, registered []string, waiting []string) Round {
+	r := NewRound()
+	r.SetMaxSpeakers(maxSpeakers)
+
+	for _, id := range registered {
+		m := NewMember()
+		m.SetRegistered(true)
+		r.SetMember(id, m)
+	}
+
+	for _, id := range waiting {
+		r.SetMember(id, NewMember())
+		r.AddToWaitlist(id)
+	}
+
+	return r
+}
+
+func TestIsFull(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxSpeakers int
+		registered  []string
+		full        bool
+	}{
+		{"unlimited", 0, []string{"a", "b", "c"}, false},
+		{"vacancy", 3, []string{"a", "b"}, false},
+		{"full", 2, []string{"a", "b"}, true},
+		{"over capacity after lowering the limit", 1, []string{"a", "b"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := waitlistRound(tt.maxSpeakers, tt.registered, nil)
+
+			// unregistered members are not counted
+			r.SetMember("x", NewMember())
+
+			if r.IsFull() != tt.full {
+				t.Fatalf("full = %v, want %v", r.IsFull(), tt.full)
+			}
+		})
+	}
+}
+
+func TestWaitlistOrder(t *testing.T) {
+	r := waitlistRound(1, []string{"a"}, []string{"b", "c", "d"})
+
+	// waiting again does not change the position
+	r.AddToWaitlist("b")
+
+	if !reflect.DeepEqual(r.Waitlist, []string{"b", "c", "d"}) {
+		t.Fatalf("waitlist = %v", r.Waitlist)
+	}
+
+	if !r.RemoveFromWaitlist("c") || r.RemoveFromWaitlist("c") {
+		t.Fatal("expected member to be removed only once")
+	}
+
+	for id, want := range map[string]int{"b": 1, "d": 2, "c": 0, "a": 0} {
+		if got := r.WaitlistPosition(id); got != want {
+			t.Errorf("position of %s = %d, want %d", id, got, want)
+		}
+	}
+}
+
+func TestPromoteWaitlist(t *testing.T) {
+	at := time.Date(2023, 6, 3, 20, 0, 0, 0, time.UTC)
+
+	r := waitlistRound(3, []string{"a"}, []string{"b", "c", "d"})
+
+	// member left the study while waiting
+	delete(r.Members, "b")
+
+	promoted := r.PromoteWaitlist(at)
+
+	if !reflect.DeepEqual(promoted, []string{"c", "d"}) {
+		t.Fatalf("promoted = %v, want [c d]", promoted)
+	}
+
+	if len(r.Waitlist) != 0 || !r.IsFull() {
+		t.Fatalf("expected round to be full with empty waitlist, got %v", r.Waitlist)
+	}
+
+	for _, id := range promoted {
+		m, _ := r.GetMember(id)
+		if !m.IsRegistered() || !m.RegisteredAt.Equal(at) {
+			t.Fatalf("member %s is not registered at %v: %+v", id, at, m)
+		}
+	}
+
+	// nobody is promoted without vacancy
+	r = waitlistRound(1, []string{"a"}, []string{"b"})
+
+	if promoted := r.PromoteWaitlist(at); len(promoted) != 0 || r.WaitlistPosition("b") != 1 {
+		t.Fatalf("expected nobody to be promoted, got %v", promoted)
+	}
+}
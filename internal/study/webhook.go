@@ -0,0 +1,78 @@
+package study
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// max number of webhooks registered to a study
+const MaxWebhooks = 5
+
+// Webhook receives events of the study as signed json
+type Webhook struct {
+	ID        string       `bson:"id" json:"id"`
+	URL       string       `bson:"url" json:"url"`
+	Secret    string       `bson:"secret" json:"secret"` // key of the hmac-sha256 signature
+	Topics    []EventTopic `bson:"topics" json:"topics,omitempty"`
+	CreatedAt time.Time    `bson:"created_at" json:"created_at"`
+}
+
+// create webhook with random id and secret, all topics are subscribed if none is given
+func NewWebhook(rawURL string, topics ...EventTopic) (Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return Webhook{}, errors.Join(ErrInvalidWebhookURL, fmt.Errorf("url: %s", rawURL))
+	}
+
+	for _, t := range topics {
+		if err := t.Validate(); err != nil {
+			return Webhook{}, err
+		}
+	}
+
+	id, err := randomHex(4)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	return Webhook{
+		ID:        id,
+		URL:       u.String(),
+		Secret:    secret,
+		Topics:    topics,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (w Webhook) Subscribes(topic EventTopic) bool {
+	if len(w.Topics) == 0 {
+		return true
+	}
+
+	for _, t := range w.Topics {
+		if t == topic {
+			return true
+		}
+	}
+
+	return false
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -22,3 +22,15 @@ func ConnectRedisCache(ctx context.Context, addr string, ttl time.Duration) (*ca
 		LocalCache: cache.NewTinyLFU(1000, ttl),
 	}), nil
 }
+
+func ConnectRedis(ctx context.Context, addr string) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
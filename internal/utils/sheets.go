@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"context"
+	"os"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func ConnectSheets(ctx context.Context, credentialsPath string) (*sheets.Service, error) {
+	b, err := os.ReadFile(credentialsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := google.JWTConfigFromJSON(b, "https://www.googleapis.com/auth/spreadsheets")
+	if err != nil {
+		return nil, err
+	}
+
+	return sheets.NewService(ctx, option.WithHTTPClient(config.Client(ctx)))
+}
@@ -0,0 +1,14 @@
+package utils
+
+import "unicode/utf8"
+
+// cut the string to n characters with an ellipsis at the end
+func Truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	r := []rune(s)
+
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+const (
+	// max length of the value of an embed field
+	maxEmbedFieldValueLength = 1024
+	// embeds are limited to 6000 characters in total, the rest is left for other contents
+	maxTimetableLength        = 4000
+	maxTimetableSubjectLength = 40
+)
+
+// timetable of the round split into embed fields within the length limit of discord
+func TimetableFields(name string, r study.Round) []*discordgo.MessageEmbedField {
+	if len(r.Timetable) == 0 {
+		return []*discordgo.MessageEmbedField{{Name: name, Value: "발표 순서가 정해지지 않았습니다."}}
+	}
+
+	lines := make([]string, 0, len(r.Timetable))
+
+	for _, slot := range r.Timetable {
+		subject := Truncate(r.Members[slot.MemberID].Subject, maxTimetableSubjectLength)
+
+		lines = append(lines, fmt.Sprintf("%d. <t:%d:t> <@%s> %s (%d분)",
+			slot.Order, slot.StartAt.Unix(), slot.MemberID, subject, int(slot.Duration.Minutes())))
+	}
+
+	var total int
+
+	for n, line := range lines {
+		total += utf8.RuneCountInString(line) + 1
+
+		// speakers beyond the limit are summarized
+		if total > maxTimetableLength {
+			lines = append(lines[:n], fmt.Sprintf("… 외 %d명", len(lines)-n))
+			break
+		}
+	}
+
+	fields := []*discordgo.MessageEmbedField{}
+	b := &strings.Builder{}
+
+	flush := func() {
+		fieldName := name
+		if len(fields) > 0 {
+			fieldName = name + " (계속)"
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField{Name: fieldName, Value: strings.TrimSuffix(b.String(), "\n")})
+		b.Reset()
+	}
+
+	for _, line := range lines {
+		if b.Len() > 0 && utf8.RuneCountInString(b.String())+utf8.RuneCountInString(line) > maxEmbedFieldValueLength {
+			flush()
+		}
+
+		b.WriteString(line + "\n")
+	}
+
+	flush()
+
+	return fields
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+func TestTimetableFields(t *testing.T) {
+	r := study.NewRound()
+
+	if fields := TimetableFields("발표 순서", r); len(fields) != 1 || fields[0].Value != "발표 순서가 정해지지 않았습니다." {
+		t.Fatalf("unexpected fields of empty timetable: %+v", fields)
+	}
+
+	ids := []string{}
+
+	for n := 0; n < 80; n++ {
+		id := fmt.Sprintf("1000000000000000%02d", n)
+		ids = append(ids, id)
+
+		m := study.NewMember()
+		m.SetSubject(strings.Repeat("발표", 50))
+		m.SetRegistered(true)
+		r.SetMember(id, m)
+	}
+
+	r.SetTimetable(study.NewTimetable(ids, study.TimetableOrderRegistration, time.Now(), 5*time.Minute, nil))
+
+	fields := TimetableFields("발표 순서", r)
+
+	if len(fields) < 2 || fields[0].Name != "발표 순서" || fields[1].Name != "발표 순서 (계속)" {
+		t.Fatalf("expected timetable split into fields, got %d fields", len(fields))
+	}
+
+	var total int
+
+	for _, f := range fields {
+		n := utf8.RuneCountInString(f.Value)
+		if n > maxEmbedFieldValueLength {
+			t.Fatalf("field has %d characters, want at most %d", n, maxEmbedFieldValueLength)
+		}
+		total += n
+	}
+
+	// summary of the rest is added after the limit
+	if total > maxTimetableLength+20 {
+		t.Fatalf("timetable has %d characters", total)
+	}
+
+	// speakers beyond the limit are summarized
+	if last := fields[len(fields)-1].Value; !strings.Contains(last, "외") || !strings.HasSuffix(last, "명") {
+		t.Fatalf("expected summary of the rest, got %q", last)
+	}
+}
@@ -0,0 +1,202 @@
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/piatoss3612/my-study-bot/internal/api"
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+// prefix of the dashboard routes
+const Prefix = "/dashboard/"
+
+const (
+	sessionCookie = "study_session"
+	stateCookie   = "study_oauth_state"
+
+	defaultDiscordURL = "https://discord.com/api"
+	defaultSessionTTL = 24 * time.Hour
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // must point to /dashboard/callback
+}
+
+type dashboard struct {
+	svc        service.Service
+	oauth      *oauth2.Config
+	discordURL string
+	sessions   *sessionStore
+	secure     bool // cookies are sent only over https
+
+	mux *http.ServeMux
+
+	sugar *zap.SugaredLogger
+}
+
+type DashboardOptsFunc func(*dashboard)
+
+// base url of the discord api, used for tests
+func WithDiscordURL(url string) DashboardOptsFunc {
+	return func(d *dashboard) {
+		d.discordURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+func WithSessionTTL(ttl time.Duration) DashboardOptsFunc {
+	return func(d *dashboard) {
+		d.sessions.ttl = ttl
+	}
+}
+
+// create read-only web dashboard, users log in with discord and see guilds they are members of
+func NewDashboard(svc service.Service, cfg Config, sugar *zap.SugaredLogger, opts ...DashboardOptsFunc) http.Handler {
+	d := &dashboard{
+		svc:        svc,
+		discordURL: defaultDiscordURL,
+		sessions:   newSessionStore(defaultSessionTTL),
+		secure:     strings.HasPrefix(cfg.RedirectURL, "https://"),
+		mux:        http.NewServeMux(),
+		sugar:      sugar,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	d.oauth = &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       []string{"identify", "guilds"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   d.discordURL + "/oauth2/authorize",
+			TokenURL:  d.discordURL + "/oauth2/token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+
+	return d.setup()
+}
+
+func (d *dashboard) setup() http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+
+	d.mux.HandleFunc(Prefix+"login", d.login)
+	d.mux.HandleFunc(Prefix+"callback", d.callback)
+	d.mux.HandleFunc(Prefix+"logout", d.logout)
+	d.mux.HandleFunc(Prefix+"me", d.me)
+	d.mux.HandleFunc(Prefix+"stages", d.stages)
+
+	// api over the study service, access is granted to members of the guild and the manager of the study
+	d.mux.Handle(Prefix+"api/", http.StripPrefix(strings.TrimSuffix(Prefix, "/"), api.NewHandler(d.svc, d.authorize)))
+
+	d.mux.Handle(Prefix, http.StripPrefix(Prefix, http.FileServer(http.FS(static))))
+
+	return d
+}
+
+func (d *dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+func (d *dashboard) session(r *http.Request) (Session, bool) {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return Session{}, false
+	}
+
+	return d.sessions.Get(c.Value)
+}
+
+// members of the guild read public data, the manager of the study reads everything
+func (d *dashboard) authorize(r *http.Request, guildID string) api.Access {
+	s, ok := d.session(r)
+	if !ok || !s.IsMemberOf(guildID) {
+		return api.AccessNone
+	}
+
+	if gs, err := d.svc.GetStudy(r.Context(), guildID); err == nil && gs.IsManager(s.UserID) {
+		return api.AccessManager
+	}
+
+	return api.AccessMember
+}
+
+// user of the session with guilds which have a study
+func (d *dashboard) me(w http.ResponseWriter, r *http.Request) {
+	s, ok := d.session(r)
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": api.ErrUnauthorized.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s)
+}
+
+type stage struct {
+	Value study.Stage `json:"value"`
+	Name  string      `json:"name"`
+}
+
+// stages of a round in order, used to draw the timeline
+func (d *dashboard) stages(w http.ResponseWriter, _ *http.Request) {
+	stages := []stage{}
+
+	for s := study.StageWait; !s.IsNone(); s = s.Next() {
+		stages = append(stages, stage{Value: s, Name: s.String()})
+
+		if s.IsFinished() {
+			break
+		}
+	}
+
+	writeJSON(w, http.StatusOK, stages)
+}
+
+func (d *dashboard) logout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		d.sessions.Delete(c.Value)
+	}
+
+	d.setCookie(w, sessionCookie, "", -1)
+
+	http.Redirect(w, r, Prefix, http.StatusFound)
+}
+
+func (d *dashboard) setCookie(w http.ResponseWriter, name, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     Prefix,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   d.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// details of the error are only logged
+func (d *dashboard) fail(w http.ResponseWriter, code int, err error) {
+	d.sugar.Errorw("dashboard error", "error", err)
+	http.Error(w, http.StatusText(code), code)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,252 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+	"github.com/piatoss3612/my-study-bot/internal/study/service"
+	"go.uber.org/zap"
+)
+
+type fakeService struct {
+	service.Service
+
+	studies map[string]*study.Study
+}
+
+func (f *fakeService) GetStudy(_ context.Context, guildID string) (*study.Study, error) {
+	s, ok := f.studies[guildID]
+	if !ok {
+		return nil, study.ErrStudyNotFound
+	}
+	return s, nil
+}
+
+func (f *fakeService) GetRounds(_ context.Context, _ string) ([]*study.Round, error) {
+	return nil, study.ErrRoundNotFound
+}
+
+// fake discord api issuing a token for the code "code"
+func newFakeDiscord(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+	})
+
+	auth := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		}
+	}
+
+	mux.HandleFunc("/users/@me", auth(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"u1","username":"alice","global_name":"Alice"}`))
+	}))
+
+	mux.HandleFunc("/users/@me/guilds", auth(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"g1","name":"study"},{"id":"g2","name":"other"}]`))
+	}))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newTestDashboard(t *testing.T) http.Handler {
+	t.Helper()
+
+	return newTestDashboardWith(t, map[string]*study.Study{
+		"g1": {GuildID: "g1", CurrentStage: study.StageSubmissionOpened},
+		"g3": {GuildID: "g3"},
+	})
+}
+
+func newTestDashboardWith(t *testing.T, studies map[string]*study.Study) http.Handler {
+	t.Helper()
+
+	discord := newFakeDiscord(t)
+
+	svc := &fakeService{
+		studies: studies,
+	}
+
+	return NewDashboard(svc, Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/dashboard/callback",
+	}, zap.NewNop().Sugar(), WithDiscordURL(discord.URL))
+}
+
+func serve(h http.Handler, method, target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func cookie(rec *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func login(t *testing.T, h http.Handler) *http.Cookie {
+	t.Helper()
+
+	rec := serve(h, http.MethodGet, "/dashboard/login")
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected redirect, got %d", rec.Code)
+	}
+
+	loc, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := cookie(rec, stateCookie)
+	if state == nil || loc.Query().Get("state") != state.Value {
+		t.Fatalf("state of the redirect does not match the cookie")
+	}
+
+	if !strings.Contains(loc.Query().Get("scope"), "guilds") {
+		t.Fatalf("expected guilds scope, got %q", loc.Query().Get("scope"))
+	}
+
+	rec = serve(h, http.MethodGet, "/dashboard/callback?code=code&state="+state.Value, state)
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected redirect after callback, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	session := cookie(rec, sessionCookie)
+	if session == nil || session.Value == "" {
+		t.Fatal("expected session cookie")
+	}
+
+	return session
+}
+
+func TestDashboardLogin(t *testing.T) {
+	h := newTestDashboard(t)
+
+	session := login(t, h)
+
+	rec := serve(h, http.MethodGet, "/dashboard/me", session)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var s Session
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	// only guilds which have a study are kept
+	if s.UserID != "u1" || s.Username != "Alice" || len(s.Guilds) != 1 || s.Guilds[0].ID != "g1" {
+		t.Fatalf("unexpected session: %+v", s)
+	}
+}
+
+func TestDashboardCallbackRejectsInvalidState(t *testing.T) {
+	h := newTestDashboard(t)
+
+	rec := serve(h, http.MethodGet, "/dashboard/callback?code=code&state=forged",
+		&http.Cookie{Name: stateCookie, Value: "state"})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestDashboardAPIAccess(t *testing.T) {
+	h := newTestDashboard(t)
+
+	if rec := serve(h, http.MethodGet, "/dashboard/api/v1/guilds/g1/study"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 without session, got %d", rec.Code)
+	}
+
+	session := login(t, h)
+
+	if rec := serve(h, http.MethodGet, "/dashboard/api/v1/guilds/g1/study", session); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	// user is not a member of the guild
+	if rec := serve(h, http.MethodGet, "/dashboard/api/v1/guilds/g3/study", session); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", rec.Code)
+	}
+
+	if rec := serve(h, http.MethodGet, "/dashboard/logout", session); rec.Code != http.StatusFound {
+		t.Fatalf("expected redirect, got %d", rec.Code)
+	}
+
+	if rec := serve(h, http.MethodGet, "/dashboard/me", session); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 after logout, got %d", rec.Code)
+	}
+}
+
+func TestDashboardAPIAccessOfManager(t *testing.T) {
+	poll := study.NewTopicPoll("c1", "p1")
+	proposal := poll.Propose("채널", "u2")
+	poll.Vote("u3", proposal.ID)
+
+	// u1 of the fake discord is the manager of g2 but not of g1
+	h := newTestDashboardWith(t, map[string]*study.Study{
+		"g1": {GuildID: "g1", ManagerID: "u2", TopicPoll: &poll},
+		"g2": {GuildID: "g2", ManagerID: "u1", TopicPoll: &poll},
+	})
+
+	session := login(t, h)
+
+	for guildID, votes := range map[string]int{"g1": 0, "g2": 1} {
+		rec := serve(h, http.MethodGet, "/dashboard/api/v1/guilds/"+guildID+"/study", session)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", guildID, rec.Code)
+		}
+
+		var s study.Study
+		if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+			t.Fatal(err)
+		}
+
+		if s.TopicPoll == nil || len(s.TopicPoll.Votes) != votes {
+			t.Fatalf("%s: expected %d votes, got %+v", guildID, votes, s.TopicPoll)
+		}
+	}
+}
+
+func TestDashboardServesEmbeddedPage(t *testing.T) {
+	h := newTestDashboard(t)
+
+	for _, path := range []string{"/dashboard/", "/dashboard/app.js", "/dashboard/style.css"} {
+		if rec := serve(h, http.MethodGet, path); rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d", path, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/piatoss3612/my-study-bot/internal/study"
+)
+
+var ErrInvalidState = errors.New("invalid oauth2 state")
+
+type discordUser struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+}
+
+type discordGuild struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Icon string `json:"icon"`
+}
+
+// redirect to the authorization page of discord
+func (d *dashboard) login(w http.ResponseWriter, r *http.Request) {
+	state, err := randomString(16)
+	if err != nil {
+		d.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	d.setCookie(w, stateCookie, state, 600)
+
+	http.Redirect(w, r, d.oauth.AuthCodeURL(state), http.StatusFound)
+}
+
+// exchange code for a token and create session with guilds of the user which have a study
+func (d *dashboard) callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// user denied authorization
+	if q.Get("error") != "" {
+		http.Redirect(w, r, Prefix, http.StatusFound)
+		return
+	}
+
+	c, err := r.Cookie(stateCookie)
+	if err != nil || c.Value == "" || c.Value != q.Get("state") {
+		d.fail(w, http.StatusBadRequest, ErrInvalidState)
+		return
+	}
+
+	d.setCookie(w, stateCookie, "", -1)
+
+	ctx := r.Context()
+
+	token, err := d.oauth.Exchange(ctx, q.Get("code"))
+	if err != nil {
+		d.fail(w, http.StatusBadGateway, err)
+		return
+	}
+
+	client := d.oauth.Client(ctx, token)
+
+	var user discordUser
+
+	if err := d.getDiscord(ctx, client, "/users/@me", &user); err != nil {
+		d.fail(w, http.StatusBadGateway, err)
+		return
+	}
+
+	var guilds []discordGuild
+
+	if err := d.getDiscord(ctx, client, "/users/@me/guilds", &guilds); err != nil {
+		d.fail(w, http.StatusBadGateway, err)
+		return
+	}
+
+	s := Session{
+		UserID:   user.ID,
+		Username: user.GlobalName,
+		Guilds:   []Guild{},
+	}
+
+	if s.Username == "" {
+		s.Username = user.Username
+	}
+
+	for _, g := range guilds {
+		_, err := d.svc.GetStudy(ctx, g.ID)
+		if err != nil {
+			if errors.Is(err, study.ErrStudyNotFound) {
+				continue
+			}
+
+			d.fail(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Guilds = append(s.Guilds, Guild{ID: g.ID, Name: g.Name, Icon: g.Icon})
+	}
+
+	id, err := d.sessions.Create(s)
+	if err != nil {
+		d.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	d.setCookie(w, sessionCookie, id, int(d.sessions.ttl.Seconds()))
+
+	http.Redirect(w, r, Prefix, http.StatusFound)
+}
+
+func (d *dashboard) getDiscord(ctx context.Context, client *http.Client, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.discordURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("discord api %s: unexpected status code %d", path, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
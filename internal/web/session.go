@@ -0,0 +1,111 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// guild of the user which has a study
+type Guild struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Icon string `json:"icon,omitempty"`
+}
+
+type Session struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Guilds    []Guild   `json:"guilds"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s Session) IsMemberOf(guildID string) bool {
+	for _, g := range s.Guilds {
+		if g.ID == guildID {
+			return true
+		}
+	}
+	return false
+}
+
+// sessions are kept in memory, users log in again after the bot restarts
+type sessionStore struct {
+	mtx      *sync.Mutex
+	sessions map[string]Session
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+func newSessionStore(ttl time.Duration) *sessionStore {
+	return &sessionStore{
+		mtx:      &sync.Mutex{},
+		sessions: map[string]Session{},
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// store session and return its id
+func (st *sessionStore) Create(s Session) (string, error) {
+	id, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+
+	defer st.mtx.Unlock()
+	st.mtx.Lock()
+
+	st.purge()
+
+	s.ExpiresAt = st.now().Add(st.ttl)
+	st.sessions[id] = s
+
+	return id, nil
+}
+
+func (st *sessionStore) Get(id string) (Session, bool) {
+	defer st.mtx.Unlock()
+	st.mtx.Lock()
+
+	s, ok := st.sessions[id]
+	if !ok {
+		return Session{}, false
+	}
+
+	if !st.now().Before(s.ExpiresAt) {
+		delete(st.sessions, id)
+		return Session{}, false
+	}
+
+	return s, true
+}
+
+func (st *sessionStore) Delete(id string) {
+	defer st.mtx.Unlock()
+	st.mtx.Lock()
+
+	delete(st.sessions, id)
+}
+
+// remove expired sessions, lock must be held
+func (st *sessionStore) purge() {
+	now := st.now()
+
+	for id, s := range st.sessions {
+		if !now.Before(s.ExpiresAt) {
+			delete(st.sessions, id)
+		}
+	}
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
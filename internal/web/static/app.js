@@ -0,0 +1,185 @@
+"use strict";
+
+const perPage = 5;
+
+const state = {
+  guildID: "",
+  page: 1,
+  totalPages: 1,
+  stages: [],
+};
+
+async function getJSON(path) {
+  const resp = await fetch(path, { credentials: "same-origin" });
+  if (resp.status === 401) {
+    const err = new Error("unauthorized");
+    err.status = 401;
+    throw err;
+  }
+  if (!resp.ok) {
+    throw new Error(`요청에 실패했습니다 (${resp.status})`);
+  }
+  return resp.json();
+}
+
+function el(tag, attrs = {}, ...children) {
+  const e = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs)) {
+    if (k === "class") {
+      e.className = v;
+    } else {
+      e.setAttribute(k, v);
+    }
+  }
+  for (const c of children) {
+    e.append(c instanceof Node ? c : document.createTextNode(String(c)));
+  }
+  return e;
+}
+
+// links are shown only for http(s) urls
+function link(url, text) {
+  if (!/^https?:\/\//.test(url || "")) {
+    return document.createTextNode("-");
+  }
+  return el("a", { href: url, target: "_blank", rel: "noopener noreferrer" }, text);
+}
+
+function showError(err) {
+  const p = document.getElementById("error");
+  p.textContent = err.message;
+  p.hidden = false;
+}
+
+function renderTimeline(current) {
+  const ol = document.getElementById("timeline");
+  ol.replaceChildren();
+
+  for (const s of state.stages) {
+    let cls = "";
+    if (s.value === current) {
+      cls = "current";
+    } else if (s.value < current) {
+      cls = "done";
+    }
+    ol.append(el("li", { class: cls }, s.name));
+  }
+}
+
+function stageName(value) {
+  const s = state.stages.find((s) => s.value === value);
+  return s ? s.name : "초기화";
+}
+
+function renderRound(r) {
+  const members = Object.entries(r.members || {})
+    .filter(([, m]) => m.registered)
+    .sort(([a], [b]) => a.localeCompare(b));
+
+  const tbody = el("tbody");
+  for (const [, m] of members) {
+    tbody.append(
+      el(
+        "tr",
+        {},
+        el("td", {}, m.name),
+        el("td", {}, m.subject || "-"),
+        el("td", {}, link(m.content_url, "자료")),
+        el("td", {}, m.attended ? "O" : "X"),
+      ),
+    );
+  }
+
+  const table = el(
+    "table",
+    {},
+    el("thead", {}, el("tr", {}, el("th", {}, "발표자"), el("th", {}, "주제"), el("th", {}, "발표 자료"), el("th", {}, "출석"))),
+    tbody,
+  );
+
+  return el(
+    "div",
+    { class: "round" },
+    el("h3", {}, `${r.number}회차: ${r.title}`),
+    el("p", {}, `단계: ${stageName(r.stage)} · 녹화: `, link(r.content_url, "보기")),
+    members.length > 0 ? table : el("p", {}, "등록된 발표자가 없습니다."),
+  );
+}
+
+async function loadGuild() {
+  const base = `api/v1/guilds/${encodeURIComponent(state.guildID)}`;
+
+  const study = await getJSON(`${base}/study`);
+  renderTimeline(study.current_stage);
+
+  // latest rounds first
+  const page = await getJSON(`${base}/rounds?page=${state.page}&per_page=${perPage}&order=desc`);
+  state.totalPages = Math.max(page.total_pages, 1);
+
+  const rounds = document.getElementById("rounds");
+  rounds.replaceChildren();
+
+  for (const r of page.items) {
+    rounds.append(renderRound(r));
+  }
+
+  if (page.items.length === 0) {
+    rounds.append(el("p", {}, "진행된 라운드가 없습니다."));
+  }
+
+  document.getElementById("page").textContent = `${state.page} / ${state.totalPages}`;
+  document.getElementById("prev").disabled = state.page >= state.totalPages;
+  document.getElementById("next").disabled = state.page <= 1;
+}
+
+async function main() {
+  let me;
+
+  try {
+    me = await getJSON("me");
+  } catch (err) {
+    if (err.status === 401) {
+      document.getElementById("login").hidden = false;
+      return;
+    }
+    throw err;
+  }
+
+  state.stages = await getJSON("stages");
+
+  document.getElementById("user").replaceChildren(`${me.username} · `, el("a", { href: "logout" }, "로그아웃"));
+
+  if (me.guilds.length === 0) {
+    throw new Error("스터디가 진행 중인 서버가 없습니다.");
+  }
+
+  const select = document.getElementById("guilds");
+  for (const g of me.guilds) {
+    select.append(el("option", { value: g.id }, g.name));
+  }
+
+  select.addEventListener("change", () => {
+    state.guildID = select.value;
+    state.page = 1;
+    loadGuild().catch(showError);
+  });
+
+  // older rounds are on later pages
+  document.getElementById("prev").addEventListener("click", () => {
+    state.page = Math.min(state.page + 1, state.totalPages);
+    loadGuild().catch(showError);
+  });
+
+  document.getElementById("next").addEventListener("click", () => {
+    state.page = Math.max(state.page - 1, 1);
+    loadGuild().catch(showError);
+  });
+
+  state.guildID = me.guilds[0].id;
+
+  document.getElementById("dashboard").hidden = false;
+
+  await loadGuild();
+}
+
+main().catch(showError);